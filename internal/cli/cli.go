@@ -0,0 +1,590 @@
+// Package cli implements devdash's headless subcommands.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/devenv"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/scanner"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK         = 0 // Command succeeded (status: project running)
+	ExitError      = 1 // Command failed
+	ExitUsage      = 2 // Invalid arguments
+	ExitNotFound   = 3 // Project or service not found
+	ExitNotRunning = 4 // Project is not running
+	ExitDegraded   = 5 // Project is running with stopped services
+	ExitMissing    = 6 // Project is registered but its directory is gone
+)
+
+// How long restart waits for a stopped project's process-compose to exit
+// before starting it again, and how often it checks.
+var (
+	stopTimeout      = 30 * time.Second
+	stopPollInterval = 250 * time.Millisecond
+)
+
+// command is a single subcommand handler.
+type command struct {
+	usage string
+	run   func(e *env, args []string) int
+	flags []string // Flags the command accepts, by name
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"list":    {"list [--all] [--json]", runList, []string{"all", "json"}},
+		"status":  {"status <project> [--json]", runStatus, []string{"json"}},
+		"start":   {"start <project> [service] [--json]", runAction("start"), []string{"json"}},
+		"stop":    {"stop <project> [service] [--json]", runAction("stop"), []string{"json"}},
+		"restart": {"restart <project> [service] [--json]", runAction("restart"), []string{"json"}},
+		"scan":    {"scan [--json]", runScan, []string{"json"}},
+		"group":   {"group <list|create|after|delete|start|stop|restart> ...", runGroup, []string{"json", "wait"}},
+		"config":  {"config check [--json]", runConfig, []string{"json"}},
+		"help":    {"help", runHelp, nil},
+	}
+}
+
+// env carries per-invocation state shared by subcommands.
+type env struct {
+	stdout io.Writer
+	stderr io.Writer
+	json   bool
+	all    bool
+	wait   bool
+}
+
+// IsCommand reports whether name is a known subcommand.
+func IsCommand(name string) bool {
+	_, ok := commands[name]
+	return ok
+}
+
+// Run executes the subcommand named by args[0] and returns the process exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	e := &env{stdout: stdout, stderr: stderr}
+	if len(args) == 0 {
+		runHelp(e, nil)
+		return ExitUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "devdash: unknown command %q\n\n", args[0])
+		runHelp(e, nil)
+		return ExitUsage
+	}
+
+	positional, err := e.parseFlags(args[0], cmd.flags, args[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "usage: devdash %s\n", cmd.usage)
+		return ExitUsage
+	}
+	return cmd.run(e, positional)
+}
+
+// parseFlags parses the flags a command accepts, which may appear anywhere
+// in args, and returns the remaining positional arguments. Any other flag
+// is an error.
+func (e *env) parseFlags(name string, accepted []string, args []string) ([]string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	for _, f := range accepted {
+		switch f {
+		case "json":
+			fs.BoolVar(&e.json, "json", false, "print machine-readable JSON output")
+		case "all":
+			fs.BoolVar(&e.all, "all", false, "include hidden projects")
+		case "wait":
+			fs.BoolVar(&e.wait, "wait", false, "wait for each project of a group to be running")
+		}
+	}
+
+	// Move flags before positional arguments so "status api --json" works
+	var flags, positional []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	if err := fs.Parse(flags); err != nil {
+		return nil, err
+	}
+	return positional, nil
+}
+
+// errorf prints an error message and returns code.
+func (e *env) errorf(code int, format string, args ...interface{}) int {
+	fmt.Fprintf(e.stderr, "devdash: "+format+"\n", args...)
+	return code
+}
+
+// writeJSON prints v as indented JSON.
+func (e *env) writeJSON(v interface{}) int {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return e.errorf(ExitError, "failed to encode JSON: %v", err)
+	}
+	return ExitOK
+}
+
+// projectJSON is the JSON representation of a project.
+type projectJSON struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Kind     string        `json:"kind,omitempty"`
+	State    string        `json:"state"`
+	Hidden   bool          `json:"hidden"`
+	Services []serviceJSON `json:"services,omitempty"`
+}
+
+// serviceJSON is the JSON representation of a service.
+type serviceJSON struct {
+	Name     string  `json:"name"`
+	Status   string  `json:"status"`
+	Running  bool    `json:"running"`
+	Pid      int     `json:"pid"`
+	ExitCode int     `json:"exit_code"`
+	Restarts int     `json:"restarts"`
+	CPU      float64 `json:"cpu"`
+	Mem      int64   `json:"mem"`
+}
+
+// actionJSON is the JSON result of start, stop and restart.
+type actionJSON struct {
+	Project string `json:"project"`
+	Service string `json:"service,omitempty"`
+	Action  string `json:"action"`
+	Result  string `json:"result"`
+}
+
+func runHelp(e *env, _ []string) int {
+	fmt.Fprintln(e.stderr, "Usage: devdash [command]")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "Without a command, devdash starts the dashboard.")
+	fmt.Fprintln(e.stderr)
+	fmt.Fprintln(e.stderr, "Commands:")
+	for _, name := range []string{"list", "status", "start", "stop", "restart", "scan"} {
+		fmt.Fprintf(e.stderr, "  devdash %s\n", commands[name].usage)
+	}
+	for _, usage := range groupUsage {
+		fmt.Fprintf(e.stderr, "  devdash %s\n", usage)
+	}
+	for _, usage := range configUsage {
+		fmt.Fprintf(e.stderr, "  devdash %s\n", usage)
+	}
+	return ExitOK
+}
+
+func runList(e *env, args []string) int {
+	if len(args) != 0 {
+		return e.errorf(ExitUsage, "usage: devdash %s", commands["list"].usage)
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+
+	var projects []projectJSON
+	for _, p := range reg.Projects {
+		if p.Hidden && !e.all {
+			continue
+		}
+		projects = append(projects, projectJSON{
+			ID:     p.ID,
+			Name:   p.Name,
+			Path:   p.Path,
+			Kind:   string(p.Kind),
+			State:  p.DetectState().String(),
+			Hidden: p.Hidden,
+		})
+	}
+
+	if e.json {
+		if projects == nil {
+			projects = []projectJSON{}
+		}
+		return e.writeJSON(projects)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATE\tPATH")
+	for _, p := range projects {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.State, p.Path)
+	}
+	w.Flush()
+	return ExitOK
+}
+
+func runStatus(e *env, args []string) int {
+	if len(args) != 1 {
+		return e.errorf(ExitUsage, "usage: devdash %s", commands["status"].usage)
+	}
+	p, code := e.findProject(args[0])
+	if p == nil {
+		return code
+	}
+
+	state := p.DetectState()
+	out := projectJSON{
+		ID:     p.ID,
+		Name:   p.Name,
+		Path:   p.Path,
+		Kind:   string(p.Kind),
+		State:  state.String(),
+		Hidden: p.Hidden,
+	}
+
+	if state.IsActive() {
+		client := compose.NewClient(p.SocketPath())
+		if err := client.Connect(); err == nil {
+			if status, err := client.GetStatus(); err == nil {
+				for _, proc := range status.Processes {
+					out.Services = append(out.Services, serviceJSON{
+						Name:     proc.Name,
+						Status:   proc.Status,
+						Running:  proc.IsRunning,
+						Pid:      proc.Pid,
+						ExitCode: proc.ExitCode,
+						Restarts: proc.Restarts,
+						CPU:      proc.CPU,
+						Mem:      proc.Mem,
+					})
+				}
+			}
+		}
+	}
+
+	if e.json {
+		if code := e.writeJSON(out); code != ExitOK {
+			return code
+		}
+	} else {
+		fmt.Fprintf(e.stdout, "%s (%s): %s\n", out.Name, out.Path, out.State)
+		if len(out.Services) > 0 {
+			w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "SERVICE\tSTATUS\tPID\tRESTARTS\tEXIT")
+			for _, svc := range out.Services {
+				fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", svc.Name, svc.Status, svc.Pid, svc.Restarts, svc.ExitCode)
+			}
+			w.Flush()
+		}
+	}
+
+	return stateExitCode(state)
+}
+
+// stateExitCode maps a project state to the status command's exit code.
+func stateExitCode(state registry.ProjectState) int {
+	switch state {
+	case registry.StateRunning:
+		return ExitOK
+	case registry.StateDegraded:
+		return ExitDegraded
+	case registry.StateMissing:
+		return ExitMissing
+	default:
+		return ExitNotRunning
+	}
+}
+
+// runAction returns the handler for start, stop and restart.
+func runAction(action string) func(e *env, args []string) int {
+	return func(e *env, args []string) int {
+		if len(args) < 1 || len(args) > 2 {
+			return e.errorf(ExitUsage, "usage: devdash %s", commands[action].usage)
+		}
+		p, code := e.findProject(args[0])
+		if p == nil {
+			return code
+		}
+
+		var result string
+		if len(args) == 2 {
+			result, code = serviceAction(e, p, args[1], action)
+		} else {
+			result, code = projectAction(e, p, action)
+		}
+		if code != ExitOK {
+			return code
+		}
+
+		if e.json {
+			return e.writeJSON(actionJSON{
+				Project: p.Name,
+				Service: serviceArg(args),
+				Action:  action,
+				Result:  result,
+			})
+		}
+		target := p.Name
+		if svc := serviceArg(args); svc != "" {
+			target = svc + " in " + p.Name
+		}
+		fmt.Fprintf(e.stdout, "%s: %s\n", target, result)
+		return ExitOK
+	}
+}
+
+func serviceArg(args []string) string {
+	if len(args) == 2 {
+		return args[1]
+	}
+	return ""
+}
+
+// projectAction starts, stops or restarts a whole project.
+func projectAction(e *env, p *registry.Project, action string) (string, int) {
+	state := p.DetectState()
+	if state == registry.StateMissing {
+		return "", e.errorf(ExitMissing, "project directory %s does not exist", p.Path)
+	}
+	running := state.IsActive()
+
+	switch action {
+	case "start":
+		if running {
+			return "already running", ExitOK
+		}
+		if err := devenv.Up(p.Dir(), p.Kind); err != nil {
+			return "", e.errorf(ExitError, "failed to start %s: %v", p.Name, err)
+		}
+		return "started", ExitOK
+	case "stop":
+		if !running {
+			return "not running", ExitOK
+		}
+		if err := devenv.Shutdown(p.SocketPath(), p.Dir(), p.Kind); err != nil {
+			return "", e.errorf(ExitError, "failed to stop %s: %v", p.Name, err)
+		}
+		return "stopped", ExitOK
+	default:
+		if running {
+			if err := devenv.Shutdown(p.SocketPath(), p.Dir(), p.Kind); err != nil {
+				return "", e.errorf(ExitError, "failed to stop %s: %v", p.Name, err)
+			}
+			// Shutdown returns before process-compose exits; starting
+			// while it is still up would find the project running.
+			if !waitStopped(p) {
+				return "", e.errorf(ExitError, "%s still running %s after stop", p.Name, stopTimeout)
+			}
+		}
+		if err := devenv.Up(p.Dir(), p.Kind); err != nil {
+			return "", e.errorf(ExitError, "failed to start %s: %v", p.Name, err)
+		}
+		return "restarted", ExitOK
+	}
+}
+
+// waitStopped polls until p is no longer active or stopTimeout passes,
+// reporting whether it stopped.
+func waitStopped(p *registry.Project) bool {
+	deadline := time.Now().Add(stopTimeout)
+	for p.DetectState().IsActive() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(stopPollInterval)
+	}
+	return true
+}
+
+// serviceAction starts, stops or restarts a single service.
+func serviceAction(e *env, p *registry.Project, service, action string) (string, int) {
+	client := compose.NewClient(p.SocketPath())
+	if err := client.Connect(); err != nil {
+		return "", e.errorf(ExitNotRunning, "%s is not running", p.Name)
+	}
+
+	status, err := client.GetStatus()
+	if err != nil {
+		return "", e.errorf(ExitError, "failed to query %s: %v", p.Name, err)
+	}
+	found := false
+	for _, proc := range status.Processes {
+		if proc.Name == service {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", e.errorf(ExitNotFound, "service %q not found in %s", service, p.Name)
+	}
+
+	var result string
+	switch action {
+	case "start":
+		err = client.StartProcess(service)
+		result = "started"
+	case "stop":
+		err = client.StopProcess(service)
+		result = "stopped"
+	default:
+		err = client.RestartProcess(service)
+		result = "restarted"
+	}
+	switch {
+	case errors.Is(err, compose.ErrNotConnected):
+		return "", e.errorf(ExitNotRunning, "%s is no longer running", p.Name)
+	case errors.Is(err, compose.ErrProcessNotFound):
+		return "", e.errorf(ExitNotFound, "service %q not found in %s", service, p.Name)
+	case err != nil:
+		return "", e.errorf(ExitError, "failed to %s %s: %v", action, service, err)
+	}
+	return result, ExitOK
+}
+
+// loadRegistry loads the registry, printing any warnings about the file.
+func (e *env) loadRegistry() (*registry.Registry, error) {
+	reg, err := registry.Load(registry.Path())
+	if err == nil {
+		e.warnFile(registry.Path(), reg.Warnings())
+	}
+	return reg, err
+}
+
+// loadConfig loads the config, printing any warnings about the file.
+func (e *env) loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(config.Path())
+	if err == nil {
+		e.warnFile(config.Path(), cfg.Warnings())
+	}
+	return cfg, err
+}
+
+func (e *env) warnFile(path string, warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(e.stderr, "devdash: warning: %s: %s\n", path, w)
+	}
+}
+
+// findProject resolves a project by ID, path or name.
+// It returns nil and the exit code to use if no single project matches.
+func (e *env) findProject(ref string) (*registry.Project, int) {
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return nil, e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+	return e.resolveProject(reg, ref)
+}
+
+// resolveProject is like findProject but looks in an already loaded registry.
+func (e *env) resolveProject(reg *registry.Registry, ref string) (*registry.Project, int) {
+	path := expandPath(ref)
+	var byName []*registry.Project
+	for _, p := range reg.Projects {
+		if p.ID == ref || p.Path == path {
+			return p, ExitOK
+		}
+		if p.Name == ref {
+			byName = append(byName, p)
+		}
+	}
+
+	switch len(byName) {
+	case 0:
+		return nil, e.errorf(ExitNotFound, "project %q not found", ref)
+	case 1:
+		return byName[0], ExitOK
+	default:
+		var paths []string
+		for _, p := range byName {
+			paths = append(paths, p.Path)
+		}
+		return nil, e.errorf(ExitUsage, "project name %q is ambiguous, use a path or ID: %s", ref, strings.Join(paths, ", "))
+	}
+}
+
+// expandPath expands ~ and makes ref absolute if it looks like a path.
+func expandPath(ref string) string {
+	if strings.HasPrefix(ref, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			ref = filepath.Join(home, ref[2:])
+		}
+	}
+	if strings.ContainsRune(ref, filepath.Separator) {
+		if abs, err := filepath.Abs(ref); err == nil {
+			return abs
+		}
+	}
+	return ref
+}
+
+func runScan(e *env, args []string) int {
+	if len(args) != 0 {
+		return e.errorf(ExitUsage, "usage: devdash %s", commands["scan"].usage)
+	}
+	cfg, err := e.loadConfig()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load config: %v", err)
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+
+	cache := scanner.LoadCache(scanner.CachePath())
+	hits, scanErr := scanner.ScanWith(context.Background(), cfg.Projects.ScanPaths, scanner.Options{
+		MaxDepth: cfg.Projects.ScanDepth,
+		Exclude:  cfg.Projects.Exclude,
+		Cache:    cache,
+	})
+	if scanErr == nil {
+		// The cache only saves time; the next scan reads everything without it
+		_ = cache.Save(scanner.CachePath())
+	}
+	added := []projectJSON{}
+	var moved []string
+	for _, h := range hits {
+		if reg.FindByPath(h.Path) == nil {
+			if p := reg.FindMoved(h.Remote, h.Fingerprint); p != nil {
+				moved = append(moved, fmt.Sprintf("moved %s (%s -> %s)", p.Name, p.Path, h.Path))
+				reg.Relocate(p.Path, h.Path)
+			} else {
+				p := reg.AddProject(h.Path)
+				added = append(added, projectJSON{ID: p.ID, Name: p.Name, Path: p.Path, Kind: string(h.Kind), State: p.DetectState().String()})
+			}
+		}
+		reg.SetLayout(h.Path, h.Kind, h.Root)
+		reg.SetIdentity(h.Path, h.Remote, h.Fingerprint)
+	}
+	if err := registry.Save(registry.Path(), reg); err != nil {
+		return e.errorf(ExitError, "failed to save registry: %v", err)
+	}
+	if scanErr != nil {
+		fmt.Fprintf(e.stderr, "devdash: warning: error during project scan: %v\n", scanErr)
+	}
+
+	if e.json {
+		return e.writeJSON(added)
+	}
+	for _, p := range added {
+		fmt.Fprintf(e.stdout, "added %s (%s)\n", p.Name, p.Path)
+	}
+	for _, line := range moved {
+		fmt.Fprintln(e.stdout, line)
+	}
+	fmt.Fprintf(e.stdout, "%d projects found, %d new\n", len(hits), len(added))
+	return ExitOK
+}
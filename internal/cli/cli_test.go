@@ -0,0 +1,396 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/compose/composetest"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// setupRegistry points XDG_CONFIG_HOME and XDG_STATE_HOME at temp dirs and
+// saves a registry containing one project per path.
+func setupRegistry(t *testing.T, paths ...string) *registry.Registry {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	reg := &registry.Registry{}
+	for _, path := range paths {
+		reg.AddProject(path)
+	}
+	if err := registry.Save(registry.Path(), reg); err != nil {
+		t.Fatalf("failed to save registry: %v", err)
+	}
+	return reg
+}
+
+func run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestIsCommand(t *testing.T) {
+	for _, name := range []string{"list", "status", "start", "stop", "restart", "scan"} {
+		if !IsCommand(name) {
+			t.Errorf("IsCommand(%q) = false, want true", name)
+		}
+	}
+	if IsCommand("--demo") {
+		t.Error("IsCommand should not accept flags")
+	}
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	code, _, stderr := run("frobnicate")
+	if code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+	if !strings.Contains(stderr, "unknown command") {
+		t.Errorf("stderr should mention unknown command, got %q", stderr)
+	}
+}
+
+func TestRejectsFlagsOfOtherCommands(t *testing.T) {
+	dir := t.TempDir()
+	setupRegistry(t, dir)
+
+	for _, args := range [][]string{
+		{"status", filepath.Base(dir), "--all"},
+		{"list", "--wait"},
+		{"stop", filepath.Base(dir), "--bogus"},
+	} {
+		if code, _, _ := run(args...); code != ExitUsage {
+			t.Errorf("%v exit code = %d, want %d", args, code, ExitUsage)
+		}
+	}
+}
+
+func TestListJSON(t *testing.T) {
+	dir := t.TempDir()
+	setupRegistry(t, dir, "/nonexistent/project")
+
+	code, stdout, _ := run("list", "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+
+	var projects []projectJSON
+	if err := json.Unmarshal([]byte(stdout), &projects); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("expected 2 projects, got %d", len(projects))
+	}
+	if projects[0].State != "idle" {
+		t.Errorf("projects[0].State = %q, want idle", projects[0].State)
+	}
+	if projects[1].State != "missing" {
+		t.Errorf("projects[1].State = %q, want missing", projects[1].State)
+	}
+}
+
+func TestListSkipsHiddenUnlessAll(t *testing.T) {
+	dir := t.TempDir()
+	reg := setupRegistry(t, dir)
+	reg.ToggleHidden(dir)
+	registry.Save(registry.Path(), reg)
+
+	_, stdout, _ := run("list")
+	if strings.Contains(stdout, dir) {
+		t.Error("hidden project should not be listed without --all")
+	}
+
+	_, stdout, _ = run("list", "--all")
+	if !strings.Contains(stdout, dir) {
+		t.Error("hidden project should be listed with --all")
+	}
+}
+
+func TestStatusExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	setupRegistry(t, dir, "/nonexistent/project")
+
+	if code, _, _ := run("status", filepath.Base(dir)); code != ExitNotRunning {
+		t.Errorf("idle project exit code = %d, want %d", code, ExitNotRunning)
+	}
+	if code, _, _ := run("status", "project"); code != ExitMissing {
+		t.Errorf("missing project exit code = %d, want %d", code, ExitMissing)
+	}
+	if code, _, _ := run("status", "no-such-project"); code != ExitNotFound {
+		t.Errorf("unknown project exit code = %d, want %d", code, ExitNotFound)
+	}
+	if code, _, _ := run("status"); code != ExitUsage {
+		t.Errorf("missing argument exit code = %d, want %d", code, ExitUsage)
+	}
+}
+
+func TestStatusRunningProjectJSON(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "devdash-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	setupRegistry(t, dir)
+
+	// Serve a fake process-compose API on the project's socket
+	runDir := filepath.Join(dir, ".devenv", "run")
+	os.MkdirAll(runDir, 0755)
+	listener, err := net.Listen("unix", filepath.Join(runDir, "pc.sock"))
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	defer listener.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"name": "web", "status": "Running", "is_running": true, "pid": 42}]}`))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	code, stdout, _ := run("status", dir, "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+
+	var out projectJSON
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if out.State != "running" {
+		t.Errorf("state = %q, want running", out.State)
+	}
+	if len(out.Services) != 1 || out.Services[0].Pid != 42 {
+		t.Errorf("unexpected services: %+v", out.Services)
+	}
+}
+
+func TestServiceActionRequiresRunningProject(t *testing.T) {
+	dir := t.TempDir()
+	setupRegistry(t, dir)
+
+	code, _, stderr := run("restart", filepath.Base(dir), "web")
+	if code != ExitNotRunning {
+		t.Errorf("exit code = %d, want %d", code, ExitNotRunning)
+	}
+	if !strings.Contains(stderr, "not running") {
+		t.Errorf("stderr = %q, want not running message", stderr)
+	}
+}
+
+func TestServiceStop(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "devdash-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	setupRegistry(t, dir)
+
+	os.MkdirAll(filepath.Join(dir, ".devenv", "run"), 0755)
+	srv, err := composetest.New(filepath.Join(dir, ".devenv", "run", "pc.sock"))
+	if err != nil {
+		t.Fatalf("failed to start fake server: %v", err)
+	}
+	defer srv.Close()
+	srv.AddProcess("web")
+
+	code, stdout, _ := run("stop", dir, "web", "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+	var out actionJSON
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if out.Result != "stopped" {
+		t.Errorf("result = %q, want %q", out.Result, "stopped")
+	}
+	if p, _ := srv.Process("web"); p.Running {
+		t.Error("web should be stopped")
+	}
+
+	code, stdout, _ = run("stop", dir, "web")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+	if want := "web in " + filepath.Base(dir) + ": stopped\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestRestartWaitsForShutdown(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "devdash-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	setupRegistry(t, dir)
+
+	defer func(timeout, interval time.Duration) {
+		stopTimeout, stopPollInterval = timeout, interval
+	}(stopTimeout, stopPollInterval)
+	stopTimeout, stopPollInterval = 100*time.Millisecond, 10*time.Millisecond
+
+	// A process-compose that accepts the shutdown but never exits
+	runDir := filepath.Join(dir, ".devenv", "run")
+	os.MkdirAll(runDir, 0755)
+	listener, err := net.Listen("unix", filepath.Join(runDir, "pc.sock"))
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	defer listener.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"name": "web", "status": "Running", "is_running": true, "pid": 42}]}`))
+	})
+	mux.HandleFunc("/project/stop", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "stopped"}`))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	code, _, stderr := run("restart", dir)
+	if code != ExitError {
+		t.Errorf("exit code = %d, want %d", code, ExitError)
+	}
+	if !strings.Contains(stderr, "still running") {
+		t.Errorf("stderr = %q, want still running message", stderr)
+	}
+}
+
+func TestStopIdleProjectIsNoop(t *testing.T) {
+	dir := t.TempDir()
+	setupRegistry(t, dir)
+
+	code, stdout, _ := run("stop", filepath.Base(dir), "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+	var out actionJSON
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if out.Result != "not running" {
+		t.Errorf("result = %q, want %q", out.Result, "not running")
+	}
+}
+
+func TestFindProjectAmbiguousName(t *testing.T) {
+	a := filepath.Join(t.TempDir(), "api")
+	b := filepath.Join(t.TempDir(), "api")
+	setupRegistry(t, a, b)
+
+	code, _, stderr := run("status", "api")
+	if code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+	if !strings.Contains(stderr, "ambiguous") {
+		t.Errorf("stderr = %q, want ambiguity message", stderr)
+	}
+}
+
+func TestScanAddsProjects(t *testing.T) {
+	setupRegistry(t)
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	os.MkdirAll(project, 0755)
+	os.WriteFile(filepath.Join(project, "devenv.nix"), []byte("{}"), 0644)
+
+	cfgDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "devdash")
+	os.MkdirAll(cfgDir, 0755)
+	cfg := "projects:\n  scan_paths:\n    - " + root + "\n  scan_depth: 2\n"
+	os.WriteFile(filepath.Join(cfgDir, "config.yaml"), []byte(cfg), 0644)
+
+	code, stdout, _ := run("scan", "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+	var added []projectJSON
+	if err := json.Unmarshal([]byte(stdout), &added); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(added) != 1 || added[0].Path != project {
+		t.Fatalf("unexpected scan result: %+v", added)
+	}
+
+	reg, _ := registry.Load(registry.Path())
+	if reg.FindByPath(project) == nil {
+		t.Error("scanned project should be saved to registry")
+	}
+}
+
+func TestScanRecordsLayouts(t *testing.T) {
+	setupRegistry(t)
+	root := t.TempDir()
+	flake := filepath.Join(root, "flake")
+	os.MkdirAll(flake, 0755)
+	os.WriteFile(filepath.Join(flake, "flake.nix"), []byte("{ outputs = { devenv, ... }: devenv.lib.mkShell { }; }"), 0644)
+	mono := filepath.Join(root, "mono")
+	api := filepath.Join(mono, "api")
+	os.MkdirAll(api, 0755)
+	os.WriteFile(filepath.Join(mono, "devenv.yaml"), []byte("imports:\n  - ./api\n"), 0644)
+	os.WriteFile(filepath.Join(api, "devenv.nix"), []byte("{}"), 0644)
+
+	cfgDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "devdash")
+	os.MkdirAll(cfgDir, 0755)
+	cfg := "projects:\n  scan_paths:\n    - " + root + "\n  scan_depth: 2\n"
+	os.WriteFile(filepath.Join(cfgDir, "config.yaml"), []byte(cfg), 0644)
+
+	if code, _, stderr := run("scan"); code != ExitOK {
+		t.Fatalf("exit code = %d: %s", code, stderr)
+	}
+	reg, _ := registry.Load(registry.Path())
+	if p := reg.FindByPath(flake); p == nil || p.Kind != registry.KindFlake {
+		t.Errorf("flake project = %+v, want kind flake", p)
+	}
+	p := reg.FindByPath(api)
+	if p == nil || p.Kind != registry.KindImported || p.Root != mono {
+		t.Fatalf("imported project = %+v, want kind imported with root %s", p, mono)
+	}
+	if want := filepath.Join(mono, ".devenv", "run", "pc.sock"); p.SocketPath() != want {
+		t.Errorf("SocketPath() = %q, want the root's socket %q", p.SocketPath(), want)
+	}
+}
+
+func TestScanRelocatesMovedProjects(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "renamed")
+	os.MkdirAll(filepath.Join(project, ".git"), 0755)
+	os.WriteFile(filepath.Join(project, ".git", "config"), []byte("[remote \"origin\"]\n\turl = git@example.com:acme/api.git\n"), 0644)
+	os.WriteFile(filepath.Join(project, "devenv.nix"), []byte("{}"), 0644)
+
+	reg := setupRegistry(t, "/gone/api")
+	p := reg.FindByPath("/gone/api")
+	p.Remote = "git@example.com:acme/api.git"
+	p.Tags = []string{"backend"}
+	registry.Save(registry.Path(), reg)
+
+	cfgDir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "devdash")
+	os.MkdirAll(cfgDir, 0755)
+	cfg := "projects:\n  scan_paths:\n    - " + root + "\n  scan_depth: 2\n"
+	os.WriteFile(filepath.Join(cfgDir, "config.yaml"), []byte(cfg), 0644)
+
+	code, stdout, stderr := run("scan")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "moved api (/gone/api -> "+project+")") || !strings.Contains(stdout, "0 new") {
+		t.Errorf("stdout = %q, want the move and no new project", stdout)
+	}
+	saved, _ := registry.Load(registry.Path())
+	if got := saved.FindByPath(project); got == nil || got.ID != p.ID || !got.HasTag("backend") {
+		t.Errorf("moved project = %+v, want the same ID and tags", got)
+	}
+}
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/schema"
+)
+
+// configUsage lists the config subcommands for help output.
+var configUsage = []string{
+	"config check [--json]",
+}
+
+// problemJSON is the JSON representation of a config problem.
+type problemJSON struct {
+	Key      string `json:"key"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// configCheckJSON is the JSON result of config check.
+type configCheckJSON struct {
+	Path     string        `json:"path"`
+	Valid    bool          `json:"valid"`
+	Problems []problemJSON `json:"problems"`
+}
+
+func runConfig(e *env, args []string) int {
+	if len(args) == 0 {
+		return e.configUsageError()
+	}
+	switch sub, args := args[0], args[1:]; sub {
+	case "check":
+		return runConfigCheck(e, args)
+	default:
+		return e.configUsageError()
+	}
+}
+
+func (e *env) configUsageError() int {
+	fmt.Fprintln(e.stderr, "usage:")
+	for _, usage := range configUsage {
+		fmt.Fprintf(e.stderr, "  devdash %s\n", usage)
+	}
+	return ExitUsage
+}
+
+// runConfigCheck validates the config file without loading it, so it never
+// creates, upgrades or rewrites the file.
+func runConfigCheck(e *env, args []string) int {
+	if len(args) != 0 {
+		return e.configUsageError()
+	}
+	path := config.Path()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		fmt.Fprintf(e.stderr, "devdash: %s does not exist, checking the defaults\n", path)
+	} else if err != nil {
+		return e.errorf(ExitError, "failed to read config: %v", err)
+	}
+
+	if version, err := schema.Version(data); err == nil && version > config.SchemaVersion {
+		return e.errorf(ExitError, "%s: version %d was written by a newer devdash (this one supports up to %d)", path, version, config.SchemaVersion)
+	}
+	cfg, err := config.Parse(data)
+	if err != nil {
+		return e.errorf(ExitError, "%s: %v", path, err)
+	}
+
+	result := configCheckJSON{Path: path, Valid: true, Problems: []problemJSON{}}
+	for _, p := range cfg.Check() {
+		severity := "warning"
+		if !p.Warning {
+			severity = "error"
+			result.Valid = false
+		}
+		result.Problems = append(result.Problems, problemJSON{
+			Key: p.Path, Line: p.Line, Column: p.Column, Severity: severity, Message: p.Message,
+		})
+	}
+
+	code := ExitOK
+	if !result.Valid {
+		code = ExitError
+	}
+	if e.json {
+		if c := e.writeJSON(result); c != ExitOK {
+			return c
+		}
+		return code
+	}
+
+	for _, p := range result.Problems {
+		if p.Line > 0 {
+			fmt.Fprintf(e.stdout, "%s:%d:%d: %s: %s: %s\n", path, p.Line, p.Column, p.Severity, p.Key, p.Message)
+		} else {
+			fmt.Fprintf(e.stdout, "%s: %s: %s: %s\n", path, p.Severity, p.Key, p.Message)
+		}
+	}
+	if result.Valid {
+		fmt.Fprintf(e.stdout, "%s: ok\n", path)
+	}
+	return code
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infktd/devdash/internal/config"
+)
+
+// writeConfig points XDG_CONFIG_HOME at a temp dir holding a config file.
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	path := config.Path()
+	os.MkdirAll(filepath.Dir(path), 0755)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigCheckValid(t *testing.T) {
+	path := writeConfig(t, "version: 1\nprojects:\n  scan_paths: []\n")
+
+	code, stdout, _ := run("config", "check")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d, want %d", code, ExitOK)
+	}
+	if stdout != path+": ok\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+}
+
+func TestConfigCheckReportsProblems(t *testing.T) {
+	path := writeConfig(t, "version: 1\nprojects:\n  scan_paths: []\n  scan_depth: 0\nui:\n  theme: nordd\n  colour: red\n")
+
+	code, stdout, _ := run("config", "check")
+	if code != ExitError {
+		t.Fatalf("exit code = %d, want %d", code, ExitError)
+	}
+	want := []string{
+		path + ":4:15: error: projects.scan_depth: must be at least 1, got 0",
+		path + ":6:10: error: ui.theme: unknown value \"nordd\"",
+		path + ":7:3: warning: ui.colour: unknown key",
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("stdout:\n%s", stdout)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, want[i]) {
+			t.Errorf("line %d = %q, want prefix %q", i, line, want[i])
+		}
+	}
+}
+
+func TestConfigCheckJSON(t *testing.T) {
+	writeConfig(t, "version: 1\nprojects:\n  scan_paths: []\npolling:\n  background_project: -5\n")
+
+	code, stdout, _ := run("config", "check", "--json")
+	if code != ExitError {
+		t.Fatalf("exit code = %d, want %d", code, ExitError)
+	}
+	var result configCheckJSON
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if result.Valid || len(result.Problems) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if p := result.Problems[0]; p.Key != "polling.background_project" || p.Line != 5 || p.Severity != "error" {
+		t.Errorf("unexpected problem: %+v", p)
+	}
+}
+
+func TestConfigCheckDoesNotWrite(t *testing.T) {
+	original := "ui:\n  theme: nord\nprojects:\n  scan_paths: []\n"
+	path := writeConfig(t, original)
+
+	if code, stdout, _ := run("config", "check"); code != ExitOK {
+		t.Fatalf("exit code = %d:\n%s", code, stdout)
+	}
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Error("config check should leave an old file for Load to upgrade")
+	}
+}
+
+func TestConfigCheckUsage(t *testing.T) {
+	if code, _, _ := run("config", "fix"); code != ExitUsage {
+		t.Errorf("exit code = %d, want %d", code, ExitUsage)
+	}
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/stack"
+)
+
+// groupUsage lists the group subcommands for help output.
+var groupUsage = []string{
+	"group list [--json]",
+	"group create <group> <project>... [--wait]",
+	"group after <group> <project> <dependency>...",
+	"group delete <group>",
+	"group start <group> [--wait] [--json]",
+	"group stop <group> [--json]",
+	"group restart <group> [--wait] [--json]",
+}
+
+// groupJSON is the JSON representation of a group.
+type groupJSON struct {
+	Name     string              `json:"name"`
+	State    string              `json:"state"`
+	Wait     bool                `json:"wait"`
+	Projects []projectJSON       `json:"projects"`
+	After    map[string][]string `json:"after,omitempty"`
+}
+
+// groupActionJSON is the JSON result of a group start, stop or restart.
+type groupActionJSON struct {
+	Group    string       `json:"group"`
+	Action   string       `json:"action"`
+	Projects []actionJSON `json:"projects"`
+	Error    string       `json:"error,omitempty"`
+}
+
+func runGroup(e *env, args []string) int {
+	if len(args) == 0 {
+		return e.groupUsageError()
+	}
+	switch sub, args := args[0], args[1:]; sub {
+	case "list":
+		return runGroupList(e, args)
+	case "create":
+		return runGroupCreate(e, args)
+	case "after":
+		return runGroupAfter(e, args)
+	case "delete":
+		return runGroupDelete(e, args)
+	case "start", "stop", "restart":
+		return runGroupAction(e, sub, args)
+	default:
+		return e.groupUsageError()
+	}
+}
+
+func (e *env) groupUsageError() int {
+	fmt.Fprintln(e.stderr, "usage:")
+	for _, usage := range groupUsage {
+		fmt.Fprintf(e.stderr, "  devdash %s\n", usage)
+	}
+	return ExitUsage
+}
+
+func runGroupList(e *env, args []string) int {
+	if len(args) != 0 {
+		return e.groupUsageError()
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+
+	groups := []groupJSON{}
+	for _, g := range reg.Groups {
+		out := groupJSON{Name: g.Name, Wait: g.Wait, Projects: []projectJSON{}}
+		var states []registry.ProjectState
+		for _, path := range g.Projects {
+			pj := projectJSON{Path: path, State: registry.StateMissing.String()}
+			if p := reg.FindByPath(path); p != nil {
+				state := p.DetectState()
+				states = append(states, state)
+				pj = projectJSON{ID: p.ID, Name: p.Name, Path: p.Path, State: state.String(), Hidden: p.Hidden}
+			} else {
+				states = append(states, registry.StateMissing)
+			}
+			out.Projects = append(out.Projects, pj)
+		}
+		out.State = registry.AggregateState(states).String()
+		if len(g.After) > 0 {
+			out.After = g.After
+		}
+		groups = append(groups, out)
+	}
+
+	if e.json {
+		return e.writeJSON(groups)
+	}
+
+	w := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP\tSTATE\tPROJECTS")
+	for _, g := range groups {
+		var names []string
+		for _, p := range g.Projects {
+			if p.Name == "" {
+				names = append(names, p.Path+" (missing)")
+			} else {
+				names = append(names, p.Name)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", g.Name, g.State, strings.Join(names, ", "))
+	}
+	w.Flush()
+	return ExitOK
+}
+
+func runGroupCreate(e *env, args []string) int {
+	if len(args) < 2 {
+		return e.groupUsageError()
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+
+	var paths []string
+	for _, ref := range args[1:] {
+		p, code := e.resolveProject(reg, ref)
+		if p == nil {
+			return code
+		}
+		paths = append(paths, p.Path)
+	}
+	g := reg.AddGroup(args[0], paths)
+	g.Wait = e.wait
+	if _, err := g.StartOrder(); err != nil {
+		return e.errorf(ExitUsage, "%v", err)
+	}
+	if err := registry.Save(registry.Path(), reg); err != nil {
+		return e.errorf(ExitError, "failed to save registry: %v", err)
+	}
+	fmt.Fprintf(e.stdout, "group %s: %d projects\n", g.Name, len(g.Projects))
+	return ExitOK
+}
+
+func runGroupAfter(e *env, args []string) int {
+	if len(args) < 3 {
+		return e.groupUsageError()
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+	g := reg.FindGroup(args[0])
+	if g == nil {
+		return e.errorf(ExitNotFound, "group %q not found", args[0])
+	}
+
+	var paths []string
+	for _, ref := range args[1:] {
+		p, code := e.resolveProject(reg, ref)
+		if p == nil {
+			return code
+		}
+		paths = append(paths, p.Path)
+	}
+	if g.After == nil {
+		g.After = make(map[string][]string)
+	}
+	g.After[paths[0]] = paths[1:]
+	if _, err := g.StartOrder(); err != nil {
+		return e.errorf(ExitUsage, "%v", err)
+	}
+	if err := registry.Save(registry.Path(), reg); err != nil {
+		return e.errorf(ExitError, "failed to save registry: %v", err)
+	}
+	fmt.Fprintf(e.stdout, "group %s: %s starts after %d project(s)\n", g.Name, args[1], len(paths)-1)
+	return ExitOK
+}
+
+func runGroupDelete(e *env, args []string) int {
+	if len(args) != 1 {
+		return e.groupUsageError()
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+	if !reg.RemoveGroup(args[0]) {
+		return e.errorf(ExitNotFound, "group %q not found", args[0])
+	}
+	if err := registry.Save(registry.Path(), reg); err != nil {
+		return e.errorf(ExitError, "failed to save registry: %v", err)
+	}
+	fmt.Fprintf(e.stdout, "group %s deleted\n", args[0])
+	return ExitOK
+}
+
+// runGroupAction starts, stops or restarts every project of a group.
+func runGroupAction(e *env, action string, args []string) int {
+	if len(args) != 1 {
+		return e.groupUsageError()
+	}
+	reg, err := e.loadRegistry()
+	if err != nil {
+		return e.errorf(ExitError, "failed to load registry: %v", err)
+	}
+	g := reg.FindGroup(args[0])
+	if g == nil {
+		return e.errorf(ExitNotFound, "group %q not found", args[0])
+	}
+	if e.wait {
+		g.Wait = true
+	}
+
+	r := &stack.Runner{}
+	if !e.json {
+		r.OnStep = func(p *registry.Project, step string) {
+			fmt.Fprintf(e.stdout, "%s: %s\n", p.Name, step)
+		}
+	}
+	var results []stack.Result
+	switch action {
+	case "start":
+		results, err = r.Start(context.Background(), reg, g)
+	case "stop":
+		results, err = r.Stop(context.Background(), reg, g)
+	default:
+		results, err = r.Restart(context.Background(), reg, g)
+	}
+
+	if e.json {
+		out := groupActionJSON{Group: g.Name, Action: action, Projects: []actionJSON{}}
+		for _, res := range results {
+			out.Projects = append(out.Projects, actionJSON{Project: res.Project.Name, Action: action, Result: res.Result})
+		}
+		if err != nil {
+			out.Error = err.Error()
+		}
+		if code := e.writeJSON(out); code != ExitOK || err == nil {
+			return code
+		}
+		return ExitError
+	}
+
+	for _, res := range results {
+		fmt.Fprintf(e.stdout, "%s: %s\n", res.Project.Name, res.Result)
+	}
+	if err != nil {
+		return e.errorf(ExitError, "failed to %s group %s: %v", action, g.Name, err)
+	}
+	return ExitOK
+}
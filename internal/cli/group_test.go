@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func TestGroupCreateAndList(t *testing.T) {
+	base := t.TempDir()
+	api, web := filepath.Join(base, "api"), filepath.Join(base, "web")
+	setupRegistry(t, api, web)
+
+	if code, _, stderr := run("group", "create", "product", "web", "api", "--wait"); code != ExitOK {
+		t.Fatalf("create exit code = %d: %s", code, stderr)
+	}
+	if code, _, stderr := run("group", "after", "product", "web", "api"); code != ExitOK {
+		t.Fatalf("after exit code = %d: %s", code, stderr)
+	}
+
+	reg, _ := registry.Load(registry.Path())
+	g := reg.FindGroup("product")
+	if g == nil || !g.Wait {
+		t.Fatalf("group not saved with wait: %+v", g)
+	}
+	if order, _ := g.StartOrder(); !reflect.DeepEqual(order, []string{api, web}) {
+		t.Errorf("start order = %v, want api first", order)
+	}
+
+	code, stdout, _ := run("group", "list", "--json")
+	if code != ExitOK {
+		t.Fatalf("list exit code = %d", code)
+	}
+	var groups []groupJSON
+	if err := json.Unmarshal([]byte(stdout), &groups); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(groups) != 1 || groups[0].State != "missing" || len(groups[0].Projects) != 2 {
+		t.Errorf("unexpected groups: %+v", groups)
+	}
+}
+
+func TestGroupAfterRejectsCycle(t *testing.T) {
+	base := t.TempDir()
+	setupRegistry(t, filepath.Join(base, "api"), filepath.Join(base, "web"))
+	run("group", "create", "product", "web", "api")
+	run("group", "after", "product", "web", "api")
+
+	code, _, stderr := run("group", "after", "product", "api", "web")
+	if code != ExitUsage || !strings.Contains(stderr, "cycle") {
+		t.Errorf("exit code = %d, stderr %q; want a cycle error", code, stderr)
+	}
+}
+
+func TestGroupStopIdleProjects(t *testing.T) {
+	base := t.TempDir()
+	api := filepath.Join(base, "api")
+	setupRegistry(t, api, t.TempDir())
+	run("group", "create", "product", api)
+
+	code, stdout, stderr := run("group", "stop", "product", "--json")
+	if code != ExitOK {
+		t.Fatalf("exit code = %d: %s", code, stderr)
+	}
+	var out groupActionJSON
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if len(out.Projects) != 1 || out.Projects[0].Result != "not running" {
+		t.Errorf("unexpected result: %+v", out)
+	}
+}
+
+func TestGroupNotFound(t *testing.T) {
+	setupRegistry(t)
+	for _, args := range [][]string{
+		{"group", "start", "ghost"},
+		{"group", "delete", "ghost"},
+		{"group", "after", "ghost", "a", "b"},
+	} {
+		if code, _, _ := run(args...); code != ExitNotFound {
+			t.Errorf("%v exit code = %d, want %d", args, code, ExitNotFound)
+		}
+	}
+	if code, _, _ := run("group", "frobnicate"); code != ExitUsage {
+		t.Errorf("unknown subcommand exit code = %d, want %d", code, ExitUsage)
+	}
+}
@@ -1,40 +1,85 @@
 // Package compose provides a client for the process-compose REST API.
+//
+// Every method has a Context variant that honors cancellation and deadlines.
+// Errors can be checked with errors.Is against ErrNotConnected,
+// ErrProcessNotFound and ErrAPIError, or unpacked with errors.As into an
+// *APIError.
 package compose
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// DefaultTimeout bounds each request unless changed with SetTimeout.
+const DefaultTimeout = 5 * time.Second
+
 // Client communicates with process-compose via Unix socket.
 type Client struct {
 	socketPath string
 	httpClient *http.Client
 	connected  bool
+
+	// Retries for requests that never reached the socket, and for GETs
+	// answered with a server error
+	retries    int
+	retryDelay time.Duration
 }
 
 // NewClient creates a new process-compose client.
 func NewClient(socketPath string) *Client {
-	return &Client{
-		socketPath: socketPath,
-		httpClient: &http.Client{
-			Transport: &http.Transport{
-				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
-					return net.Dial("unix", socketPath)
-				},
+	c := &Client{socketPath: socketPath}
+	c.httpClient = &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return c.dial(ctx)
 			},
-			Timeout: 5 * time.Second,
 		},
+		Timeout: DefaultTimeout,
+	}
+	return c
+}
+
+// SetTimeout sets the time limit for each request. Zero means no limit
+// beyond the request's context.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.httpClient.Timeout = timeout
+}
+
+// SetRetries makes failed requests retry up to retries times, waiting delay
+// between attempts. Only requests that never reached process-compose, and
+// GETs answered with a server error, are retried.
+func (c *Client) SetRetries(retries int, delay time.Duration) {
+	c.retries = retries
+	c.retryDelay = delay
+}
+
+// dial connects to the socket, wrapping failures so they match ErrNotConnected.
+func (c *Client) dial(ctx context.Context) (net.Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", c.socketPath)
+	if err != nil {
+		return nil, &connectError{err: err}
 	}
+	return conn, nil
 }
 
 // Connect attempts to connect to the process-compose socket.
 func (c *Client) Connect() error {
-	conn, err := net.Dial("unix", c.socketPath)
+	return c.ConnectContext(context.Background())
+}
+
+// ConnectContext is like Connect but honors ctx.
+func (c *Client) ConnectContext(ctx context.Context) error {
+	conn, err := c.dial(ctx)
 	if err != nil {
 		c.connected = false
 		return err
@@ -51,101 +96,294 @@ func (c *Client) IsConnected() bool {
 
 // GetStatus fetches the current process status.
 func (c *Client) GetStatus() (*ProjectStatus, error) {
-	resp, err := c.httpClient.Get("http://unix/processes")
-	if err != nil {
+	return c.GetStatusContext(context.Background())
+}
+
+// GetStatusContext is like GetStatus but honors ctx.
+func (c *Client) GetStatusContext(ctx context.Context) (*ProjectStatus, error) {
+	var apiResp processesResponse
+	if err := c.do(ctx, http.MethodGet, "/processes", nil, &apiResp); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return &ProjectStatus{Processes: apiResp.Data}, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+// StartProcess starts a specific process.
+func (c *Client) StartProcess(name string) error {
+	return c.StartProcessContext(context.Background(), name)
+}
+
+// StartProcessContext is like StartProcess but honors ctx.
+func (c *Client) StartProcessContext(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/process/"+url.PathEscape(name)+"/start", nil, nil)
+}
+
+// StopProcess stops a specific process.
+func (c *Client) StopProcess(name string) error {
+	return c.StopProcessContext(context.Background(), name)
+}
+
+// StopProcessContext is like StopProcess but honors ctx.
+func (c *Client) StopProcessContext(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/process/"+url.PathEscape(name)+"/stop", nil, nil)
+}
+
+// RestartProcess restarts a specific process.
+func (c *Client) RestartProcess(name string) error {
+	return c.RestartProcessContext(context.Background(), name)
+}
+
+// RestartProcessContext is like RestartProcess but honors ctx.
+func (c *Client) RestartProcessContext(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodPost, "/process/"+url.PathEscape(name)+"/restart", nil, nil)
+}
+
+// ShutdownProject stops all processes and shuts down.
+func (c *Client) ShutdownProject() error {
+	return c.ShutdownProjectContext(context.Background())
+}
+
+// ShutdownProjectContext is like ShutdownProject but honors ctx.
+func (c *Client) ShutdownProjectContext(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/project/stop", nil, nil)
+}
+
+// GetLogs fetches recent logs for a process.
+// endOffset is offset from end (0 = most recent), limit is max lines to return.
+func (c *Client) GetLogs(processName string, endOffset, limit int) ([]string, error) {
+	return c.GetLogsContext(context.Background(), processName, endOffset, limit)
+}
+
+// GetLogsContext is like GetLogs but honors ctx.
+func (c *Client) GetLogsContext(ctx context.Context, processName string, endOffset, limit int) ([]string, error) {
+	path := fmt.Sprintf("/process/logs/%s/%d/%d", url.PathEscape(processName), endOffset, limit)
+	var logsResp logsResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &logsResp); err != nil {
+		return nil, err
 	}
+	return logsResp.Logs, nil
+}
 
-	var apiResp processesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+// GetProcess fetches the status of a single process.
+func (c *Client) GetProcess(name string) (*ProcessStatus, error) {
+	return c.GetProcessContext(context.Background(), name)
+}
+
+// GetProcessContext is like GetProcess but honors ctx.
+func (c *Client) GetProcessContext(ctx context.Context, name string) (*ProcessStatus, error) {
+	var status ProcessStatus
+	if err := c.do(ctx, http.MethodGet, "/process/"+url.PathEscape(name), nil, &status); err != nil {
 		return nil, err
 	}
+	return &status, nil
+}
 
-	return &ProjectStatus{Processes: apiResp.Data}, nil
+// GetProcessInfo fetches the configuration of a process.
+func (c *Client) GetProcessInfo(name string) (*ProcessConfig, error) {
+	return c.GetProcessInfoContext(context.Background(), name)
 }
 
-// StartProcess starts a specific process.
-func (c *Client) StartProcess(name string) error {
-	url := fmt.Sprintf("http://unix/process/%s/start", name)
-	resp, err := c.httpClient.Post(url, "application/json", nil)
-	if err != nil {
-		return err
+// GetProcessInfoContext is like GetProcessInfo but honors ctx.
+func (c *Client) GetProcessInfoContext(ctx context.Context, name string) (*ProcessConfig, error) {
+	var info ProcessConfig
+	if err := c.do(ctx, http.MethodGet, "/process/info/"+url.PathEscape(name), nil, &info); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &info, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to start process: %d", resp.StatusCode)
+// GetProcessPorts fetches the ports a process is listening on.
+func (c *Client) GetProcessPorts(name string) (*ProcessPorts, error) {
+	return c.GetProcessPortsContext(context.Background(), name)
+}
+
+// GetProcessPortsContext is like GetProcessPorts but honors ctx.
+func (c *Client) GetProcessPortsContext(ctx context.Context, name string) (*ProcessPorts, error) {
+	var ports ProcessPorts
+	if err := c.do(ctx, http.MethodGet, "/process/ports/"+url.PathEscape(name), nil, &ports); err != nil {
+		return nil, err
 	}
-	return nil
+	return &ports, nil
 }
 
-// StopProcess stops a specific process.
-func (c *Client) StopProcess(name string) error {
-	url := fmt.Sprintf("http://unix/process/%s/stop", name)
-	resp, err := c.httpClient.Post(url, "application/json", nil)
-	if err != nil {
-		return err
+// ScaleProcess sets the number of replicas of a process.
+func (c *Client) ScaleProcess(name string, replicas int) error {
+	return c.ScaleProcessContext(context.Background(), name, replicas)
+}
+
+// ScaleProcessContext is like ScaleProcess but honors ctx.
+func (c *Client) ScaleProcessContext(ctx context.Context, name string, replicas int) error {
+	path := fmt.Sprintf("/process/scale/%s/%d", url.PathEscape(name), replicas)
+	return c.do(ctx, http.MethodPatch, path, nil, nil)
+}
+
+// UpdateProcess replaces the configuration of a process, restarting it if
+// it is running.
+func (c *Client) UpdateProcess(cfg ProcessConfig) error {
+	return c.UpdateProcessContext(context.Background(), cfg)
+}
+
+// UpdateProcessContext is like UpdateProcess but honors ctx.
+func (c *Client) UpdateProcessContext(ctx context.Context, cfg ProcessConfig) error {
+	return c.do(ctx, http.MethodPost, "/process", cfg, nil)
+}
+
+// GetDependencyGraph fetches the process dependency graph.
+func (c *Client) GetDependencyGraph() (*DependencyGraph, error) {
+	return c.GetDependencyGraphContext(context.Background())
+}
+
+// GetDependencyGraphContext is like GetDependencyGraph but honors ctx.
+func (c *Client) GetDependencyGraphContext(ctx context.Context) (*DependencyGraph, error) {
+	var graph DependencyGraph
+	if err := c.do(ctx, http.MethodGet, "/graph", nil, &graph); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return &graph, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to stop process: %d", resp.StatusCode)
+// GetProjectState fetches the state of the process-compose project.
+func (c *Client) GetProjectState() (*ProjectState, error) {
+	return c.GetProjectStateContext(context.Background())
+}
+
+// GetProjectStateContext is like GetProjectState but honors ctx.
+func (c *Client) GetProjectStateContext(ctx context.Context) (*ProjectState, error) {
+	var state ProjectState
+	if err := c.do(ctx, http.MethodGet, "/project/state", nil, &state); err != nil {
+		return nil, err
 	}
-	return nil
+	return &state, nil
 }
 
-// RestartProcess restarts a specific process.
-func (c *Client) RestartProcess(name string) error {
-	url := fmt.Sprintf("http://unix/process/%s/restart", name)
-	resp, err := c.httpClient.Post(url, "application/json", nil)
-	if err != nil {
-		return err
+// UpdateProject replaces the project's processes. process-compose adds,
+// updates and removes processes to match and reports what changed. Fields
+// not modeled by ProcessConfig are reset to their defaults.
+func (c *Client) UpdateProject(processes map[string]ProcessConfig) (UpdateStatus, error) {
+	return c.UpdateProjectContext(context.Background(), processes)
+}
+
+// UpdateProjectContext is like UpdateProject but honors ctx.
+func (c *Client) UpdateProjectContext(ctx context.Context, processes map[string]ProcessConfig) (UpdateStatus, error) {
+	body := struct {
+		Processes map[string]ProcessConfig `json:"processes"`
+	}{processes}
+	var status UpdateStatus
+	if err := c.do(ctx, http.MethodPost, "/project", body, &status); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return status, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to restart process: %d", resp.StatusCode)
+// ReloadProject makes process-compose reload its configuration files and
+// reports what changed.
+func (c *Client) ReloadProject() (UpdateStatus, error) {
+	return c.ReloadProjectContext(context.Background())
+}
+
+// ReloadProjectContext is like ReloadProject but honors ctx.
+func (c *Client) ReloadProjectContext(ctx context.Context) (UpdateStatus, error) {
+	var status UpdateStatus
+	if err := c.do(ctx, http.MethodPost, "/project/configuration", nil, &status); err != nil {
+		return nil, err
 	}
-	return nil
+	return status, nil
 }
 
-// ShutdownProject stops all processes and shuts down.
-func (c *Client) ShutdownProject() error {
-	resp, err := c.httpClient.Post("http://unix/project/stop", "application/json", nil)
+// GetHostname fetches the hostname of the machine running process-compose.
+func (c *Client) GetHostname() (string, error) {
+	return c.GetHostnameContext(context.Background())
+}
+
+// GetHostnameContext is like GetHostname but honors ctx.
+func (c *Client) GetHostnameContext(ctx context.Context) (string, error) {
+	var resp hostnameResponse
+	if err := c.do(ctx, http.MethodGet, "/hostname", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Name, nil
+}
+
+// GetVersion fetches the process-compose version, as reported in the
+// project state.
+func (c *Client) GetVersion() (string, error) {
+	return c.GetVersionContext(context.Background())
+}
+
+// GetVersionContext is like GetVersion but honors ctx.
+func (c *Client) GetVersionContext(ctx context.Context) (string, error) {
+	state, err := c.GetProjectStateContext(ctx)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer resp.Body.Close()
+	return state.Version, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to shutdown: %d", resp.StatusCode)
+// do sends a request to the API, retrying as configured, and decodes a JSON
+// response into out. A nil body sends no payload; a nil out discards the
+// response.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := c.send(ctx, method, path, data, out)
+		if err == nil || attempt >= c.retries || !retryable(method, err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.retryDelay):
+		}
 	}
-	return nil
 }
 
-// GetLogs fetches recent logs for a process.
-// endOffset is offset from end (0 = most recent), limit is max lines to return.
-func (c *Client) GetLogs(processName string, endOffset, limit int) ([]string, error) {
-	url := fmt.Sprintf("http://unix/process/logs/%s/%d/%d", processName, endOffset, limit)
-	resp, err := c.httpClient.Get(url)
+// send makes a single request. A nil data sends no payload.
+func (c *Client) send(ctx context.Context, method, path string, data []byte, out any) error {
+	var reqBody io.Reader
+	if data != nil {
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, "http://unix"+path, reqBody)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Report socket failures without the url.Error wrapping
+		var ce *connectError
+		if errors.As(err, &ce) {
+			c.connected = false
+			return ce
+		}
+		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get logs: %d", resp.StatusCode)
+		return newAPIError(method, path, resp)
 	}
-
-	var logsResp logsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&logsResp); err != nil {
-		return nil, err
+	if out == nil {
+		return nil
 	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
 
-	return logsResp.Logs, nil
+// retryable reports whether a failed request may be sent again.
+func retryable(method string, err error) bool {
+	if errors.Is(err, ErrNotConnected) {
+		return true
+	}
+	var apiErr *APIError
+	return method == http.MethodGet && errors.As(err, &apiErr) && apiErr.StatusCode >= 500
 }
@@ -1,10 +1,16 @@
 package compose
 
 import (
+	"encoding/json"
+	"errors"
 	"net"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/compose/composetest"
 )
 
 func TestNewClient(t *testing.T) {
@@ -288,3 +294,196 @@ func TestClientGetLogs(t *testing.T) {
 		t.Errorf("logs[1] = %q, want %q", logs[1], "log line 2")
 	}
 }
+
+// serveAPI serves handler on a fresh Unix socket and returns a client for it.
+func serveAPI(t *testing.T, socketPath string, handler http.Handler) *Client {
+	t.Helper()
+	_ = os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() {
+		server.Close()
+		os.Remove(socketPath)
+	})
+	return NewClient(socketPath)
+}
+
+func TestClientProcessEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /process/web", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "web", "status": "Running", "is_running": true, "pid": 7}`))
+	})
+	mux.HandleFunc("GET /process/info/web", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "web", "command": "npm start", "depends_on": {"db": {"condition": "process_healthy"}}, "availability": {"restart": "on_failure", "max_restarts": 3}, "replicas": 1}`))
+	})
+	mux.HandleFunc("GET /process/ports/web", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "web", "tcp_ports": [3000, 9229], "udp_ports": []}`))
+	})
+	var scaled string
+	mux.HandleFunc("PATCH /process/scale/web/3", func(w http.ResponseWriter, r *http.Request) {
+		scaled = r.URL.Path
+	})
+	var updated ProcessConfig
+	mux.HandleFunc("POST /process", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&updated)
+	})
+
+	client := serveAPI(t, "/tmp/devdash-test-process-api.sock", mux)
+
+	status, err := client.GetProcess("web")
+	if err != nil || status.Pid != 7 || !status.IsRunning {
+		t.Errorf("GetProcess() = %+v, %v", status, err)
+	}
+
+	info, err := client.GetProcessInfo("web")
+	if err != nil {
+		t.Fatalf("GetProcessInfo() error: %v", err)
+	}
+	if info.Command != "npm start" || info.DependsOn["db"].Condition != "process_healthy" || info.Availability.MaxRestarts != 3 {
+		t.Errorf("unexpected process info: %+v", info)
+	}
+
+	ports, err := client.GetProcessPorts("web")
+	if err != nil || len(ports.TCPPorts) != 2 || ports.TCPPorts[0] != 3000 {
+		t.Errorf("GetProcessPorts() = %+v, %v", ports, err)
+	}
+
+	if err := client.ScaleProcess("web", 3); err != nil || scaled == "" {
+		t.Errorf("ScaleProcess() error: %v (called: %v)", err, scaled != "")
+	}
+
+	if err := client.UpdateProcess(ProcessConfig{Name: "web", Command: "npm run dev"}); err != nil {
+		t.Fatalf("UpdateProcess() error: %v", err)
+	}
+	if updated.Name != "web" || updated.Command != "npm run dev" {
+		t.Errorf("UpdateProcess() sent %+v", updated)
+	}
+}
+
+func TestClientProjectEndpoints(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /graph", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"nodes": {"web": {"name": "web", "is_running": true, "depends_on": {"db": {"name": "db", "depends_on": {}}}}}}`))
+	})
+	mux.HandleFunc("GET /project/state", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"fileNames": ["process-compose.yaml"], "upTime": 90000000000, "processNum": 2, "runningProcessNum": 1, "version": "v1.40.1", "projectName": "api"}`))
+	})
+	var gotProcesses map[string]ProcessConfig
+	mux.HandleFunc("POST /project", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Processes map[string]ProcessConfig `json:"processes"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotProcesses = body.Processes
+		w.Write([]byte(`{"web": "updated", "worker": "removed"}`))
+	})
+	mux.HandleFunc("POST /project/configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"cache": "added"}`))
+	})
+	mux.HandleFunc("GET /hostname", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "devbox"}`))
+	})
+
+	client := serveAPI(t, "/tmp/devdash-test-project-api.sock", mux)
+
+	graph, err := client.GetDependencyGraph()
+	if err != nil {
+		t.Fatalf("GetDependencyGraph() error: %v", err)
+	}
+	web := graph.Nodes["web"]
+	if web == nil || !web.IsRunning || web.DependsOn["db"] == nil || web.DependsOn["db"].Name != "db" {
+		t.Errorf("unexpected graph: %+v", graph)
+	}
+
+	state, err := client.GetProjectState()
+	if err != nil {
+		t.Fatalf("GetProjectState() error: %v", err)
+	}
+	if state.UpTime != 90*time.Second || state.RunningProcessNum != 1 || state.ProjectName != "api" {
+		t.Errorf("unexpected project state: %+v", state)
+	}
+
+	changes, err := client.UpdateProject(map[string]ProcessConfig{"web": {Name: "web", Command: "serve"}})
+	if err != nil {
+		t.Fatalf("UpdateProject() error: %v", err)
+	}
+	if changes["web"] != ProcessUpdated || changes["worker"] != ProcessRemoved {
+		t.Errorf("unexpected update status: %v", changes)
+	}
+	if gotProcesses["web"].Command != "serve" {
+		t.Errorf("UpdateProject() sent %+v", gotProcesses)
+	}
+
+	changes, err = client.ReloadProject()
+	if err != nil || changes["cache"] != ProcessAdded {
+		t.Errorf("ReloadProject() = %v, %v", changes, err)
+	}
+
+	if host, err := client.GetHostname(); err != nil || host != "devbox" {
+		t.Errorf("GetHostname() = %q, %v", host, err)
+	}
+	if version, err := client.GetVersion(); err != nil || version != "v1.40.1" {
+		t.Errorf("GetVersion() = %q, %v", version, err)
+	}
+}
+
+func TestClientEndpointErrorStatus(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no such process", http.StatusBadRequest)
+	})
+	client := serveAPI(t, "/tmp/devdash-test-api-error.sock", mux)
+
+	if _, err := client.GetProcessInfo("missing"); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected status error, got %v", err)
+	}
+	if err := client.ScaleProcess("missing", 2); err == nil {
+		t.Error("expected ScaleProcess() to fail")
+	}
+}
+
+func TestClientAgainstFakeServer(t *testing.T) {
+	srv, err := composetest.New("/tmp/devdash-test-fake-server.sock")
+	if err != nil {
+		t.Fatalf("composetest.New() error: %v", err)
+	}
+	defer srv.Close()
+	srv.AddProcess("web", "db")
+	srv.AddProcess("db")
+	srv.SetPorts("web", 8080)
+
+	client := NewClient(srv.SocketPath())
+	if err := client.StopProcess("web"); err != nil {
+		t.Fatalf("StopProcess() error: %v", err)
+	}
+	status, err := client.GetStatus()
+	if err != nil {
+		t.Fatalf("GetStatus() error: %v", err)
+	}
+	if len(status.Processes) != 2 || status.Processes[1].Name != "web" || status.Processes[1].IsRunning {
+		t.Errorf("web should be stopped: %+v", status.Processes)
+	}
+
+	info, err := client.GetProcessInfo("web")
+	if err != nil || info.DependsOn["db"].Condition == "" {
+		t.Errorf("GetProcessInfo() = %+v, %v", info, err)
+	}
+	if ports, err := client.GetProcessPorts("web"); err != nil || len(ports.TCPPorts) != 1 {
+		t.Errorf("GetProcessPorts() = %+v, %v", ports, err)
+	}
+	if err := client.RestartProcess("ghost"); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("RestartProcess(ghost) = %v, want ErrProcessNotFound", err)
+	}
+
+	if err := client.ShutdownProject(); err != nil {
+		t.Fatalf("ShutdownProject() error: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, err := client.GetStatus(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("GetStatus() after shutdown = %v, want ErrNotConnected", err)
+	}
+}
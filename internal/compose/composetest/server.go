@@ -0,0 +1,608 @@
+// Package composetest serves a scriptable fake process-compose API on a Unix
+// socket, so the client and UI can be exercised without Nix or real daemons.
+//
+// A Server starts with no processes. Add them with AddProcess, then drive
+// them with Start, Stop, Crash and Log while a client polls the socket.
+// Unknown processes are rejected the way process-compose does, with a 400
+// and a "no such process" error.
+//
+// The package does not import compose so compose's own tests can use it.
+package composetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Version is reported as the process-compose version.
+const Version = "v1.40.1-composetest"
+
+// subscriberBuffer is the number of log lines queued per websocket client
+// before further lines are dropped.
+const subscriberBuffer = 1024
+
+// Process is a snapshot of a fake process.
+type Process struct {
+	Name      string
+	Status    string // Running, Completed or Error
+	Running   bool
+	Pid       int
+	ExitCode  int
+	Restarts  int
+	CPU       float64
+	Mem       int64
+	Replicas  int
+	Started   time.Time
+	DependsOn []string
+	Ports     []int
+	Logs      []string
+}
+
+// Server is a fake process-compose API server.
+type Server struct {
+	socketPath string
+	listener   net.Listener
+	server     *http.Server
+	started    time.Time
+
+	mu        sync.Mutex
+	processes map[string]*Process
+	subs      map[*subscriber]struct{}
+	requests  []string
+	latency   time.Duration
+	nextPid   int
+	name      string
+	closed    bool
+}
+
+// subscriber is a websocket client following the logs of some processes.
+type subscriber struct {
+	names map[string]bool
+	lines chan logMessage
+}
+
+// New starts a server listening on socketPath, replacing any stale socket.
+func New(socketPath string) (*Server, error) {
+	_ = os.Remove(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		socketPath: socketPath,
+		listener:   listener,
+		started:    time.Now(),
+		processes:  make(map[string]*Process),
+		subs:       make(map[*subscriber]struct{}),
+		nextPid:    1000,
+		name:       "composetest",
+	}
+	s.server = &http.Server{Handler: s.routes()}
+	go s.server.Serve(listener)
+	return s, nil
+}
+
+// SocketPath returns the socket the server listens on.
+func (s *Server) SocketPath() string {
+	return s.socketPath
+}
+
+// Close stops the server, disconnects log followers and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for sub := range s.subs {
+		close(sub.lines)
+		delete(s.subs, sub)
+	}
+	s.mu.Unlock()
+
+	err := s.server.Close()
+	os.Remove(s.socketPath)
+	return err
+}
+
+// SetProjectName sets the name reported by /project/state.
+func (s *Server) SetProjectName(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.name = name
+}
+
+// SetLatency delays every response by d.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// AddProcess adds a running process.
+func (s *Server) AddProcess(name string, dependsOn ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := &Process{Name: name, Replicas: 1, DependsOn: dependsOn}
+	s.processes[name] = p
+	s.run(p)
+}
+
+// run marks a process as running with a fresh pid. Callers hold s.mu.
+func (s *Server) run(p *Process) {
+	s.nextPid++
+	p.Status = "Running"
+	p.Running = true
+	p.Pid = s.nextPid
+	p.ExitCode = 0
+	p.Started = time.Now()
+}
+
+// halt marks a process as exited. Callers hold s.mu.
+func halt(p *Process, exitCode int) {
+	p.Status = "Completed"
+	if exitCode != 0 {
+		p.Status = "Error"
+	}
+	p.Running = false
+	p.Pid = 0
+	p.ExitCode = exitCode
+	p.CPU = 0
+	p.Mem = 0
+}
+
+// update applies fn to a process, failing if it doesn't exist.
+func (s *Server) update(name string, fn func(p *Process)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.processes[name]
+	if !ok {
+		return fmt.Errorf("no such process: %s", name)
+	}
+	fn(p)
+	return nil
+}
+
+// Start runs a stopped process.
+func (s *Server) Start(name string) error {
+	return s.update(name, func(p *Process) {
+		if !p.Running {
+			s.run(p)
+		}
+	})
+}
+
+// Stop stops a process cleanly.
+func (s *Server) Stop(name string) error {
+	return s.update(name, func(p *Process) { halt(p, 0) })
+}
+
+// Crash makes a process exit with exitCode.
+func (s *Server) Crash(name string, exitCode int) error {
+	return s.update(name, func(p *Process) { halt(p, exitCode) })
+}
+
+// Restart restarts a process, counting the restart.
+func (s *Server) Restart(name string) error {
+	return s.update(name, func(p *Process) {
+		p.Restarts++
+		s.run(p)
+	})
+}
+
+// SetUsage sets the CPU percentage and memory bytes reported for a process.
+func (s *Server) SetUsage(name string, cpu float64, mem int64) error {
+	return s.update(name, func(p *Process) {
+		p.CPU = cpu
+		p.Mem = mem
+	})
+}
+
+// SetPorts sets the TCP ports a process reports listening on.
+func (s *Server) SetPorts(name string, ports ...int) error {
+	return s.update(name, func(p *Process) { p.Ports = ports })
+}
+
+// Log appends lines to a process's log and sends them to log followers.
+func (s *Server) Log(name string, lines ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.processes[name]
+	if !ok {
+		return fmt.Errorf("no such process: %s", name)
+	}
+	p.Logs = append(p.Logs, lines...)
+	for sub := range s.subs {
+		if !sub.names[name] {
+			continue
+		}
+		for _, line := range lines {
+			select {
+			case sub.lines <- logMessage{Message: line, ProcessName: name}:
+			default:
+				// Slow follower; drop rather than block the script
+			}
+		}
+	}
+	return nil
+}
+
+// Process returns a snapshot of a process.
+func (s *Server) Process(name string) (Process, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.processes[name]
+	if !ok {
+		return Process{}, false
+	}
+	snapshot := *p
+	snapshot.DependsOn = append([]string(nil), p.DependsOn...)
+	snapshot.Ports = append([]int(nil), p.Ports...)
+	snapshot.Logs = append([]string(nil), p.Logs...)
+	return snapshot, true
+}
+
+// Requests returns the requests served so far as "METHOD /path".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// names returns the process names in order. Callers hold s.mu.
+func (s *Server) names() []string {
+	names := make([]string, 0, len(s.processes))
+	for name := range s.processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Wire formats, mirroring the process-compose API
+
+type processState struct {
+	Name       string  `json:"name"`
+	Namespace  string  `json:"namespace"`
+	Status     string  `json:"status"`
+	IsRunning  bool    `json:"is_running"`
+	Pid        int     `json:"pid"`
+	ExitCode   int     `json:"exit_code"`
+	SystemTime string  `json:"system_time"`
+	Restarts   int     `json:"restarts"`
+	Mem        int64   `json:"mem"`
+	CPU        float64 `json:"cpu"`
+}
+
+type dependencyNode struct {
+	processState
+	DependsOn map[string]*dependencyNode `json:"depends_on"`
+}
+
+type logMessage struct {
+	Message     string `json:"message"`
+	ProcessName string `json:"process_name"`
+}
+
+// state converts a process to its API representation. Callers hold s.mu.
+func state(p *Process) processState {
+	st := processState{
+		Name:      p.Name,
+		Namespace: "default",
+		Status:    p.Status,
+		IsRunning: p.Running,
+		Pid:       p.Pid,
+		ExitCode:  p.ExitCode,
+		Restarts:  p.Restarts,
+		Mem:       p.Mem,
+		CPU:       p.CPU,
+	}
+	if p.Running {
+		st.SystemTime = time.Since(p.Started).Round(time.Second).String()
+	}
+	return st
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+// routes builds the API handler.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /processes", s.handleProcesses)
+	mux.HandleFunc("/process/", s.handleProcess)
+	mux.HandleFunc("GET /process/logs/ws", s.handleLogStream)
+	mux.HandleFunc("GET /graph", s.handleGraph)
+	mux.HandleFunc("GET /project/state", s.handleProjectState)
+	mux.HandleFunc("POST /project/stop", s.handleShutdown)
+	mux.HandleFunc("POST /project/configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{})
+	})
+	mux.HandleFunc("GET /hostname", func(w http.ResponseWriter, r *http.Request) {
+		host, _ := os.Hostname()
+		writeJSON(w, map[string]string{"name": host})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		latency := s.latency
+		s.mu.Unlock()
+
+		if latency > 0 {
+			select {
+			case <-time.After(latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleProcesses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data := make([]processState, 0, len(s.processes))
+	for _, name := range s.names() {
+		data = append(data, state(s.processes[name]))
+	}
+	s.mu.Unlock()
+	writeJSON(w, map[string]any{"data": data})
+}
+
+// handleProcess serves the /process/... endpoints, which overlap too much
+// for ServeMux patterns.
+func (s *Server) handleProcess(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/process/"), "/")
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.withProcess(w, parts[0], func(p *Process) any { return state(p) })
+	case len(parts) == 2 && parts[0] == "info" && r.Method == http.MethodGet:
+		s.withProcess(w, parts[1], func(p *Process) any {
+			deps := make(map[string]map[string]string, len(p.DependsOn))
+			for _, dep := range p.DependsOn {
+				deps[dep] = map[string]string{"condition": "process_started"}
+			}
+			return map[string]any{
+				"name":       p.Name,
+				"namespace":  "default",
+				"command":    "composetest " + p.Name,
+				"depends_on": deps,
+				"replicas":   p.Replicas,
+			}
+		})
+	case len(parts) == 2 && parts[0] == "ports" && r.Method == http.MethodGet:
+		s.withProcess(w, parts[1], func(p *Process) any {
+			return map[string]any{"name": p.Name, "tcp_ports": p.Ports, "udp_ports": []int{}}
+		})
+	case len(parts) == 3 && parts[0] == "scale" && r.Method == http.MethodPatch:
+		replicas, err := strconv.Atoi(parts[2])
+		if err != nil || replicas < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid scale %q", parts[2]))
+			return
+		}
+		s.withProcess(w, parts[1], func(p *Process) any {
+			p.Replicas = replicas
+			return map[string]string{"name": p.Name}
+		})
+	case len(parts) == 4 && parts[0] == "logs" && r.Method == http.MethodGet:
+		offset, _ := strconv.Atoi(parts[2])
+		limit, _ := strconv.Atoi(parts[3])
+		s.withProcess(w, parts[1], func(p *Process) any {
+			end := len(p.Logs) - offset
+			if end < 0 {
+				end = 0
+			}
+			start := end - limit
+			if start < 0 {
+				start = 0
+			}
+			return map[string]any{"logs": append([]string{}, p.Logs[start:end]...)}
+		})
+	case len(parts) == 2 && r.Method == http.MethodPost:
+		var action func(string) error
+		switch parts[1] {
+		case "start":
+			action = s.Start
+		case "stop":
+			action = s.Stop
+		case "restart":
+			action = s.Restart
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err := action(parts[0]); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, map[string]string{"name": parts[0]})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// withProcess writes fn's result for a process, or a not-found error.
+func (s *Server) withProcess(w http.ResponseWriter, name string, fn func(p *Process) any) {
+	s.mu.Lock()
+	p, ok := s.processes[name]
+	var v any
+	if ok {
+		v = fn(p)
+	}
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("no such process: %s", name))
+		return
+	}
+	writeJSON(w, v)
+}
+
+func (s *Server) handleGraph(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var build func(name string, seen map[string]bool) *dependencyNode
+	build = func(name string, seen map[string]bool) *dependencyNode {
+		p, ok := s.processes[name]
+		if !ok || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		defer delete(seen, name)
+		node := &dependencyNode{processState: state(p), DependsOn: map[string]*dependencyNode{}}
+		for _, dep := range p.DependsOn {
+			if child := build(dep, seen); child != nil {
+				node.DependsOn[dep] = child
+			}
+		}
+		return node
+	}
+
+	// Roots are processes nothing else depends on
+	dependedOn := make(map[string]bool)
+	for _, p := range s.processes {
+		for _, dep := range p.DependsOn {
+			dependedOn[dep] = true
+		}
+	}
+	nodes := make(map[string]*dependencyNode)
+	for _, name := range s.names() {
+		if !dependedOn[name] {
+			nodes[name] = build(name, map[string]bool{})
+		}
+	}
+	writeJSON(w, map[string]any{"nodes": nodes})
+}
+
+func (s *Server) handleProjectState(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	running := 0
+	for _, p := range s.processes {
+		if p.Running {
+			running++
+		}
+	}
+	host, _ := os.Hostname()
+	resp := map[string]any{
+		"fileNames":         []string{"process-compose.yaml"},
+		"upTime":            time.Since(s.started),
+		"startTime":         s.started,
+		"processNum":        len(s.processes),
+		"runningProcessNum": running,
+		"userName":          os.Getenv("USER"),
+		"hostName":          host,
+		"version":           Version,
+		"projectName":       s.name,
+	}
+	s.mu.Unlock()
+	writeJSON(w, resp)
+}
+
+// handleShutdown stops every process and, like process-compose, exits: the
+// socket goes away once the response is sent.
+func (s *Server) handleShutdown(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	for _, p := range s.processes {
+		halt(p, 0)
+	}
+	s.mu.Unlock()
+	writeJSON(w, map[string]string{"status": "stopped"})
+	go s.Close()
+}
+
+var upgrader = websocket.Upgrader{}
+
+func (s *Server) handleLogStream(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	offset, _ := strconv.Atoi(query.Get("offset"))
+	sub := &subscriber{names: make(map[string]bool), lines: make(chan logMessage, subscriberBuffer)}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	var replay []logMessage
+	for _, name := range strings.Split(query.Get("name"), ",") {
+		p, ok := s.processes[name]
+		if !ok {
+			s.mu.Unlock()
+			writeError(w, http.StatusBadRequest, fmt.Errorf("no such process: %s", name))
+			return
+		}
+		sub.names[name] = true
+		start := len(p.Logs) - offset
+		if start < 0 {
+			start = 0
+		}
+		for _, line := range p.Logs[start:] {
+			replay = append(replay, logMessage{Message: line, ProcessName: name})
+		}
+	}
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, sub)
+		s.mu.Unlock()
+	}()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	// Notice the client going away
+	gone := make(chan struct{})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for _, msg := range replay {
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+	for {
+		select {
+		case msg, ok := <-sub.lines:
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-gone:
+			return
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package composetest
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func newServer(t *testing.T, name string) *Server {
+	t.Helper()
+	s, err := New("/tmp/devdash-composetest-" + name + ".sock")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// get requests path over the server's socket and decodes the JSON response.
+func get(t *testing.T, s *Server, method, path string, out any) int {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return net.Dial("unix", s.SocketPath())
+		},
+	}}
+	req, _ := http.NewRequest(method, "http://unix"+path, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		json.NewDecoder(resp.Body).Decode(out)
+	}
+	return resp.StatusCode
+}
+
+func TestServerProcessLifecycle(t *testing.T) {
+	s := newServer(t, "lifecycle")
+	s.AddProcess("web", "db")
+	s.AddProcess("db")
+
+	var list struct {
+		Data []processState `json:"data"`
+	}
+	get(t, s, "GET", "/processes", &list)
+	if len(list.Data) != 2 || list.Data[0].Name != "db" || !list.Data[1].IsRunning {
+		t.Fatalf("unexpected processes: %+v", list.Data)
+	}
+
+	s.Crash("web", 137)
+	var st processState
+	get(t, s, "GET", "/process/web", &st)
+	if st.IsRunning || st.ExitCode != 137 || st.Status != "Error" {
+		t.Errorf("crashed process = %+v", st)
+	}
+
+	if code := get(t, s, "POST", "/process/web/restart", nil); code != http.StatusOK {
+		t.Fatalf("restart status = %d", code)
+	}
+	p, _ := s.Process("web")
+	if !p.Running || p.Restarts != 1 {
+		t.Errorf("restarted process = %+v", p)
+	}
+
+	var errResp map[string]string
+	if code := get(t, s, "POST", "/process/ghost/start", &errResp); code != http.StatusBadRequest {
+		t.Errorf("unknown process status = %d", code)
+	}
+	if !strings.Contains(errResp["error"], "no such process") {
+		t.Errorf("unexpected error body: %v", errResp)
+	}
+}
+
+func TestServerGraph(t *testing.T) {
+	s := newServer(t, "graph")
+	s.AddProcess("web", "api")
+	s.AddProcess("api", "db")
+	s.AddProcess("db")
+
+	var graph struct {
+		Nodes map[string]*dependencyNode `json:"nodes"`
+	}
+	get(t, s, "GET", "/graph", &graph)
+	if len(graph.Nodes) != 1 || graph.Nodes["web"] == nil {
+		t.Fatalf("expected web as the only root, got %v", graph.Nodes)
+	}
+	if graph.Nodes["web"].DependsOn["api"].DependsOn["db"] == nil {
+		t.Error("graph should nest web -> api -> db")
+	}
+}
+
+func TestServerLatency(t *testing.T) {
+	s := newServer(t, "latency")
+	s.SetLatency(100 * time.Millisecond)
+
+	start := time.Now()
+	get(t, s, "GET", "/processes", nil)
+	if time.Since(start) < 100*time.Millisecond {
+		t.Error("response should be delayed by the configured latency")
+	}
+	if reqs := s.Requests(); len(reqs) != 1 || reqs[0] != "GET /processes" {
+		t.Errorf("Requests() = %v", reqs)
+	}
+}
+
+func TestServerLogStream(t *testing.T) {
+	s := newServer(t, "logs")
+	s.AddProcess("web")
+	s.Log("web", "old 1", "old 2", "old 3")
+
+	dialer := websocket.Dialer{
+		NetDial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", s.SocketPath())
+		},
+	}
+	conn, _, err := dialer.Dial("ws://unix/process/logs/ws?name=web&offset=2&follow=true", nil)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+
+	s.Log("web", "new")
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for _, want := range []string{"old 2", "old 3", "new"} {
+		var msg logMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("read error waiting for %q: %v", want, err)
+		}
+		if msg.Message != want || msg.ProcessName != "web" {
+			t.Errorf("got %+v, want %q", msg, want)
+		}
+	}
+}
+
+func TestServerShutdownRemovesSocket(t *testing.T) {
+	s := newServer(t, "shutdown")
+	s.AddProcess("web")
+
+	get(t, s, "POST", "/project/stop", nil)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(s.SocketPath()); os.IsNotExist(err) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("socket should be removed after shutdown")
+}
@@ -0,0 +1,93 @@
+package compose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotConnected is returned when the process-compose socket can't be
+	// reached, e.g. because the project was stopped.
+	ErrNotConnected = errors.New("process-compose socket not reachable")
+
+	// ErrProcessNotFound is returned when the API reports an unknown process.
+	ErrProcessNotFound = errors.New("process not found")
+
+	// ErrAPIError matches any *APIError.
+	ErrAPIError = errors.New("process-compose API error")
+)
+
+// maxErrorBody bounds how much of an error response is kept.
+const maxErrorBody = 4096
+
+// APIError is a non-OK response from the process-compose API.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string // Error reported in the response body, if any
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%s %s: unexpected status: %d", e.Method, e.Path, e.StatusCode)
+	}
+	return fmt.Sprintf("%s %s: %s (status %d)", e.Method, e.Path, e.Message, e.StatusCode)
+}
+
+// Is matches ErrAPIError, and ErrProcessNotFound when the API rejected the
+// request because the process doesn't exist. process-compose reports unknown
+// processes with a "no such process" message, or a 404 from a per-process
+// endpoint. A 404 elsewhere is a missing route, not a missing process.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrAPIError:
+		return true
+	case ErrProcessNotFound:
+		if e.StatusCode == http.StatusNotFound && strings.HasPrefix(e.Path, "/process/") {
+			return true
+		}
+		return strings.Contains(strings.ToLower(e.Message), "no such process")
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a response, reading the message from
+// the body's "error" field or, failing that, the body text.
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	var payload struct {
+		Error string `json:"error"`
+	}
+	message := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		message = payload.Error
+	}
+	return &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Message:    message,
+	}
+}
+
+// connectError is a failure to reach the socket. It matches ErrNotConnected.
+type connectError struct {
+	err error
+}
+
+func (e *connectError) Error() string {
+	return ErrNotConnected.Error() + ": " + e.err.Error()
+}
+
+func (e *connectError) Is(target error) bool {
+	return target == ErrNotConnected
+}
+
+func (e *connectError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,154 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAPIErrorFromResponseBody(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/process/info/ghost", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "no such process: ghost"}`))
+	})
+	mux.HandleFunc("/project/configuration", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "reload failed", http.StatusInternalServerError)
+	})
+	client := serveAPI(t, "/tmp/devdash-test-api-errors.sock", mux)
+
+	_, err := client.GetProcessInfo("ghost")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "no such process: ghost" {
+		t.Errorf("unexpected API error: %+v", apiErr)
+	}
+	if !errors.Is(err, ErrProcessNotFound) || !errors.Is(err, ErrAPIError) {
+		t.Errorf("error should match ErrProcessNotFound and ErrAPIError: %v", err)
+	}
+
+	_, err = client.ReloadProject()
+	if !errors.As(err, &apiErr) || apiErr.Message != "reload failed" {
+		t.Errorf("expected body text as message, got %v", err)
+	}
+	if errors.Is(err, ErrProcessNotFound) {
+		t.Error("server error should not match ErrProcessNotFound")
+	}
+}
+
+func TestNotFoundStatusMatchesProcessNotFound(t *testing.T) {
+	err := &APIError{Method: "GET", Path: "/process/x", StatusCode: http.StatusNotFound}
+	if !errors.Is(err, ErrProcessNotFound) {
+		t.Error("404 should match ErrProcessNotFound")
+	}
+	if err.Error() != "GET /process/x: unexpected status: 404" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	for _, err := range []*APIError{
+		{Method: "POST", Path: "/project/configuration", StatusCode: http.StatusNotFound},
+		{Method: "GET", Path: "/processes", StatusCode: http.StatusNotFound, Message: "404 page not found"},
+		{Method: "GET", Path: "/graph", StatusCode: http.StatusBadRequest, Message: "project not found"},
+	} {
+		if errors.Is(err, ErrProcessNotFound) {
+			t.Errorf("%v should not match ErrProcessNotFound", err)
+		}
+	}
+}
+
+func TestClientNotConnected(t *testing.T) {
+	client := NewClient("/nonexistent/socket.sock")
+
+	if err := client.Connect(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("Connect() = %v, want ErrNotConnected", err)
+	}
+	if _, err := client.GetStatus(); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("GetStatus() = %v, want ErrNotConnected", err)
+	}
+	if err := client.StartProcess("web"); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("StartProcess() = %v, want ErrNotConnected", err)
+	}
+	if _, err := client.StreamLogs(context.Background(), []string{"web"}, 0); !errors.Is(err, ErrNotConnected) {
+		t.Errorf("StreamLogs() = %v, want ErrNotConnected", err)
+	}
+}
+
+func TestClientRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"data": [{"name": "web"}]}`))
+	})
+	mux.HandleFunc("/process/web/restart", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	})
+	client := serveAPI(t, "/tmp/devdash-test-api-retry.sock", mux)
+
+	if _, err := client.GetStatus(); err == nil {
+		t.Fatal("GetStatus() should fail without retries")
+	}
+
+	calls.Store(0)
+	client.SetRetries(2, time.Millisecond)
+	status, err := client.GetStatus()
+	if err != nil || len(status.Processes) != 1 {
+		t.Fatalf("GetStatus() with retries = %+v, %v", status, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", calls.Load())
+	}
+
+	// Non-idempotent requests that reached the server are not retried
+	calls.Store(0)
+	if err := client.RestartProcess("web"); err == nil {
+		t.Fatal("RestartProcess() should fail")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("restart should not be retried, got %d attempts", calls.Load())
+	}
+}
+
+func TestClientContextCancellation(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	client := serveAPI(t, "/tmp/devdash-test-api-cancel.sock", mux)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetStatusContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetStatusContext() = %v, want deadline exceeded", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("request should stop when the context expires")
+	}
+}
+
+func TestClientSetTimeout(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	client := serveAPI(t, "/tmp/devdash-test-api-timeout.sock", mux)
+	client.SetTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := client.GetStatus(); err == nil {
+		t.Fatal("GetStatus() should time out")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("request should respect the configured timeout")
+	}
+}
@@ -0,0 +1,154 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Processes returns every process in the graph by name.
+func (g *DependencyGraph) Processes() map[string]*DependencyNode {
+	all := make(map[string]*DependencyNode)
+	var walk func(nodes map[string]*DependencyNode)
+	walk = func(nodes map[string]*DependencyNode) {
+		for name, node := range nodes {
+			if node == nil {
+				continue
+			}
+			if _, seen := all[name]; seen {
+				continue
+			}
+			all[name] = node
+			walk(node.DependsOn)
+		}
+	}
+	walk(g.Nodes)
+	return all
+}
+
+// Dependencies returns the direct dependencies of every process, sorted by
+// name. Processes without dependencies map to an empty slice.
+func (g *DependencyGraph) Dependencies() map[string][]string {
+	deps := make(map[string][]string)
+	for name, node := range g.Processes() {
+		list := make([]string, 0, len(node.DependsOn))
+		for dep := range node.DependsOn {
+			list = append(list, dep)
+		}
+		sort.Strings(list)
+		deps[name] = list
+	}
+	return deps
+}
+
+// Dependents returns the processes that directly depend on each process,
+// sorted by name.
+func (g *DependencyGraph) Dependents() map[string][]string {
+	dependents := make(map[string][]string)
+	for name, deps := range g.Dependencies() {
+		if _, ok := dependents[name]; !ok {
+			dependents[name] = nil
+		}
+		for _, dep := range deps {
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+	for _, list := range dependents {
+		sort.Strings(list)
+	}
+	return dependents
+}
+
+// StartOrder returns name and everything it transitively depends on, each
+// after its dependencies.
+func (g *DependencyGraph) StartOrder(name string) []string {
+	return postOrder(g.Dependencies(), name)
+}
+
+// StopOrder returns name and everything that transitively depends on it,
+// each after its dependents.
+func (g *DependencyGraph) StopOrder(name string) []string {
+	return postOrder(g.Dependents(), name)
+}
+
+// postOrder walks edges depth-first from name and lists every process after
+// the processes it has edges to. Cycles are broken at the first revisit.
+func postOrder(edges map[string][]string, name string) []string {
+	var order []string
+	visited := make(map[string]bool)
+	var visit func(n string)
+	visit = func(n string) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+		for _, next := range edges[n] {
+			visit(next)
+		}
+		order = append(order, n)
+	}
+	visit(name)
+	return order
+}
+
+// StartWithDependencies starts a process after starting, dependencies first,
+// everything it transitively depends on that isn't running. It returns the
+// processes it started.
+func (c *Client) StartWithDependencies(name string) ([]string, error) {
+	return c.StartWithDependenciesContext(context.Background(), name)
+}
+
+// StartWithDependenciesContext is like StartWithDependencies but honors ctx.
+func (c *Client) StartWithDependenciesContext(ctx context.Context, name string) ([]string, error) {
+	graph, err := c.GetDependencyGraphContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	procs := graph.Processes()
+	if _, ok := procs[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProcessNotFound, name)
+	}
+
+	var started []string
+	for _, proc := range graph.StartOrder(name) {
+		if procs[proc].IsRunning {
+			continue
+		}
+		if err := c.StartProcessContext(ctx, proc); err != nil {
+			return started, fmt.Errorf("starting %s: %w", proc, err)
+		}
+		started = append(started, proc)
+	}
+	return started, nil
+}
+
+// StopWithDependents stops a process after stopping, dependents first,
+// everything running that transitively depends on it. It returns the
+// processes it stopped.
+func (c *Client) StopWithDependents(name string) ([]string, error) {
+	return c.StopWithDependentsContext(context.Background(), name)
+}
+
+// StopWithDependentsContext is like StopWithDependents but honors ctx.
+func (c *Client) StopWithDependentsContext(ctx context.Context, name string) ([]string, error) {
+	graph, err := c.GetDependencyGraphContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	procs := graph.Processes()
+	if _, ok := procs[name]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProcessNotFound, name)
+	}
+
+	var stopped []string
+	for _, proc := range graph.StopOrder(name) {
+		if !procs[proc].IsRunning {
+			continue
+		}
+		if err := c.StopProcessContext(ctx, proc); err != nil {
+			return stopped, fmt.Errorf("stopping %s: %w", proc, err)
+		}
+		stopped = append(stopped, proc)
+	}
+	return stopped, nil
+}
@@ -0,0 +1,101 @@
+package compose
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/infktd/devdash/internal/compose/composetest"
+)
+
+func node(name string, running bool, deps ...*DependencyNode) *DependencyNode {
+	n := &DependencyNode{ProcessStatus: ProcessStatus{Name: name, IsRunning: running}, DependsOn: map[string]*DependencyNode{}}
+	for _, d := range deps {
+		n.DependsOn[d.Name] = d
+	}
+	return n
+}
+
+// testGraph is web -> api -> {postgres, redis}, worker -> redis.
+func testGraph() *DependencyGraph {
+	postgres := node("postgres", true)
+	redis := node("redis", true)
+	api := node("api", true, postgres, redis)
+	return &DependencyGraph{Nodes: map[string]*DependencyNode{
+		"web":    node("web", true, api),
+		"worker": node("worker", true, redis),
+	}}
+}
+
+func TestDependencyGraphEdges(t *testing.T) {
+	g := testGraph()
+
+	if got := len(g.Processes()); got != 5 {
+		t.Errorf("Processes() has %d entries, want 5", got)
+	}
+	if got := g.Dependencies()["api"]; !reflect.DeepEqual(got, []string{"postgres", "redis"}) {
+		t.Errorf("Dependencies()[api] = %v", got)
+	}
+	if got := g.Dependents()["redis"]; !reflect.DeepEqual(got, []string{"api", "worker"}) {
+		t.Errorf("Dependents()[redis] = %v", got)
+	}
+	if got := g.Dependents()["web"]; len(got) != 0 {
+		t.Errorf("Dependents()[web] = %v, want none", got)
+	}
+}
+
+func TestDependencyGraphOrders(t *testing.T) {
+	g := testGraph()
+
+	if got := g.StartOrder("web"); !reflect.DeepEqual(got, []string{"postgres", "redis", "api", "web"}) {
+		t.Errorf("StartOrder(web) = %v", got)
+	}
+	if got := g.StopOrder("redis"); !reflect.DeepEqual(got, []string{"web", "api", "worker", "redis"}) {
+		t.Errorf("StopOrder(redis) = %v", got)
+	}
+	if got := g.StartOrder("postgres"); !reflect.DeepEqual(got, []string{"postgres"}) {
+		t.Errorf("StartOrder(postgres) = %v", got)
+	}
+}
+
+func TestPostOrderBreaksCycles(t *testing.T) {
+	edges := map[string][]string{"a": {"b"}, "b": {"a"}}
+	if got := postOrder(edges, "a"); !reflect.DeepEqual(got, []string{"b", "a"}) {
+		t.Errorf("postOrder() = %v", got)
+	}
+}
+
+func TestStartWithDependenciesAndStopWithDependents(t *testing.T) {
+	srv, err := composetest.New("/tmp/devdash-test-graph.sock")
+	if err != nil {
+		t.Fatalf("composetest.New() error: %v", err)
+	}
+	defer srv.Close()
+	srv.AddProcess("web", "api")
+	srv.AddProcess("api", "db")
+	srv.AddProcess("db")
+	client := NewClient(srv.SocketPath())
+
+	stopped, err := client.StopWithDependents("db")
+	if err != nil {
+		t.Fatalf("StopWithDependents() error: %v", err)
+	}
+	if !reflect.DeepEqual(stopped, []string{"web", "api", "db"}) {
+		t.Errorf("stopped %v, want dependents first", stopped)
+	}
+
+	started, err := client.StartWithDependencies("api")
+	if err != nil {
+		t.Fatalf("StartWithDependencies() error: %v", err)
+	}
+	if !reflect.DeepEqual(started, []string{"db", "api"}) {
+		t.Errorf("started %v, want dependencies first", started)
+	}
+	if p, _ := srv.Process("web"); p.Running {
+		t.Error("web doesn't depend on api and should stay stopped")
+	}
+
+	if _, err := client.StartWithDependencies("ghost"); !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("StartWithDependencies(ghost) = %v, want ErrProcessNotFound", err)
+	}
+}
@@ -0,0 +1,79 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// logStreamBuffer is the channel capacity for streamed log lines.
+const logStreamBuffer = 256
+
+// StreamLogs follows the logs of the given processes over process-compose's
+// websocket endpoint. offset is the number of existing lines per process to
+// replay before following (0 = only new lines).
+//
+// Lines are delivered on the returned channel until ctx is cancelled or the
+// connection drops, after which the channel is closed.
+func (c *Client) StreamLogs(ctx context.Context, processNames []string, offset int) (<-chan LogLine, error) {
+	dialer := websocket.Dialer{
+		NetDialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return c.dial(ctx)
+		},
+		HandshakeTimeout: 5 * time.Second,
+	}
+
+	query := url.Values{}
+	query.Set("name", strings.Join(processNames, ","))
+	query.Set("offset", strconv.Itoa(offset))
+	query.Set("follow", "true")
+	wsURL := "ws://unix/process/logs/ws?" + query.Encode()
+
+	conn, resp, err := dialer.DialContext(ctx, wsURL, nil)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err != nil {
+		var ce *connectError
+		if errors.As(err, &ce) {
+			return nil, ce
+		}
+		return nil, err
+	}
+
+	lines := make(chan LogLine, logStreamBuffer)
+	done := make(chan struct{})
+
+	// Close the connection on cancellation to unblock ReadJSON
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	go func() {
+		defer close(lines)
+		defer close(done)
+		for {
+			var msg logMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			select {
+			case lines <- LogLine{Service: msg.ProcessName, Message: msg.Message}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return lines, nil
+}
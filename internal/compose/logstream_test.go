@@ -0,0 +1,126 @@
+package compose
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// serveLogStream starts a fake /process/logs/ws endpoint that sends the given
+// messages and then keeps the connection open until the client disconnects.
+func serveLogStream(t *testing.T, socketPath string, messages []logMessage, gotQuery chan<- string) func() {
+	t.Helper()
+	_ = os.Remove(socketPath)
+
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/process/logs/ws", func(w http.ResponseWriter, r *http.Request) {
+		if gotQuery != nil {
+			gotQuery <- r.URL.RawQuery
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for _, msg := range messages {
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+		// Block until the client goes away
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	return func() {
+		server.Close()
+		os.Remove(socketPath)
+	}
+}
+
+func TestClientStreamLogs(t *testing.T) {
+	socketPath := "/tmp/devdash-test-logstream.sock"
+	queries := make(chan string, 1)
+	cleanup := serveLogStream(t, socketPath, []logMessage{
+		{Message: "line 1", ProcessName: "web"},
+		{Message: "line 1", ProcessName: "web"}, // identical lines must not be deduplicated
+		{Message: "line 2", ProcessName: "db"},
+	}, queries)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := NewClient(socketPath)
+	lines, err := client.StreamLogs(ctx, []string{"web", "db"}, 100)
+	if err != nil {
+		t.Fatalf("StreamLogs() error: %v", err)
+	}
+
+	query := <-queries
+	if query != "follow=true&name=web%2Cdb&offset=100" {
+		t.Errorf("unexpected query %q", query)
+	}
+
+	want := []LogLine{
+		{Service: "web", Message: "line 1"},
+		{Service: "web", Message: "line 1"},
+		{Service: "db", Message: "line 2"},
+	}
+	for i, w := range want {
+		select {
+		case got := <-lines:
+			if got != w {
+				t.Errorf("line %d = %+v, want %+v", i, got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for line %d", i)
+		}
+	}
+}
+
+func TestClientStreamLogsClosesOnCancel(t *testing.T) {
+	socketPath := "/tmp/devdash-test-logstream-cancel.sock"
+	cleanup := serveLogStream(t, socketPath, nil, nil)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient(socketPath)
+	lines, err := client.StreamLogs(ctx, []string{"web"}, 0)
+	if err != nil {
+		t.Fatalf("StreamLogs() error: %v", err)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Error("expected channel to be closed after cancel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after cancel")
+	}
+}
+
+func TestClientStreamLogsNoSocket(t *testing.T) {
+	client := NewClient("/nonexistent/socket.sock")
+	if _, err := client.StreamLogs(context.Background(), []string{"web"}, 0); err == nil {
+		t.Fatal("StreamLogs() should fail for nonexistent socket")
+	}
+}
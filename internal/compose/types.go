@@ -1,5 +1,7 @@
 package compose
 
+import "time"
+
 // ProcessStatus represents the status of a process in process-compose.
 type ProcessStatus struct {
 	Name       string  `json:"name"`
@@ -14,6 +16,12 @@ type ProcessStatus struct {
 	CPU        float64 `json:"cpu"`
 }
 
+// Succeeded reports whether the process ran to completion with exit code 0,
+// such as a one-shot setup task.
+func (p ProcessStatus) Succeeded() bool {
+	return !p.IsRunning && p.Status == "Completed" && p.ExitCode == 0
+}
+
 // processesResponse is the API response wrapper for /processes.
 type processesResponse struct {
 	Data []ProcessStatus `json:"data"`
@@ -28,3 +36,94 @@ type ProjectStatus struct {
 type logsResponse struct {
 	Logs []string `json:"logs"`
 }
+
+// LogLine is a single log line delivered by StreamLogs.
+type LogLine struct {
+	Service string
+	Message string
+}
+
+// logMessage is the websocket payload sent by /process/logs/ws.
+type logMessage struct {
+	Message     string `json:"message"`
+	ProcessName string `json:"process_name"`
+}
+
+// ProcessConfig is the configuration of a process as loaded by process-compose.
+type ProcessConfig struct {
+	Name         string                       `json:"name"`
+	Namespace    string                       `json:"namespace"`
+	Description  string                       `json:"description,omitempty"`
+	Command      string                       `json:"command"`
+	WorkingDir   string                       `json:"working_dir,omitempty"`
+	Environment  []string                     `json:"environment,omitempty"`
+	DependsOn    map[string]ProcessDependency `json:"depends_on,omitempty"`
+	Availability ProcessAvailability          `json:"availability"`
+	IsDaemon     bool                         `json:"is_daemon"`
+	Disabled     bool                         `json:"disabled"`
+	Replicas     int                          `json:"replicas"`
+	ReplicaName  string                       `json:"replica_name,omitempty"`
+}
+
+// ProcessDependency is a depends_on entry of a process.
+type ProcessDependency struct {
+	Condition string `json:"condition"` // e.g. process_started, process_healthy
+}
+
+// ProcessAvailability is the restart policy of a process.
+type ProcessAvailability struct {
+	Restart        string `json:"restart,omitempty"`
+	BackoffSeconds int    `json:"backoff_seconds,omitempty"`
+	MaxRestarts    int    `json:"max_restarts,omitempty"`
+	ExitOnEnd      bool   `json:"exit_on_end,omitempty"`
+}
+
+// ProcessPorts lists the ports a process is listening on.
+type ProcessPorts struct {
+	Name     string `json:"name"`
+	TCPPorts []int  `json:"tcp_ports"`
+	UDPPorts []int  `json:"udp_ports"`
+}
+
+// DependencyNode is a process in the dependency graph along with the
+// processes it depends on.
+type DependencyNode struct {
+	ProcessStatus
+	DependsOn map[string]*DependencyNode `json:"depends_on"`
+}
+
+// DependencyGraph is the process dependency graph. Nodes holds the roots:
+// processes no other process depends on.
+type DependencyGraph struct {
+	Nodes map[string]*DependencyNode `json:"nodes"`
+}
+
+// ProjectState describes the running process-compose project.
+type ProjectState struct {
+	FileNames         []string      `json:"fileNames"`
+	UpTime            time.Duration `json:"upTime"`
+	StartTime         time.Time     `json:"startTime"`
+	ProcessNum        int           `json:"processNum"`
+	RunningProcessNum int           `json:"runningProcessNum"`
+	UserName          string        `json:"userName"`
+	HostName          string        `json:"hostName"`
+	Version           string        `json:"version"`
+	ProjectName       string        `json:"projectName"`
+}
+
+// UpdateStatus maps process names to the change an update or reload made
+// to them.
+type UpdateStatus map[string]string
+
+// Values reported in UpdateStatus.
+const (
+	ProcessAdded   = "added"
+	ProcessUpdated = "updated"
+	ProcessRemoved = "removed"
+	ProcessError   = "error"
+)
+
+// hostnameResponse is the API response for /hostname.
+type hostnameResponse struct {
+	Name string `json:"name"`
+}
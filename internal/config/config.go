@@ -2,10 +2,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/infktd/devdash/internal/fsutil"
+	"github.com/infktd/devdash/internal/schema"
 )
 
 const (
@@ -23,7 +27,8 @@ func Path() string {
 	return filepath.Join(configHome, configDir, configFile)
 }
 
-// Load reads config from path, creating default if missing.
+// Load reads config from path, creating default if missing. Files from
+// older versions are upgraded in place first.
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
@@ -37,24 +42,78 @@ func Load(path string) (*Config, error) {
 		return nil, err
 	}
 
+	data, upgrade, err := schema.Upgrade(path, data, migrations)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// A second parse is a deep copy that in-place edits don't reach
+	cfg.base, _ = Parse(data)
+	if upgrade != nil {
+		cfg.warnings = append(cfg.warnings, upgrade.String())
+	}
+	// Other warnings, such as missing scan paths, are left to config check
+	for _, k := range cfg.unknown {
+		cfg.warnings = append(cfg.warnings, unknownKeyProblem(k).String())
+	}
+	return cfg, nil
+}
+
+// Parse decodes a config file on top of the defaults, recording where each
+// value is for Check. Unlike Load, it doesn't validate, upgrade or write.
+func Parse(data []byte) (*Config, error) {
 	cfg := Default() // Start with defaults
 	if err := yaml.Unmarshal(data, cfg); err != nil {
 		return nil, err
 	}
+	cfg.positions = schema.Positions(data)
+	cfg.unknown = schema.UnknownKeys(data, cfg)
 	return cfg, nil
 }
 
-// Save writes config to path, creating directories as needed.
+// Save writes config to path atomically, creating directories as needed.
+// Concurrent saves from other instances are serialized with a file lock,
+// and for a config that came from Load only the fields changed since are
+// written over the file's current contents. cfg itself is not modified.
 func Save(path string, cfg *Config) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	lock, err := fsutil.Lock(path)
+	if err != nil {
 		return err
 	}
+	defer lock.Unlock()
 
-	data, err := yaml.Marshal(cfg)
+	stamped := *cfg
+	if cfg.base != nil {
+		if disk := readCurrent(path); disk != nil {
+			merge(cfg.base, cfg, disk)
+			stamped = *disk
+		}
+	}
+	stamped.Version = SchemaVersion
+	data, err := yaml.Marshal(&stamped)
 	if err != nil {
 		return err
 	}
+	return fsutil.WriteFile(path, data, 0644)
+}
 
-	return os.WriteFile(path, data, 0644)
+// readCurrent parses the config file at path for merging, or returns nil
+// if it is missing, unreadable or not at the current schema version.
+func readCurrent(path string) *Config {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	disk, err := Parse(data)
+	if err != nil || disk.Version != SchemaVersion {
+		return nil
+	}
+	return disk
 }
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -45,6 +47,45 @@ func TestSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestSaveKeepsOtherInstancesChanges(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := Save(configPath, Default()); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	mine, _ := Load(configPath)
+	other, _ := Load(configPath)
+
+	other.UI.Theme = "nord"
+	other.Projects.ScanPaths = []string{"/src"}
+	if err := Save(configPath, other); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	mine.Polling.FocusedProject = 7
+	mine.Projects.ScanPaths = []string{"/work"}
+	if err := Save(configPath, mine); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if loaded.UI.Theme != "nord" {
+		t.Errorf("theme = %q, another instance's change should survive", loaded.UI.Theme)
+	}
+	if loaded.Polling.FocusedProject != 7 {
+		t.Errorf("focused polling = %d, want 7", loaded.Polling.FocusedProject)
+	}
+	if len(loaded.Projects.ScanPaths) != 1 || loaded.Projects.ScanPaths[0] != "/work" {
+		t.Errorf("scan paths = %v, the later save should win", loaded.Projects.ScanPaths)
+	}
+	if mine.UI.Theme != "matrix" {
+		t.Error("Save() should not modify the config it saves")
+	}
+}
+
 func TestConfigPath(t *testing.T) {
 	path := Path()
 	if path == "" {
@@ -54,3 +95,58 @@ func TestConfigPath(t *testing.T) {
 		t.Errorf("Path() should return absolute path, got %q", path)
 	}
 }
+
+func TestLoadUpgradesUnversionedFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	original := "ui:\n  theme: nord\n"
+	if err := os.WriteFile(configPath, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.UI.Theme != "nord" || cfg.Version != SchemaVersion {
+		t.Errorf("Load() = theme %q, version %d", cfg.UI.Theme, cfg.Version)
+	}
+	if len(cfg.Warnings()) != 1 || !strings.Contains(cfg.Warnings()[0], "upgraded from version 0") {
+		t.Errorf("Warnings() = %v, want an upgrade note", cfg.Warnings())
+	}
+	if backup, err := os.ReadFile(configPath + ".v0.bak"); err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+}
+
+func TestLoadWarnsAboutUnknownKeys(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	data := "version: 1\nui:\n  sidebar_widht: 30\n"
+	if err := os.WriteFile(configPath, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	want := "line 3, column 3: ui.sidebar_widht: unknown key"
+	if len(cfg.Warnings()) != 1 || cfg.Warnings()[0] != want {
+		t.Errorf("Warnings() = %v, want [%s]", cfg.Warnings(), want)
+	}
+}
+
+func TestLoadRejectsNewerVersion(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 99\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(configPath); err == nil {
+		t.Error("Load() should refuse a file from a newer devdash")
+	}
+}
+
+func TestMigrationsMatchSchemaVersion(t *testing.T) {
+	if len(migrations) != SchemaVersion {
+		t.Errorf("%d migrations for schema version %d", len(migrations), SchemaVersion)
+	}
+}
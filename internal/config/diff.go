@@ -0,0 +1,26 @@
+package config
+
+import "reflect"
+
+// Changes describes what differs between two configs, such as "theme" or
+// "scan paths", for telling the user what a reload changed.
+func Changes(old, new *Config) []string {
+	var changes []string
+	add := func(what string, a, b any) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, what)
+		}
+	}
+
+	add("theme", old.UI.Theme, new.UI.Theme)
+	oldUI, newUI := old.UI, new.UI
+	oldUI.Theme, newUI.Theme = "", ""
+	add("display", oldUI, newUI)
+	add("polling intervals", old.Polling, new.Polling)
+	add("scan paths", old.Projects, new.Projects)
+	add("notifications", old.Notifications, new.Notifications)
+	add("restart policies", old.Restart, new.Restart)
+	add("health", old.Health, new.Health)
+	add("probes", old.Probes, new.Probes)
+	return changes
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	old := Default()
+	if got := Changes(old, Default()); len(got) != 0 {
+		t.Errorf("Changes() between defaults = %v", got)
+	}
+
+	changed := Default()
+	changed.UI.Theme = "nord"
+	changed.UI.SidebarWidth = 30
+	changed.Polling.BackgroundProject = 30
+	changed.Projects.ScanPaths = append(changed.Projects.ScanPaths, "/src")
+	changed.Notifications.CriticalOnly = true
+
+	want := []string{"theme", "display", "polling intervals", "scan paths", "notifications"}
+	if got := Changes(old, changed); !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes() = %v, want %v", got, want)
+	}
+}
+
+func TestChangesIgnoresPositions(t *testing.T) {
+	parsed, err := Parse([]byte("ui:\n  theme: matrix\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Changes(Default(), parsed); len(got) != 0 {
+		t.Errorf("Changes() = %v, want none for the same values", got)
+	}
+}
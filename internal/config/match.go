@@ -0,0 +1,26 @@
+package config
+
+import (
+	"path"
+	"strings"
+)
+
+// MatchScore matches a name against an override pattern and reports how
+// specific the match is. An empty or "*" pattern matches anything with score
+// 0, any other glob (path.Match syntax) scores 1 and an exact name scores 2.
+func MatchScore(pattern, name string) (int, bool) {
+	if pattern == "" || pattern == "*" {
+		return 0, true
+	}
+	if !strings.ContainsAny(pattern, `*?[\`) {
+		if pattern != name {
+			return 0, false
+		}
+		return 2, true
+	}
+	ok, err := path.Match(pattern, name)
+	if err != nil || !ok {
+		return 0, false
+	}
+	return 1, true
+}
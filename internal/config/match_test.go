@@ -0,0 +1,27 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestMatchScore(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		score         int
+		ok            bool
+	}{
+		{"", "postgres", 0, true},
+		{"*", "postgres", 0, true},
+		{"postgres", "postgres", 2, true},
+		{"postgres", "redis", 0, false},
+		{"redis*", "redis-cache", 1, true},
+		{"redis*", "postgres", 0, false},
+		{"[", "postgres", 0, false}, // malformed glob never matches
+	}
+	for _, tt := range tests {
+		score, ok := MatchScore(tt.pattern, tt.name)
+		if score != tt.score || ok != tt.ok {
+			t.Errorf("MatchScore(%q, %q) = (%d, %v), want (%d, %v)", tt.pattern, tt.name, score, ok, tt.score, tt.ok)
+		}
+	}
+}
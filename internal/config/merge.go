@@ -0,0 +1,26 @@
+package config
+
+import "reflect"
+
+// merge applies the fields changed in mine since base on top of disk, so
+// saving one setting keeps changes another instance saved meanwhile. Where
+// both sides changed a field, mine wins. Structs are merged field by field;
+// lists and maps are replaced whole.
+func merge(base, mine, disk *Config) {
+	mergeValue(reflect.ValueOf(base).Elem(), reflect.ValueOf(mine).Elem(), reflect.ValueOf(disk).Elem())
+}
+
+func mergeValue(base, mine, disk reflect.Value) {
+	if mine.Kind() != reflect.Struct {
+		if !reflect.DeepEqual(base.Interface(), mine.Interface()) {
+			disk.Set(mine)
+		}
+		return
+	}
+	for i := 0; i < mine.NumField(); i++ {
+		if !mine.Type().Field(i).IsExported() {
+			continue
+		}
+		mergeValue(base.Field(i), mine.Field(i), disk.Field(i))
+	}
+}
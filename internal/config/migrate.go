@@ -0,0 +1,21 @@
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/infktd/devdash/internal/schema"
+)
+
+// SchemaVersion is the config file version this devdash writes.
+const SchemaVersion = 1
+
+// migrations upgrade config files one version at a time; migrations[i]
+// takes a file from version i to i+1. Add one whenever a key is renamed or
+// restructured, and bump SchemaVersion.
+var migrations = []schema.Migration{
+	{
+		// Files before versioning only lack the version field
+		Description: "add version field",
+		Apply:       func(doc *yaml.Node) error { return nil },
+	},
+}
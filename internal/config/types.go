@@ -3,14 +3,51 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
+
+	"github.com/infktd/devdash/internal/scanner"
+	"github.com/infktd/devdash/internal/schema"
 )
 
 // Config represents the devdash configuration.
 type Config struct {
+	Version       int                 `yaml:"version"`
 	Projects      ProjectsConfig      `yaml:"projects"`
 	Notifications NotificationsConfig `yaml:"notifications"`
 	UI            UIConfig            `yaml:"ui"`
 	Polling       PollingConfig       `yaml:"polling"`
+	Restart       RestartConfig       `yaml:"restart"`
+	Health        HealthConfig        `yaml:"health"`
+	Probes        []ProbeConfig       `yaml:"probes,omitempty"`
+
+	warnings  []string              // Problems found by Load
+	positions map[string]schema.Key // Where each value is in the file
+	unknown   []schema.Key          // Keys in the file that don't map to a field
+	base      *Config               // As loaded, for merging on Save
+}
+
+// Warnings returns problems Load found that didn't stop it, such as
+// unknown keys, and notes about upgrades it made.
+func (c *Config) Warnings() []string {
+	return c.warnings
+}
+
+// ProbeConfig is a readiness check for services. Project and Service accept
+// glob patterns; the most specific matching probe applies. Exactly one of
+// TCP, HTTP and Command should be set.
+type ProbeConfig struct {
+	Project string `yaml:"project,omitempty"`
+	Service string `yaml:"service"`
+	TCP     string `yaml:"tcp,omitempty"`     // host:port that must accept connections
+	HTTP    string `yaml:"http,omitempty"`    // URL that must return 2xx
+	Command string `yaml:"command,omitempty"` // Shell command (run in the project dir) that must exit 0
+	Timeout int    `yaml:"timeout,omitempty"` // Seconds
+}
+
+// HealthConfig configures service health tracking.
+type HealthConfig struct {
+	FlapThreshold int `yaml:"flap_threshold"` // State changes within FlapWindow that mark a service as flapping (0 disables)
+	FlapWindow    int `yaml:"flap_window"`    // Seconds
 }
 
 // ProjectsConfig configures project discovery.
@@ -18,6 +55,7 @@ type ProjectsConfig struct {
 	ScanPaths    []string `yaml:"scan_paths"`
 	AutoDiscover bool     `yaml:"auto_discover"`
 	ScanDepth    int      `yaml:"scan_depth"`
+	Exclude      []string `yaml:"exclude"` // Directory globs to skip, matching the name or, with a slash, the path
 }
 
 // NotificationsConfig configures alerts and notifications.
@@ -26,13 +64,64 @@ type NotificationsConfig struct {
 	TUIAlerts     bool                   `yaml:"tui_alerts"`
 	CriticalOnly  bool                   `yaml:"critical_only"`
 	Overrides     []NotificationOverride `yaml:"overrides,omitempty"`
+	Webhooks      []WebhookConfig        `yaml:"webhooks,omitempty"`
+	Commands      []CommandHookConfig    `yaml:"commands,omitempty"`
+	History       HistoryConfig          `yaml:"history"`
+}
+
+// HistoryConfig configures the persistent alert history.
+type HistoryConfig struct {
+	MaxEntries    int `yaml:"max_entries"`    // Alerts kept in memory and loaded on start
+	RetentionDays int `yaml:"retention_days"` // Alerts older than this are dropped on load (0 keeps all)
+	MaxFileKB     int `yaml:"max_file_kb"`    // History file is rotated past this size
 }
 
-// NotificationOverride allows per-service notification settings.
+// WebhookConfig posts health events as JSON to an HTTP endpoint.
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Events  []string          `yaml:"events,omitempty"` // Empty means all event types
+}
+
+// CommandHookConfig runs a shell command for health events, passing the event
+// in DEVDASH_* environment variables.
+type CommandHookConfig struct {
+	Command string   `yaml:"command"`
+	Events  []string `yaml:"events,omitempty"` // Empty means all event types
+}
+
+// NotificationOverride allows per-project and per-service notification settings.
+// Project and Service accept glob patterns; an empty field matches anything.
 type NotificationOverride struct {
+	Project      string   `yaml:"project,omitempty"`
+	Service      string   `yaml:"service"`
+	System       *bool    `yaml:"system,omitempty"` // Nil leaves desktop notifications as configured
+	CriticalOnly bool     `yaml:"critical_only,omitempty"`
+	Events       []string `yaml:"events,omitempty"` // Only notify for these event types (crashed, recovered, ...)
+}
+
+// Restart policy modes.
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartBackoff   = "backoff"
+)
+
+// RestartConfig configures automatic restarts of crashed services.
+type RestartConfig struct {
+	Policies []RestartPolicy `yaml:"policies,omitempty"`
+}
+
+// RestartPolicy sets how crashed services are restarted. Project and Service
+// accept glob patterns; the most specific matching policy applies.
+type RestartPolicy struct {
+	Project      string `yaml:"project,omitempty"`
 	Service      string `yaml:"service"`
-	System       bool   `yaml:"system"`
-	CriticalOnly bool   `yaml:"critical_only,omitempty"`
+	Policy       string `yaml:"policy"`                  // never | on-failure | backoff
+	MaxAttempts  int    `yaml:"max_attempts,omitempty"`  // Give up after this many crashes within Window
+	Window       int    `yaml:"window,omitempty"`        // Seconds
+	InitialDelay int    `yaml:"initial_delay,omitempty"` // Seconds before the first restart
+	MaxDelay     int    `yaml:"max_delay,omitempty"`     // Backoff cap in seconds
 }
 
 // UIConfig configures the user interface.
@@ -43,8 +132,24 @@ type UIConfig struct {
 	ShowTimestamps bool   `yaml:"show_timestamps"`
 	DimTimestamps  bool   `yaml:"dim_timestamps"`
 	SidebarWidth   int    `yaml:"sidebar_width"`
+	SidebarGroupBy string `yaml:"sidebar_group_by"` // state, tag or root
+	SidebarSort    string `yaml:"sidebar_sort"`     // name, last_active or state
 }
 
+// Sidebar grouping modes.
+const (
+	GroupByState = "state" // Active projects, then idle
+	GroupByTag   = "tag"   // One section per project's first tag
+	GroupByRoot  = "root"  // One section per scan path
+)
+
+// Sidebar sort modes.
+const (
+	SortByName       = "name"
+	SortByLastActive = "last_active" // Most recently active first
+	SortByState      = "state"       // Running first, missing last
+)
+
 // PollingConfig configures polling intervals in seconds.
 type PollingConfig struct {
 	FocusedProject    int `yaml:"focused_project"`
@@ -62,15 +167,22 @@ func Default() *Config {
 		}
 	}
 	return &Config{
+		Version: SchemaVersion,
 		Projects: ProjectsConfig{
 			ScanPaths:    scanPaths,
 			AutoDiscover: true,
 			ScanDepth:    3,
+			Exclude:      slices.Clone(scanner.DefaultExcludes),
 		},
 		Notifications: NotificationsConfig{
 			SystemEnabled: true,
 			TUIAlerts:     true,
 			CriticalOnly:  false,
+			History: HistoryConfig{
+				MaxEntries:    500,
+				RetentionDays: 30,
+				MaxFileKB:     1024,
+			},
 		},
 		UI: UIConfig{
 			Theme:          "matrix",
@@ -79,10 +191,16 @@ func Default() *Config {
 			ShowTimestamps: true,
 			DimTimestamps:  true,
 			SidebarWidth:   25,
+			SidebarGroupBy: GroupByState,
+			SidebarSort:    SortByName,
 		},
 		Polling: PollingConfig{
 			FocusedProject:    2,
 			BackgroundProject: 10,
 		},
+		Health: HealthConfig{
+			FlapThreshold: 5,
+			FlapWindow:    120,
+		},
 	}
 }
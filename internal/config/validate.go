@@ -0,0 +1,267 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/infktd/devdash/internal/schema"
+)
+
+// ThemeNames lists the built-in themes.
+var ThemeNames = []string{
+	"matrix", "gruvbox", "dracula", "nord", "tokyo-night", "ayu-dark", "solarized-dark", "monokai",
+}
+
+// eventNames lists the health event types notifications can filter on.
+var eventNames = []string{"crashed", "recovered", "started", "stopped", "flapping", "ready", "not-ready"}
+
+// Problem is a config value devdash can't use, or for warnings, one that's
+// probably not what the user meant.
+type Problem struct {
+	Path    string // Key path, such as ui.theme or probes[1].tcp
+	Line    int    // Position of the value; 0 if it isn't in the file
+	Column  int
+	Message string
+	Warning bool // Reported, but the config is still usable
+}
+
+// String describes the problem with its position.
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.Path, p.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s: %s", p.Line, p.Column, p.Path, p.Message)
+}
+
+// ValidationError lists every problem that makes a config unusable.
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Problems) == 1 {
+		return "invalid config: " + e.Problems[0].String()
+	}
+	var msgs []string
+	for _, p := range e.Problems {
+		msgs = append(msgs, p.String())
+	}
+	return fmt.Sprintf("invalid config (%d problems): %s", len(e.Problems), strings.Join(msgs, "; "))
+}
+
+// Validate checks the config for values devdash can't use. It returns a
+// *ValidationError listing every problem, or nil. Warnings, such as scan
+// paths that don't exist yet, don't fail validation; see Check.
+func (c *Config) Validate() error {
+	var errs []Problem
+	for _, p := range c.Check() {
+		if !p.Warning {
+			errs = append(errs, p)
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Problems: errs}
+	}
+	return nil
+}
+
+// Check returns every problem with the config, warnings included, in the
+// order they appear in the file.
+func (c *Config) Check() []Problem {
+	ch := &checker{positions: c.positions}
+	for _, k := range c.unknown {
+		ch.problems = append(ch.problems, unknownKeyProblem(k))
+	}
+
+	ch.checkScanPaths(c.Projects.ScanPaths)
+	ch.atLeast("projects.scan_depth", c.Projects.ScanDepth, 1)
+	for i, pattern := range c.Projects.Exclude {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			ch.errorf(fmt.Sprintf("projects.exclude[%d]", i), "invalid pattern %q", pattern)
+		}
+	}
+
+	ch.oneOf("ui.theme", c.UI.Theme, ThemeNames)
+	ch.oneOf("ui.default_log_view", c.UI.DefaultLogView, []string{"focused", "unified"})
+	ch.atLeast("ui.sidebar_width", c.UI.SidebarWidth, 10)
+	ch.oneOf("ui.sidebar_group_by", c.UI.SidebarGroupBy, []string{GroupByState, GroupByTag, GroupByRoot})
+	ch.oneOf("ui.sidebar_sort", c.UI.SidebarSort, []string{SortByName, SortByLastActive, SortByState})
+
+	ch.atLeast("polling.focused_project", c.Polling.FocusedProject, 1)
+	ch.atLeast("polling.background_project", c.Polling.BackgroundProject, 1)
+
+	ch.atLeast("health.flap_threshold", c.Health.FlapThreshold, 0)
+	if c.Health.FlapThreshold > 0 {
+		ch.atLeast("health.flap_window", c.Health.FlapWindow, 1)
+	}
+
+	n := c.Notifications
+	ch.atLeast("notifications.history.max_entries", n.History.MaxEntries, 0)
+	ch.atLeast("notifications.history.retention_days", n.History.RetentionDays, 0)
+	ch.atLeast("notifications.history.max_file_kb", n.History.MaxFileKB, 0)
+	for i, o := range n.Overrides {
+		ch.events(fmt.Sprintf("notifications.overrides[%d].events", i), o.Events)
+	}
+	for i, wh := range n.Webhooks {
+		path := fmt.Sprintf("notifications.webhooks[%d]", i)
+		if u, err := url.Parse(wh.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			ch.errorf(path+".url", "%q is not an http or https URL", wh.URL)
+		}
+		ch.events(path+".events", wh.Events)
+	}
+	for i, cmd := range n.Commands {
+		path := fmt.Sprintf("notifications.commands[%d]", i)
+		if strings.TrimSpace(cmd.Command) == "" {
+			ch.errorf(path+".command", "command is empty")
+		}
+		ch.events(path+".events", cmd.Events)
+	}
+
+	for i, p := range c.Restart.Policies {
+		path := fmt.Sprintf("restart.policies[%d]", i)
+		ch.oneOf(path+".policy", p.Policy, []string{RestartNever, RestartOnFailure, RestartBackoff})
+		ch.atLeast(path+".max_attempts", p.MaxAttempts, 0)
+		ch.atLeast(path+".window", p.Window, 0)
+		ch.atLeast(path+".initial_delay", p.InitialDelay, 0)
+		ch.atLeast(path+".max_delay", p.MaxDelay, 0)
+	}
+
+	for i, p := range c.Probes {
+		path := fmt.Sprintf("probes[%d]", i)
+		if p.Service == "" {
+			ch.errorf(path+".service", "service is required")
+		}
+		set := 0
+		for _, check := range []string{p.TCP, p.HTTP, p.Command} {
+			if check != "" {
+				set++
+			}
+		}
+		if set != 1 {
+			ch.errorf(path, "set exactly one of tcp, http and command")
+		}
+		ch.atLeast(path+".timeout", p.Timeout, 0)
+	}
+
+	sort.SliceStable(ch.problems, func(i, j int) bool {
+		a, b := ch.problems[i], ch.problems[j]
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0 // Values from defaults last
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return ch.problems
+}
+
+func unknownKeyProblem(k schema.Key) Problem {
+	return Problem{Path: k.Path, Line: k.Line, Column: k.Column, Message: "unknown key", Warning: true}
+}
+
+// checker collects problems, positioning them with the file's keys.
+type checker struct {
+	positions map[string]schema.Key
+	problems  []Problem
+}
+
+func (ch *checker) add(path string, warning bool, format string, args ...any) {
+	pos := ch.positions[path]
+	ch.problems = append(ch.problems, Problem{
+		Path:    path,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Message: fmt.Sprintf(format, args...),
+		Warning: warning,
+	})
+}
+
+func (ch *checker) errorf(path, format string, args ...any) {
+	ch.add(path, false, format, args...)
+}
+
+func (ch *checker) warnf(path, format string, args ...any) {
+	ch.add(path, true, format, args...)
+}
+
+func (ch *checker) atLeast(path string, value, min int) {
+	if value < min {
+		ch.errorf(path, "must be at least %d, got %d", min, value)
+	}
+}
+
+func (ch *checker) oneOf(path, value string, valid []string) {
+	if slices.Contains(valid, value) {
+		return
+	}
+	if guess := closest(value, valid); guess != "" {
+		ch.errorf(path, "unknown value %q, did you mean %q? (one of %s)", value, guess, strings.Join(valid, ", "))
+		return
+	}
+	ch.errorf(path, "unknown value %q (one of %s)", value, strings.Join(valid, ", "))
+}
+
+func (ch *checker) events(path string, events []string) {
+	for i, e := range events {
+		ch.oneOf(fmt.Sprintf("%s[%d]", path, i), e, eventNames)
+	}
+}
+
+func (ch *checker) checkScanPaths(paths []string) {
+	home, _ := os.UserHomeDir()
+	for i, p := range paths {
+		path := fmt.Sprintf("projects.scan_paths[%d]", i)
+		expanded := p
+		if rest, ok := strings.CutPrefix(p, "~/"); ok && home != "" {
+			expanded = filepath.Join(home, rest)
+		}
+		info, err := os.Stat(expanded)
+		switch {
+		case os.IsNotExist(err):
+			ch.warnf(path, "%s does not exist", p)
+		case err != nil:
+			ch.warnf(path, "%v", err)
+		case !info.IsDir():
+			ch.errorf(path, "%s is not a directory", p)
+		}
+	}
+}
+
+// closest returns the option within two edits of value, if any, for
+// suggesting a fix for typos.
+func closest(value string, options []string) string {
+	best, bestDist := "", 3
+	for _, o := range options {
+		if d := editDistance(strings.ToLower(value), o); d < bestDist {
+			best, bestDist = o, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infktd/devdash/internal/health"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Errorf("Default().Validate() = %v", err)
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file")
+	os.WriteFile(file, nil, 0644)
+
+	data := `projects:
+  scan_paths:
+    - ` + dir + `
+    - ` + file + `
+  scan_depth: -1
+ui:
+  theme: nordd
+polling:
+  focused_project: 0
+probes:
+  - service: db
+    tcp: localhost:5432
+    http: http://localhost
+`
+	cfg, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var verr *ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("Validate() = %v, want a *ValidationError", err)
+	}
+	want := []string{
+		"line 4, column 7: projects.scan_paths[1]: " + file + " is not a directory",
+		"line 5, column 15: projects.scan_depth: must be at least 1, got -1",
+		`line 7, column 10: ui.theme: unknown value "nordd", did you mean "nord"?`,
+		"line 9, column 20: polling.focused_project: must be at least 1, got 0",
+		"line 11, column 5: probes[0]: set exactly one of tcp, http and command",
+	}
+	if len(verr.Problems) != len(want) {
+		t.Fatalf("got %d problems, want %d:\n%v", len(verr.Problems), len(want), verr)
+	}
+	for i, p := range verr.Problems {
+		if !strings.HasPrefix(p.String(), want[i]) {
+			t.Errorf("problem %d = %q, want prefix %q", i, p.String(), want[i])
+		}
+	}
+}
+
+func TestCheckWarnings(t *testing.T) {
+	data := `projects:
+  scan_paths:
+    - /nonexistent/devdash/path
+ui:
+  sidebar_widht: 30
+`
+	cfg, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("warnings shouldn't fail validation: %v", err)
+	}
+
+	problems := cfg.Check()
+	if len(problems) != 2 {
+		t.Fatalf("Check() = %v, want 2 warnings", problems)
+	}
+	for _, p := range problems {
+		if !p.Warning {
+			t.Errorf("%v should be a warning", p)
+		}
+	}
+	if problems[0].Path != "projects.scan_paths[0]" || problems[1].Path != "ui.sidebar_widht" {
+		t.Errorf("problems out of file order: %v", problems)
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	cfg := Default()
+	cfg.Notifications.Webhooks = []WebhookConfig{{URL: "hooks.example.com", Events: []string{"crashd"}}}
+	cfg.Notifications.Commands = []CommandHookConfig{{Command: " "}}
+	cfg.Restart.Policies = []RestartPolicy{{Service: "*", Policy: "always"}}
+
+	var verr *ValidationError
+	if !errors.As(cfg.Validate(), &verr) {
+		t.Fatal("Validate() should fail")
+	}
+	var paths []string
+	for _, p := range verr.Problems {
+		if p.Line != 0 {
+			t.Errorf("%v: values set in code have no position", p)
+		}
+		paths = append(paths, p.Path)
+	}
+	want := "notifications.webhooks[0].url notifications.webhooks[0].events[0] notifications.commands[0].command restart.policies[0].policy"
+	if got := strings.Join(paths, " "); got != want {
+		t.Errorf("problem paths = %s, want %s", got, want)
+	}
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\nui:\n  theme: nordd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(configPath)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Load() error = %v, want a *ValidationError", err)
+	}
+}
+
+func TestEventNamesMatchHealthEvents(t *testing.T) {
+	var names []string
+	for e := health.EventType(0); e.String() != "unknown"; e++ {
+		names = append(names, e.String())
+	}
+	if strings.Join(names, ",") != strings.Join(eventNames, ",") {
+		t.Errorf("eventNames = %v, health events are %v", eventNames, names)
+	}
+}
+
+func TestClosest(t *testing.T) {
+	tests := map[string]string{
+		"nordd":      "nord",
+		"Dracula":    "dracula",
+		"tokyonight": "tokyo-night",
+		"solarized":  "",
+	}
+	for value, want := range tests {
+		if got := closest(value, ThemeNames); got != want {
+			t.Errorf("closest(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestValidateExcludePatterns(t *testing.T) {
+	data := `projects:
+  exclude:
+    - build
+    - "[unclosed"
+`
+	cfg, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	err = cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), `line 4, column 7: projects.exclude[1]: invalid pattern "[unclosed"`) {
+		t.Errorf("Validate() = %v, want the bad pattern", err)
+	}
+}
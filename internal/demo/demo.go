@@ -0,0 +1,221 @@
+// Package demo populates devdash with fake projects for `devdash --demo`.
+//
+// Each running project is backed by a composetest server on the project's
+// socket, with processes that log, use CPU and occasionally crash, so the
+// dashboard can be explored (and issues reproduced) without Nix.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/infktd/devdash/internal/compose/composetest"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// service is a fake process in a demo project.
+type service struct {
+	name      string
+	dependsOn []string
+	ports     []int
+	logs      []string // Messages picked at random, prefixed with a level
+	flaky     bool     // Crashes now and then and comes back
+}
+
+// project is a demo project; projects without services are idle.
+type project struct {
+	name     string
+	services []service
+}
+
+var projects = []project{
+	{
+		name: "storefront",
+		services: []service{
+			{name: "web", dependsOn: []string{"api"}, ports: []int{3000}, logs: []string{
+				"INFO GET / 200 4ms",
+				"INFO GET /products 200 18ms",
+				"INFO compiled client in 412ms",
+				"WARN slow render of /checkout (230ms)",
+			}},
+			{name: "api", dependsOn: []string{"postgres", "redis"}, ports: []int{8080}, logs: []string{
+				"INFO GET /api/products 200 12ms",
+				"INFO POST /api/cart 201 31ms",
+				"DEBUG cache hit products:page:1",
+				"ERROR POST /api/checkout 502 upstream timeout",
+			}},
+			{name: "postgres", ports: []int{5432}, logs: []string{
+				"INFO checkpoint starting: time",
+				"INFO checkpoint complete: wrote 42 buffers",
+				"WARN duration: 1204 ms statement: SELECT * FROM orders",
+			}},
+			{name: "redis", ports: []int{6379}, logs: []string{
+				"INFO DB saved on disk",
+				"INFO 10 changes in 300 seconds. Saving...",
+			}},
+			{name: "worker", dependsOn: []string{"redis"}, flaky: true, logs: []string{
+				"INFO processed job send-receipt",
+				"INFO processed job resize-image",
+				"WARN job sync-inventory retrying (attempt 2)",
+			}},
+		},
+	},
+	{
+		name: "analytics",
+		services: []service{
+			{name: "clickhouse", ports: []int{8123, 9000}, logs: []string{
+				"INFO merged 6 parts in events",
+				"DEBUG query finished in 38ms",
+			}},
+			{name: "ingest", dependsOn: []string{"clickhouse"}, flaky: true, logs: []string{
+				"INFO flushed 500 events",
+				"WARN batch delayed: backpressure",
+				"ERROR failed to parse event: unexpected EOF",
+			}},
+			{name: "jupyter", ports: []int{8888}, logs: []string{
+				"INFO Kernel started",
+				"INFO Saving file at /notebooks/retention.ipynb",
+			}},
+		},
+	},
+	{name: "blog"},
+}
+
+// Demo is a set of fake projects with their servers.
+type Demo struct {
+	// Dir holds the project directories and the demo's config and state.
+	Dir      string
+	Registry *registry.Registry
+
+	servers []*composetest.Server
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// Start creates the demo projects and starts simulating activity.
+//
+// Start points XDG_CONFIG_HOME and XDG_STATE_HOME into the demo directory so
+// settings, the registry and alert history saved during the demo don't touch
+// the real ones.
+func Start() (*Demo, error) {
+	// Short base path: Unix socket paths are limited to ~104 bytes
+	dir, err := os.MkdirTemp("/tmp", "devdash-demo")
+	if err != nil {
+		return nil, err
+	}
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	os.Setenv("XDG_STATE_HOME", filepath.Join(dir, "state"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Demo{Dir: dir, Registry: &registry.Registry{}, cancel: cancel}
+
+	for _, proj := range projects {
+		path := filepath.Join(dir, proj.name)
+		if err := os.MkdirAll(filepath.Join(path, ".devenv", "run"), 0755); err != nil {
+			d.Close()
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(path, "devenv.nix"), []byte("{ ... }: { }\n"), 0644); err != nil {
+			d.Close()
+			return nil, err
+		}
+		p := d.Registry.AddProject(path)
+		if len(proj.services) == 0 {
+			continue
+		}
+
+		srv, err := composetest.New(p.SocketPath())
+		if err != nil {
+			d.Close()
+			return nil, fmt.Errorf("starting %s: %w", proj.name, err)
+		}
+		srv.SetProjectName(proj.name)
+		d.servers = append(d.servers, srv)
+		for _, svc := range proj.services {
+			srv.AddProcess(svc.name, svc.dependsOn...)
+			srv.SetPorts(svc.name, svc.ports...)
+		}
+
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			simulate(ctx, srv, proj.services)
+		}()
+	}
+
+	// The running projects make up one product
+	d.Registry.AddGroup("product", []string{
+		filepath.Join(dir, "storefront"),
+		filepath.Join(dir, "analytics"),
+	})
+	return d, nil
+}
+
+// Close stops the simulation and servers and removes the demo directory.
+func (d *Demo) Close() error {
+	d.cancel()
+	d.wg.Wait()
+	for _, srv := range d.servers {
+		srv.Close()
+	}
+	return os.RemoveAll(d.Dir)
+}
+
+// Timing of the simulation
+const (
+	tickInterval  = 700 * time.Millisecond
+	crashChance   = 0.01 // Per tick, for flaky services
+	crashDowntime = 6 * time.Second
+)
+
+// simulate emits logs and resource usage and crashes flaky services until
+// ctx is cancelled.
+func simulate(ctx context.Context, srv *composetest.Server, services []service) {
+	ticker := time.NewTicker(tickInterval)
+	defer ticker.Stop()
+	downSince := make(map[string]time.Time)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, svc := range services {
+				if since, down := downSince[svc.name]; down {
+					if now.Sub(since) >= crashDowntime {
+						delete(downSince, svc.name)
+						srv.Restart(svc.name)
+						srv.Log(svc.name, logLine(now, "INFO restarted after crash"))
+					}
+					continue
+				}
+				if p, ok := srv.Process(svc.name); !ok || !p.Running {
+					// Stopped from the dashboard
+					continue
+				}
+
+				if svc.flaky && rand.Float64() < crashChance {
+					srv.Log(svc.name, logLine(now, "ERROR panic: connection reset by peer"))
+					srv.Crash(svc.name, 1)
+					downSince[svc.name] = now
+					continue
+				}
+
+				srv.SetUsage(svc.name, rand.Float64()*25, int64(50+rand.IntN(400))<<20)
+				if len(svc.logs) > 0 && rand.IntN(3) == 0 {
+					srv.Log(svc.name, logLine(now, svc.logs[rand.IntN(len(svc.logs))]))
+				}
+			}
+		}
+	}
+}
+
+// logLine formats a message the way the log view parses timestamps.
+func logLine(t time.Time, msg string) string {
+	return t.Format("2006-01-02 15:04:05") + " " + msg
+}
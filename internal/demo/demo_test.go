@@ -0,0 +1,52 @@
+package demo
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func TestStartCreatesProjects(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+
+	d, err := Start()
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	if !strings.HasPrefix(os.Getenv("XDG_CONFIG_HOME"), d.Dir) {
+		t.Errorf("XDG_CONFIG_HOME = %q, want inside %q", os.Getenv("XDG_CONFIG_HOME"), d.Dir)
+	}
+	if len(d.Registry.Projects) != len(projects) {
+		t.Fatalf("expected %d projects, got %d", len(projects), len(d.Registry.Projects))
+	}
+
+	states := make(map[string]registry.ProjectState)
+	for _, p := range d.Registry.Projects {
+		states[p.Name] = p.DetectState()
+	}
+	if !states["storefront"].IsActive() || !states["analytics"].IsActive() {
+		t.Errorf("demo projects with services should be running: %v", states)
+	}
+	if states["blog"] != registry.StateIdle {
+		t.Errorf("blog should be idle, got %v", states["blog"])
+	}
+
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if _, err := os.Stat(d.Dir); !os.IsNotExist(err) {
+		t.Error("Close() should remove the demo directory")
+	}
+}
+
+func TestLogLineHasParsableTimestamp(t *testing.T) {
+	ts := time.Date(2026, 1, 20, 14, 30, 0, 0, time.Local)
+	if got := logLine(ts, "INFO ready"); got != "2026-01-20 14:30:00 INFO ready" {
+		t.Errorf("logLine() = %q", got)
+	}
+}
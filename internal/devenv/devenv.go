@@ -0,0 +1,60 @@
+// Package devenv wraps the devenv CLI for starting and stopping projects.
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// Up starts the project in dir in the background using devenv up -d.
+func Up(dir string, kind registry.Kind) error {
+	return run(dir, kind, "up", "-d")
+}
+
+// Down stops the project in dir using devenv down.
+func Down(dir string, kind registry.Kind) error {
+	return run(dir, kind, "down")
+}
+
+// Shutdown stops a running project. It asks process-compose to shut down
+// through its socket first and falls back to devenv down if the socket
+// is not reachable.
+func Shutdown(socketPath, dir string, kind registry.Kind) error {
+	client := compose.NewClient(socketPath)
+	if err := client.Connect(); err == nil {
+		if err := client.ShutdownProject(); err != nil {
+			return fmt.Errorf("API shutdown failed: %v", err)
+		}
+		return nil
+	}
+	return Down(dir, kind)
+}
+
+// Command returns the command running devenv with args for a project of
+// kind in dir. Flake projects get devenv from their dev shell, so it runs
+// through nix develop; --impure lets devenv find the project directory.
+func Command(ctx context.Context, dir string, kind registry.Kind, args ...string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if kind == registry.KindFlake {
+		cmd = exec.CommandContext(ctx, "nix", append([]string{"develop", "--impure", "-c", "devenv"}, args...)...)
+	} else {
+		cmd = exec.CommandContext(ctx, "devenv", args...)
+	}
+	cmd.Dir = dir
+	return cmd
+}
+
+// run executes devenv with args in dir, including the command output in
+// the returned error so build failures are visible to the caller.
+func run(dir string, kind registry.Kind, args ...string) error {
+	cmd := Command(context.Background(), dir, kind, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil && len(output) > 0 {
+		return fmt.Errorf("%v: %s", err, string(output))
+	}
+	return err
+}
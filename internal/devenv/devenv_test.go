@@ -0,0 +1,101 @@
+package devenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// fakeDevenv installs a devenv stub on PATH that records its arguments
+// to args.txt in dir and exits with the given code.
+func fakeDevenv(t *testing.T, exitCode int, output string) string {
+	t.Helper()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo \"$@\" > \"$PWD/args.txt\"\n" +
+		"echo '" + output + "'\n" +
+		"exit " + string(rune('0'+exitCode)) + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "devenv"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write stub: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return binDir
+}
+
+func TestUpRunsDevenvUpDetached(t *testing.T) {
+	fakeDevenv(t, 0, "ok")
+	dir := t.TempDir()
+
+	if err := Up(dir, registry.KindStandalone); err != nil {
+		t.Fatalf("Up() error: %v", err)
+	}
+
+	args, err := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if err != nil {
+		t.Fatalf("stub was not run in project dir: %v", err)
+	}
+	if strings.TrimSpace(string(args)) != "up -d" {
+		t.Errorf("args = %q, want %q", strings.TrimSpace(string(args)), "up -d")
+	}
+}
+
+func TestDownRunsDevenvDown(t *testing.T) {
+	fakeDevenv(t, 0, "ok")
+	dir := t.TempDir()
+
+	if err := Down(dir, registry.KindStandalone); err != nil {
+		t.Fatalf("Down() error: %v", err)
+	}
+
+	args, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if strings.TrimSpace(string(args)) != "down" {
+		t.Errorf("args = %q, want %q", strings.TrimSpace(string(args)), "down")
+	}
+}
+
+func TestUpIncludesOutputInError(t *testing.T) {
+	fakeDevenv(t, 1, "error: attribute missing")
+
+	err := Up(t.TempDir(), registry.KindStandalone)
+	if err == nil {
+		t.Fatal("Up() should fail when devenv exits non-zero")
+	}
+	if !strings.Contains(err.Error(), "attribute missing") {
+		t.Errorf("error should include command output, got %q", err.Error())
+	}
+}
+
+func TestShutdownFallsBackToDown(t *testing.T) {
+	fakeDevenv(t, 0, "ok")
+	dir := t.TempDir()
+
+	if err := Shutdown(filepath.Join(dir, "missing.sock"), dir, registry.KindStandalone); err != nil {
+		t.Fatalf("Shutdown() error: %v", err)
+	}
+
+	args, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if strings.TrimSpace(string(args)) != "down" {
+		t.Errorf("expected fallback to devenv down, got args %q", args)
+	}
+}
+
+func TestFlakeProjectsRunThroughNixDevelop(t *testing.T) {
+	binDir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" > \"$PWD/args.txt\"\n"
+	if err := os.WriteFile(filepath.Join(binDir, "nix"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write stub: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	dir := t.TempDir()
+
+	if err := Up(dir, registry.KindFlake); err != nil {
+		t.Fatalf("Up() error: %v", err)
+	}
+	args, _ := os.ReadFile(filepath.Join(dir, "args.txt"))
+	if want := "develop --impure -c devenv up -d"; strings.TrimSpace(string(args)) != want {
+		t.Errorf("args = %q, want %q", strings.TrimSpace(string(args)), want)
+	}
+}
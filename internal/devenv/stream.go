@@ -0,0 +1,134 @@
+package devenv
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// Stage is a phase of devenv up, parsed from its output.
+type Stage int
+
+const (
+	StageStarting Stage = iota
+	StageEvaluating
+	StageBuilding
+	StageLaunching
+	StageWaiting
+	StageReady
+)
+
+func (s Stage) String() string {
+	switch s {
+	case StageStarting:
+		return "Starting devenv..."
+	case StageEvaluating:
+		return "Evaluating Nix expressions..."
+	case StageBuilding:
+		return "Building environment..."
+	case StageLaunching:
+		return "Launching process-compose..."
+	case StageWaiting:
+		return "Waiting for processes..."
+	case StageReady:
+		return "Services online"
+	default:
+		return "unknown"
+	}
+}
+
+// Progress returns the approximate completion (0.0 to 1.0) at the start of s.
+func (s Stage) Progress() float64 {
+	switch s {
+	case StageEvaluating:
+		return 0.1
+	case StageBuilding:
+		return 0.3
+	case StageLaunching:
+		return 0.6
+	case StageWaiting:
+		return 0.8
+	case StageReady:
+		return 1.0
+	default:
+		return 0.0
+	}
+}
+
+// stageMarkers maps output fragments (lowercased) to the stage they indicate,
+// checked from the latest stage backwards.
+var stageMarkers = []struct {
+	stage   Stage
+	markers []string
+}{
+	{StageLaunching, []string{"process-compose", "starting processes", "running in background"}},
+	{StageBuilding, []string{"building", "copying path", "downloading", "fetching"}},
+	{StageEvaluating, []string{"evaluating", "loading", "using cachix"}},
+}
+
+// ParseStage returns the stage indicated by a line of devenv output.
+func ParseStage(line string) (Stage, bool) {
+	lower := strings.ToLower(line)
+	for _, sm := range stageMarkers {
+		for _, marker := range sm.markers {
+			if strings.Contains(lower, marker) {
+				return sm.stage, true
+			}
+		}
+	}
+	return StageStarting, false
+}
+
+// UpStream starts the project in dir in the background like Up, sending each
+// line of output to lines as it is produced. lines is closed when devenv
+// exits. Cancelling ctx kills devenv and everything it spawned.
+func UpStream(ctx context.Context, dir string, kind registry.Kind, lines chan<- string) error {
+	defer close(lines)
+
+	cmd := Command(ctx, dir, kind, "up", "-d")
+	// Cancelling also stops nix builds where the platform allows it
+	killProcessGroup(cmd)
+	// Don't hang on output pipes inherited by the detached process-compose
+	cmd.WaitDelay = 2 * time.Second
+
+	pr, pw := io.Pipe()
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		pw.Close()
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+			}
+		}
+		// Drain anything left so devenv never blocks on a full pipe
+		io.Copy(io.Discard, pr)
+	}()
+
+	err := cmd.Wait()
+	pw.Close()
+	<-done
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("devenv up failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package devenv
+
+import "os/exec"
+
+// Platforms without process groups only kill devenv itself on cancel.
+
+func killProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,115 @@
+package devenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// fakeDevenvScript installs a devenv stub on PATH with the given body.
+func fakeDevenvScript(t *testing.T, body string) {
+	t.Helper()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(filepath.Join(binDir, "devenv"), []byte(script), 0755); err != nil {
+		t.Fatalf("failed to write stub: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func collect(lines <-chan string) []string {
+	var out []string
+	for line := range lines {
+		out = append(out, line)
+	}
+	return out
+}
+
+func TestUpStreamSendsOutputLines(t *testing.T) {
+	fakeDevenvScript(t, `echo "• Evaluating devenv.nix"; echo "building '/nix/store/abc.drv'" >&2; echo "• Starting processes"`)
+
+	lines := make(chan string, 10)
+	err := UpStream(context.Background(), t.TempDir(), registry.KindStandalone, lines)
+	if err != nil {
+		t.Fatalf("UpStream() error: %v", err)
+	}
+
+	got := collect(lines)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 lines (stdout and stderr), got %q", got)
+	}
+	if got[1] != "building '/nix/store/abc.drv'" {
+		t.Errorf("line 2 = %q", got[1])
+	}
+}
+
+func TestUpStreamFailure(t *testing.T) {
+	fakeDevenvScript(t, `echo "error: attribute 'postgress' missing"; exit 1`)
+
+	lines := make(chan string, 10)
+	err := UpStream(context.Background(), t.TempDir(), registry.KindStandalone, lines)
+	if err == nil {
+		t.Fatal("expected error for failing devenv")
+	}
+	if got := collect(lines); len(got) != 1 {
+		t.Errorf("error output should still be streamed, got %q", got)
+	}
+}
+
+func TestUpStreamCancel(t *testing.T) {
+	fakeDevenvScript(t, `echo started; sleep 30`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan string, 10)
+	result := make(chan error, 1)
+	go func() { result <- UpStream(ctx, t.TempDir(), registry.KindStandalone, lines) }()
+
+	if line := <-lines; line != "started" {
+		t.Fatalf("first line = %q", line)
+	}
+	cancel()
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("UpStream did not return after cancel")
+	}
+}
+
+func TestParseStage(t *testing.T) {
+	tests := []struct {
+		line  string
+		stage Stage
+		ok    bool
+	}{
+		{"• Evaluating devenv.nix", StageEvaluating, true},
+		{"• Using Cachix: devenv", StageEvaluating, true},
+		{"building '/nix/store/xyz-postgres.drv'...", StageBuilding, true},
+		{"copying path '/nix/store/abc' from 'https://cache.nixos.org'", StageBuilding, true},
+		{"• Starting processes ...", StageLaunching, true},
+		{"process-compose is running in background", StageLaunching, true},
+		{"hello world", StageStarting, false},
+	}
+	for _, tt := range tests {
+		stage, ok := ParseStage(tt.line)
+		if stage != tt.stage || ok != tt.ok {
+			t.Errorf("ParseStage(%q) = (%v, %v), want (%v, %v)", tt.line, stage, ok, tt.stage, tt.ok)
+		}
+	}
+}
+
+func TestStageProgressIncreases(t *testing.T) {
+	for s := StageStarting; s < StageReady; s++ {
+		if s.Progress() >= (s + 1).Progress() {
+			t.Errorf("progress of %v should be below %v", s, s+1)
+		}
+	}
+}
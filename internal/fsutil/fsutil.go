@@ -0,0 +1,87 @@
+// Package fsutil provides crash- and concurrency-safe file writes for the
+// files devdash shares between instances, such as the registry and config.
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// WriteFile writes data to path atomically: it writes a temporary file in
+// the same directory and renames it over path, so readers see either the
+// old or the new contents, never a partial write. Parent directories are
+// created as needed. A symlinked path is followed, so the link (e.g. one
+// from a dotfile manager) stays in place and its target is replaced.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	path = resolve(path)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Clean up on any failure; after the rename this is a no-op
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// resolve returns the file path refers to after following symlinks, or path
+// itself if it can't be resolved, e.g. because it doesn't exist yet.
+func resolve(path string) string {
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		return real
+	}
+	return path
+}
+
+// FileLock is an advisory lock held on a file's lock file.
+type FileLock struct {
+	f *os.File
+}
+
+// Lock takes an exclusive advisory lock for path, blocking until other
+// holders release it. The lock is held on a sibling path+".lock" file, since
+// WriteFile replaces path itself. Like WriteFile it follows symlinks, so the
+// link and its target share a lock. Parent directories are created as needed.
+func Lock(path string) (*FileLock, error) {
+	path = resolve(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &FileLock{f: f}, nil
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	unlockErr := unlockFile(l.f)
+	if err := l.f.Close(); err != nil && unlockErr == nil {
+		return err
+	}
+	return unlockErr
+}
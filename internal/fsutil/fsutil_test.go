@@ -0,0 +1,101 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "projects.yaml")
+
+	if err := WriteFile(path, []byte("one"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := WriteFile(path, []byte("two"), 0600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "two" {
+		t.Fatalf("ReadFile() = %q, %v; want \"two\"", data, err)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestWriteFileKeepsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "dotfiles", "config.yaml")
+	os.MkdirAll(filepath.Dir(target), 0755)
+	os.WriteFile(target, []byte("one"), 0644)
+	link := filepath.Join(dir, "config.yaml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+
+	if err := WriteFile(link, []byte("two"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("symlink was replaced: %v", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "two" {
+		t.Errorf("target = %q, want \"two\"", data)
+	}
+
+	lock, err := Lock(link)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	defer lock.Unlock()
+	if _, err := os.Stat(target + ".lock"); err != nil {
+		t.Errorf("lock should sit next to the target: %v", err)
+	}
+}
+
+func TestLockExcludes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no advisory locks on windows")
+	}
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+
+	first, err := Lock(path)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	acquired := make(chan *FileLock)
+	go func() {
+		second, err := Lock(path)
+		if err != nil {
+			t.Errorf("second Lock() error = %v", err)
+		}
+		acquired <- second
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Lock() should wait for the first to unlock")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := first.Unlock(); err != nil {
+		t.Fatalf("Unlock() error = %v", err)
+	}
+	select {
+	case second := <-acquired:
+		second.Unlock()
+	case <-time.After(2 * time.Second):
+		t.Fatal("second Lock() not acquired after Unlock()")
+	}
+}
@@ -13,6 +13,9 @@ const (
 	EventServiceRecovered
 	EventServiceStarted
 	EventServiceStopped
+	EventServiceFlapping
+	EventServiceReady
+	EventServiceNotReady
 )
 
 func (e EventType) String() string {
@@ -25,6 +28,12 @@ func (e EventType) String() string {
 		return "started"
 	case EventServiceStopped:
 		return "stopped"
+	case EventServiceFlapping:
+		return "flapping"
+	case EventServiceReady:
+		return "ready"
+	case EventServiceNotReady:
+		return "not-ready"
 	default:
 		return "unknown"
 	}
@@ -35,8 +44,26 @@ type Event struct {
 	Type      EventType
 	Project   string
 	Service   string
-	ExitCode  int // Only for crashed events
+	ExitCode  int // For crashed events, and flapping events started by a crash
 	Timestamp time.Time
+
+	// Suppressed marks a transition hidden by flap detection. It should not
+	// be notified, but restart supervision still needs to see it.
+	Suppressed bool
+	// Settled marks the state a flapping service settled in. It is worth
+	// notifying but was already seen as a suppressed transition.
+	Settled bool
+}
+
+// IsCrash reports whether the event records a new crash of the service.
+func (e Event) IsCrash() bool {
+	switch e.Type {
+	case EventServiceCrashed:
+		return !e.Settled
+	case EventServiceFlapping:
+		return e.ExitCode != 0
+	}
+	return false
 }
 
 // ServiceState represents the last known state of a service.
@@ -46,6 +73,12 @@ type ServiceState struct {
 	Timestamp time.Time
 }
 
+// flapState tracks recent transitions of a service for flap detection.
+type flapState struct {
+	transitions []time.Time
+	flapping    bool
+}
+
 // Monitor watches for service state changes.
 type Monitor struct {
 	states   map[string]ServiceState // key: "project:service"
@@ -54,6 +87,15 @@ type Monitor struct {
 	done     chan struct{}
 	mu       sync.RWMutex
 	running  bool
+
+	// Readiness probe results for running services
+	ready map[string]bool
+
+	// Flap detection (disabled when flapThreshold is 0)
+	flaps         map[string]*flapState
+	flapThreshold int
+	flapWindow    time.Duration
+	now           func() time.Time
 }
 
 // NewMonitor creates a health monitor with the given polling interval.
@@ -63,6 +105,9 @@ func NewMonitor(interval time.Duration) *Monitor {
 		events:   make(chan Event, 100), // Buffered to avoid blocking
 		interval: interval,
 		done:     make(chan struct{}),
+		flaps:    make(map[string]*flapState),
+		ready:    make(map[string]bool),
+		now:      time.Now,
 	}
 }
 
@@ -76,22 +121,46 @@ func key(project, service string) string {
 	return project + ":" + service
 }
 
+// SetFlapDetection enables flap detection: a service that changes between
+// running and stopped threshold times within window is marked as flapping.
+// A threshold of 0 disables detection.
+func (m *Monitor) SetFlapDetection(threshold int, window time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.flapThreshold = threshold
+	m.flapWindow = window
+	if threshold <= 0 {
+		m.flaps = make(map[string]*flapState)
+	}
+}
+
 // UpdateService updates the state of a service and emits events if changed.
 // Returns any event generated by the state change.
+//
+// With flap detection enabled, a flapping service emits a single
+// EventServiceFlapping, then its further transitions are marked Suppressed.
+// Once it has not changed state for the flap window, the settled state is
+// emitted as a crashed, recovered or stopped event marked Settled.
 func (m *Monitor) UpdateService(project, service string, running bool, exitCode int) *Event {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	now := m.now()
 	k := key(project, service)
 	prev, exists := m.states[k]
 
 	newState := ServiceState{
 		Running:   running,
 		ExitCode:  exitCode,
-		Timestamp: time.Now(),
+		Timestamp: now,
 	}
 	m.states[k] = newState
 
+	// A stopped service has to pass its probe again after restarting
+	if !running {
+		delete(m.ready, k)
+	}
+
 	if !exists {
 		// First time seeing this service
 		if running {
@@ -99,13 +168,9 @@ func (m *Monitor) UpdateService(project, service string, running bool, exitCode
 				Type:      EventServiceStarted,
 				Project:   project,
 				Service:   service,
-				Timestamp: time.Now(),
-			}
-			// Non-blocking send
-			select {
-			case m.events <- event:
-			default:
+				Timestamp: now,
 			}
+			m.send(event)
 			return &event
 		}
 		return nil
@@ -113,45 +178,143 @@ func (m *Monitor) UpdateService(project, service string, running bool, exitCode
 
 	// Check for state transitions
 	var event *Event
-	if prev.Running && !running {
-		// Was running, now stopped
-		if exitCode != 0 {
-			event = &Event{
-				Type:      EventServiceCrashed,
-				Project:   project,
-				Service:   service,
-				ExitCode:  exitCode,
-				Timestamp: time.Now(),
-			}
-		} else {
-			event = &Event{
-				Type:      EventServiceStopped,
-				Project:   project,
-				Service:   service,
-				Timestamp: time.Now(),
-			}
-		}
-	} else if !prev.Running && running {
+	if prev.Running != running {
+		event = stateEvent(project, service, newState)
+	}
+
+	if m.flapThreshold > 0 {
+		event = m.detectFlapping(k, project, service, newState, event)
+	}
+
+	if event != nil {
+		m.send(*event)
+	}
+
+	return event
+}
+
+// stateEvent returns the event describing a service's current state.
+func stateEvent(project, service string, state ServiceState) *Event {
+	event := &Event{
+		Project:   project,
+		Service:   service,
+		Timestamp: state.Timestamp,
+	}
+	switch {
+	case state.Running:
 		// Was stopped, now running
-		event = &Event{
-			Type:      EventServiceRecovered,
-			Project:   project,
-			Service:   service,
-			Timestamp: time.Now(),
+		event.Type = EventServiceRecovered
+	case state.ExitCode != 0:
+		// Was running, now stopped with an error
+		event.Type = EventServiceCrashed
+		event.ExitCode = state.ExitCode
+	default:
+		event.Type = EventServiceStopped
+	}
+	return event
+}
+
+// detectFlapping records a transition (if event is non-nil) and returns the
+// event to emit after applying flap detection. Caller must hold the lock.
+func (m *Monitor) detectFlapping(k, project, service string, state ServiceState, event *Event) *Event {
+	f, ok := m.flaps[k]
+	if !ok {
+		f = &flapState{}
+		m.flaps[k] = f
+	}
+	now := state.Timestamp
+
+	if event == nil {
+		// No transition: a flapping service settles once quiet for the window
+		if f.flapping && len(f.transitions) > 0 && now.Sub(f.transitions[len(f.transitions)-1]) >= m.flapWindow {
+			f.flapping = false
+			f.transitions = nil
+			event := stateEvent(project, service, state)
+			event.Settled = true
+			return event
 		}
+		return nil
 	}
 
-	if event != nil {
-		// Non-blocking send
-		select {
-		case m.events <- *event:
-		default:
+	// Keep only transitions inside the window
+	recent := f.transitions[:0]
+	for _, t := range f.transitions {
+		if now.Sub(t) < m.flapWindow {
+			recent = append(recent, t)
 		}
 	}
+	f.transitions = append(recent, now)
 
+	if f.flapping {
+		event.Suppressed = true
+		return event
+	}
+	if len(f.transitions) >= m.flapThreshold {
+		f.flapping = true
+		return &Event{
+			Type:      EventServiceFlapping,
+			Project:   project,
+			Service:   service,
+			ExitCode:  event.ExitCode,
+			Timestamp: now,
+		}
+	}
 	return event
 }
 
+// send delivers an event to the events channel without blocking.
+func (m *Monitor) send(event Event) {
+	select {
+	case m.events <- event:
+	default:
+	}
+}
+
+// UpdateReadiness records a readiness probe result for a service and returns
+// an event when readiness changes. A service that has never passed its probe
+// is still starting, so a first failing result emits nothing.
+func (m *Monitor) UpdateReadiness(project, service string, ready bool) *Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := key(project, service)
+	prev, known := m.ready[k]
+	m.ready[k] = ready
+
+	if (known && prev == ready) || (!known && !ready) {
+		return nil
+	}
+
+	event := Event{
+		Type:      EventServiceReady,
+		Project:   project,
+		Service:   service,
+		Timestamp: m.now(),
+	}
+	if !ready {
+		event.Type = EventServiceNotReady
+	}
+	m.send(event)
+	return &event
+}
+
+// Readiness returns the last probe result for a service and whether the
+// service has been probed since it last started.
+func (m *Monitor) Readiness(project, service string) (ready, probed bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ready, probed = m.ready[key(project, service)]
+	return ready, probed
+}
+
+// IsFlapping reports whether a service is currently marked as flapping.
+func (m *Monitor) IsFlapping(project, service string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	f, ok := m.flaps[key(project, service)]
+	return ok && f.flapping
+}
+
 // GetState returns the current state of a service.
 func (m *Monitor) GetState(project, service string) (ServiceState, bool) {
 	m.mu.RLock()
@@ -165,6 +328,8 @@ func (m *Monitor) ClearStates() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.states = make(map[string]ServiceState)
+	m.flaps = make(map[string]*flapState)
+	m.ready = make(map[string]bool)
 }
 
 // Close shuts down the monitor and closes the events channel.
@@ -162,3 +162,137 @@ func TestMonitorEventsChannel(t *testing.T) {
 
 	m.Close()
 }
+
+func TestMonitorFlapDetection(t *testing.T) {
+	m := NewMonitor(time.Second)
+	defer m.Close()
+	m.SetFlapDetection(3, time.Minute)
+
+	now := time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	step := func(d time.Duration, running bool, exitCode int) *Event {
+		now = now.Add(d)
+		return m.UpdateService("proj", "svc", running, exitCode)
+	}
+
+	step(0, true, 0)
+	if e := step(time.Second, false, 1); e == nil || e.Type != EventServiceCrashed {
+		t.Fatalf("expected crash before flapping, got %+v", e)
+	}
+	if e := step(time.Second, true, 0); e == nil || e.Type != EventServiceRecovered {
+		t.Fatalf("expected recovery before flapping, got %+v", e)
+	}
+
+	// Third transition within the window marks the service as flapping
+	e := step(time.Second, false, 1)
+	if e == nil || e.Type != EventServiceFlapping {
+		t.Fatalf("expected flapping event, got %+v", e)
+	}
+	if !m.IsFlapping("proj", "svc") {
+		t.Error("IsFlapping() should be true")
+	}
+
+	if !e.IsCrash() || e.ExitCode != 1 {
+		t.Errorf("flapping event started by a crash should count as a crash, got %+v", e)
+	}
+
+	// Further transitions are suppressed
+	if e := step(time.Second, true, 0); e == nil || !e.Suppressed || e.Type != EventServiceRecovered {
+		t.Errorf("expected suppressed recovery while flapping, got %+v", e)
+	}
+	if e := step(time.Second, false, 1); e == nil || !e.Suppressed || !e.IsCrash() {
+		t.Errorf("expected suppressed crash while flapping, got %+v", e)
+	}
+
+	// Still within the window since the last transition
+	if e := step(30*time.Second, false, 1); e != nil {
+		t.Errorf("expected no event before settling, got %+v", e)
+	}
+
+	// Quiet for a full window: emit the settled state once
+	e = step(31*time.Second, false, 1)
+	if e == nil || e.Type != EventServiceCrashed || e.ExitCode != 1 || !e.Settled {
+		t.Fatalf("expected settled crash event, got %+v", e)
+	}
+	if e.IsCrash() {
+		t.Error("settled crash was already counted and should not be a new crash")
+	}
+	if m.IsFlapping("proj", "svc") {
+		t.Error("IsFlapping() should be false after settling")
+	}
+	if e := step(time.Second, false, 1); e != nil {
+		t.Errorf("settled state should be emitted only once, got %+v", e)
+	}
+}
+
+func TestMonitorFlapWindowExpires(t *testing.T) {
+	m := NewMonitor(time.Second)
+	defer m.Close()
+	m.SetFlapDetection(3, 10*time.Second)
+
+	now := time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+
+	running := true
+	m.UpdateService("proj", "svc", running, 0)
+	for i := 0; i < 5; i++ {
+		// Transitions spaced wider than the window never accumulate
+		now = now.Add(time.Minute)
+		running = !running
+		e := m.UpdateService("proj", "svc", running, 1)
+		if e == nil || e.Type == EventServiceFlapping {
+			t.Fatalf("transition %d: expected regular event, got %+v", i, e)
+		}
+	}
+}
+
+func TestMonitorFlapDetectionDisabled(t *testing.T) {
+	m := NewMonitor(time.Second)
+	defer m.Close()
+
+	m.UpdateService("proj", "svc", true, 0)
+	for i := 0; i < 10; i++ {
+		e := m.UpdateService("proj", "svc", i%2 == 1, 1)
+		if e == nil || e.Type == EventServiceFlapping {
+			t.Fatalf("expected per-transition events without flap detection, got %+v", e)
+		}
+	}
+}
+
+func TestMonitorReadiness(t *testing.T) {
+	m := NewMonitor(time.Second)
+	defer m.Close()
+
+	m.UpdateService("proj", "web", true, 0)
+
+	// Not ready while starting: no event yet
+	if e := m.UpdateReadiness("proj", "web", false); e != nil {
+		t.Errorf("expected no event for a starting service, got %+v", e)
+	}
+	if ready, probed := m.Readiness("proj", "web"); ready || !probed {
+		t.Errorf("Readiness() = (%v, %v), want (false, true)", ready, probed)
+	}
+
+	if e := m.UpdateReadiness("proj", "web", true); e == nil || e.Type != EventServiceReady {
+		t.Fatalf("expected ready event, got %+v", e)
+	}
+	if e := m.UpdateReadiness("proj", "web", true); e != nil {
+		t.Errorf("unchanged readiness should not emit, got %+v", e)
+	}
+	if e := m.UpdateReadiness("proj", "web", false); e == nil || e.Type != EventServiceNotReady {
+		t.Fatalf("expected not-ready event, got %+v", e)
+	}
+}
+
+func TestMonitorReadinessResetsWhenStopped(t *testing.T) {
+	m := NewMonitor(time.Second)
+	defer m.Close()
+
+	m.UpdateService("proj", "web", true, 0)
+	m.UpdateReadiness("proj", "web", true)
+	m.UpdateService("proj", "web", false, 1)
+
+	if _, probed := m.Readiness("proj", "web"); probed {
+		t.Error("readiness should reset when the service stops")
+	}
+}
@@ -0,0 +1,548 @@
+// Package metrics keeps the CPU, memory and restart history of every
+// service devdash polls. Samples are averaged into buckets, with recent
+// history kept at a fine resolution and older history at a coarser one, and
+// the history is saved so it survives restarts.
+//
+// The history file is a log of JSON lines: a version header, then one line
+// per bucket. Saves append the buckets that changed since the last save; a
+// later line for the same bucket replaces the earlier one. Once the log
+// holds more superseded lines than live ones it is rewritten compactly,
+// keeping the buckets other instances appended.
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/infktd/devdash/internal/fsutil"
+)
+
+const (
+	storeDir    = "devdash"
+	storeFile   = "metrics.jsonl"
+	fileVersion = 2
+)
+
+// Path returns the default metrics history file path.
+func Path() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			stateHome = filepath.Join(".local", "state")
+		} else {
+			stateHome = filepath.Join(home, ".local", "state")
+		}
+	}
+	return filepath.Join(stateHome, storeDir, storeFile)
+}
+
+// tier is a resolution history is kept at, and for how long.
+type tier struct {
+	step      time.Duration
+	retention time.Duration
+}
+
+// tiers go from finest to coarsest. Every sample goes into each tier.
+var tiers = []tier{
+	{step: 5 * time.Second, retention: time.Hour},
+	{step: time.Minute, retention: 24 * time.Hour},
+}
+
+// Sample is one reading of a service.
+type Sample struct {
+	CPU      float64 // Percent of one core
+	Mem      int64   // Bytes
+	Restarts int     // Restarts since process-compose started the service
+}
+
+// Point is the history of a service over one bucket of time: the mean CPU
+// and memory of the samples in it and the highest restart count.
+type Point struct {
+	Time     time.Time // Start of the bucket
+	CPU      float64
+	Mem      int64
+	Restarts int
+}
+
+// bucket accumulates the samples of one step of a tier. Its JSON form is
+// part of a log line.
+type bucket struct {
+	Start    int64   `json:"t"` // Unix seconds
+	Count    int     `json:"n"`
+	CPU      float64 `json:"c"`
+	Mem      float64 `json:"m"`
+	Restarts int     `json:"r"`
+}
+
+func (b *bucket) add(s Sample) {
+	b.Count++
+	b.CPU += (s.CPU - b.CPU) / float64(b.Count)
+	b.Mem += (float64(s.Mem) - b.Mem) / float64(b.Count)
+	b.Restarts = max(b.Restarts, s.Restarts)
+}
+
+func (b bucket) point() Point {
+	return Point{
+		Time:     time.Unix(b.Start, 0),
+		CPU:      b.CPU,
+		Mem:      int64(b.Mem),
+		Restarts: b.Restarts,
+	}
+}
+
+// series is the history of one service, with a run of buckets per tier in
+// time order.
+type series struct {
+	Project string
+	Service string
+	Tiers   [][]bucket
+
+	// saved holds, per tier, the start of the last bucket in the log. That
+	// bucket may have taken more samples since, so it is written again.
+	saved []int64
+	dirty bool // Recorded since the last save
+}
+
+func newSeries(project, service string) *series {
+	return &series{
+		Project: project,
+		Service: service,
+		Tiers:   make([][]bucket, len(tiers)),
+		saved:   make([]int64, len(tiers)),
+	}
+}
+
+// record adds a sample taken at at to every tier, dropping buckets that
+// aged out. Samples older than a tier's latest bucket are ignored.
+func (s *series) record(at time.Time, sample Sample) {
+	for i, t := range tiers {
+		start := at.Truncate(t.step).Unix()
+		buckets := s.Tiers[i]
+		switch n := len(buckets); {
+		case n > 0 && buckets[n-1].Start == start:
+			buckets[n-1].add(sample)
+		case n == 0 || buckets[n-1].Start < start:
+			b := bucket{Start: start}
+			b.add(sample)
+			buckets = append(buckets, b)
+		}
+		s.Tiers[i] = expire(buckets, at.Add(-t.retention))
+	}
+	s.dirty = true
+}
+
+// restore puts a bucket read from the log into tier i. A bucket already
+// there with the same start is replaced by the newer copy.
+func (s *series) restore(i int, b bucket) {
+	buckets := s.Tiers[i]
+	switch n := len(buckets); {
+	case n > 0 && buckets[n-1].Start == b.Start:
+		buckets[n-1] = b
+	case n == 0 || buckets[n-1].Start < b.Start:
+		buckets = append(buckets, b)
+	}
+	s.Tiers[i] = buckets
+	s.saved[i] = buckets[len(buckets)-1].Start
+}
+
+// insert adds a bucket to tier i in time order, unless one with the same
+// start is there already.
+func (s *series) insert(i int, b bucket) {
+	buckets := s.Tiers[i]
+	j, found := slices.BinarySearchFunc(buckets, b.Start, func(b bucket, start int64) int {
+		return cmp.Compare(b.Start, start)
+	})
+	if !found {
+		s.Tiers[i] = slices.Insert(buckets, j, b)
+	}
+}
+
+// unsaved calls fn with every bucket not yet in the log, and the bucket
+// that was last written, and marks them saved.
+func (s *series) unsaved(fn func(i int, b bucket)) {
+	for i, buckets := range s.Tiers {
+		from := sort.Search(len(buckets), func(j int) bool {
+			return buckets[j].Start >= s.saved[i]
+		})
+		for _, b := range buckets[from:] {
+			fn(i, b)
+		}
+		if n := len(buckets); n > 0 {
+			s.saved[i] = buckets[n-1].Start
+		}
+	}
+	s.dirty = false
+}
+
+// size returns the number of buckets the series holds.
+func (s *series) size() int {
+	n := 0
+	for _, buckets := range s.Tiers {
+		n += len(buckets)
+	}
+	return n
+}
+
+// expire drops the buckets that started before cutoff.
+func expire(buckets []bucket, cutoff time.Time) []bucket {
+	i := sort.Search(len(buckets), func(i int) bool {
+		return buckets[i].Start >= cutoff.Unix()
+	})
+	return buckets[i:]
+}
+
+// empty reports whether the series has no history left.
+func (s *series) empty() bool {
+	for _, buckets := range s.Tiers {
+		if len(buckets) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// header is the first line of the history file.
+type header struct {
+	Version int `json:"version"`
+}
+
+// entry is a line of the history file: a bucket of one tier of a series.
+type entry struct {
+	Project string `json:"p"`
+	Service string `json:"s"`
+	Tier    int    `json:"i"`
+	bucket
+}
+
+type key struct {
+	project, service string
+}
+
+// Store is the metrics history of all services. It's safe for concurrent
+// use.
+type Store struct {
+	mu     sync.Mutex
+	series map[key]*series
+	path   string // Where Save writes; empty for a store that isn't saved
+
+	// Save writes the file without holding mu, so recording isn't held up
+	saveMu  sync.Mutex
+	lines   int  // Bucket lines in the file
+	rewrite bool // The file is missing or unreadable and must be rewritten
+}
+
+// NewStore returns an empty store that isn't saved.
+func NewStore() *Store {
+	return &Store{series: make(map[key]*series)}
+}
+
+// Load returns the store saved at path, dropping history that aged out.
+// The returned store is usable even when loading fails, and saves to path.
+func Load(path string) (*Store, error) {
+	s := NewStore()
+	s.path = path
+	s.rewrite = true
+
+	series, lines, torn, err := readLog(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	s.series, s.lines, s.rewrite = series, lines, torn
+	s.expire(time.Now())
+	return s, nil
+}
+
+// readLog reads the history file at path. torn reports lines that couldn't
+// be read, such as from a save cut short by a crash, or a file in another
+// format, which reads as empty; either way the file must be rewritten.
+func readLog(path string) (out map[key]*series, lines int, torn bool, err error) {
+	out = make(map[key]*series)
+	f, err := os.Open(path)
+	if err != nil {
+		return out, 0, false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return out, 0, false, scanner.Err()
+	}
+	var head header
+	if err := json.Unmarshal(scanner.Bytes(), &head); err != nil {
+		return out, 0, false, fmt.Errorf("%s: %w", path, err)
+	}
+	if head.Version != fileVersion {
+		return out, 0, true, nil // Start over rather than misread another format
+	}
+
+	for scanner.Scan() {
+		var e entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Tier < 0 || e.Tier >= len(tiers) {
+			torn = true
+			continue
+		}
+		k := key{e.Project, e.Service}
+		sr, ok := out[k]
+		if !ok {
+			sr = newSeries(e.Project, e.Service)
+			out[k] = sr
+		}
+		sr.restore(e.Tier, e.bucket)
+		lines++
+	}
+	return out, lines, torn, scanner.Err()
+}
+
+// expire drops history that aged out by now. Caller must hold mu.
+func (s *Store) expire(now time.Time) {
+	for k, sr := range s.series {
+		for i, t := range tiers {
+			sr.Tiers[i] = expire(sr.Tiers[i], now.Add(-t.retention))
+		}
+		if sr.empty() {
+			delete(s.series, k)
+		}
+	}
+}
+
+// Save writes what was recorded since the last save to the path the store
+// was loaded from, doing nothing if nothing was. Another devdash saving the
+// same file appends to it too, so before compacting the file Save merges in
+// the buckets the other one wrote.
+func (s *Store) Save() error {
+	if s.path == "" {
+		return nil
+	}
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	lock, err := fsutil.Lock(s.path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	compact, dirty := s.pending()
+	if !dirty && !compact {
+		return nil
+	}
+	var disk map[key]*series
+	if compact {
+		// Best effort: what can't be read is lost either way
+		disk, _, _, _ = readLog(s.path)
+	}
+
+	data, n, err := s.unsavedLines(compact, disk)
+	if err != nil {
+		return err
+	}
+	if compact {
+		err = fsutil.WriteFile(s.path, data, 0644)
+	} else {
+		err = appendFile(s.path, data)
+	}
+	if err != nil {
+		// The buckets are marked saved; rewrite them all next time
+		s.rewrite = true
+		return err
+	}
+	if compact {
+		s.lines, s.rewrite = n, false
+	} else {
+		s.lines += n
+	}
+	return nil
+}
+
+// pending reports whether anything was recorded since the last save, and
+// whether the file needs compacting. Caller must hold saveMu.
+func (s *Store) pending() (compact, dirty bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	live := 0
+	for _, sr := range s.series {
+		live += sr.size()
+		dirty = dirty || sr.dirty
+	}
+	return s.rewrite || (dirty && s.lines > 2*live), dirty
+}
+
+// unsavedLines encodes what Save should write: the buckets recorded since
+// the last save, or with compact the whole file, after merging in the
+// buckets of disk that the store doesn't have. Caller must hold saveMu.
+func (s *Store) unsavedLines(compact bool, disk map[key]*series) (data []byte, lines int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var buf bytes.Buffer
+	if compact {
+		s.merge(disk)
+		s.expire(time.Now())
+		if err := json.NewEncoder(&buf).Encode(header{Version: fileVersion}); err != nil {
+			return nil, 0, err
+		}
+		for _, sr := range s.series {
+			clear(sr.saved)
+		}
+	}
+	lines, err = s.encode(&buf, !compact)
+	return buf.Bytes(), lines, err
+}
+
+// merge adds the buckets of other that the store doesn't have. Caller must
+// hold mu.
+func (s *Store) merge(other map[key]*series) {
+	for k, o := range other {
+		sr, ok := s.series[k]
+		if !ok {
+			sr = newSeries(k.project, k.service)
+			s.series[k] = sr
+		}
+		for i, buckets := range o.Tiers {
+			for _, b := range buckets {
+				sr.insert(i, b)
+			}
+		}
+	}
+}
+
+// appendFile appends data to the file at path and syncs it.
+func appendFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// encode writes a line for every unsaved bucket to buf, in a stable order,
+// and returns the number of lines. With onlyDirty, series not recorded
+// since the last save are skipped. Caller must hold mu.
+func (s *Store) encode(buf *bytes.Buffer, onlyDirty bool) (int, error) {
+	keys := make([]key, 0, len(s.series))
+	for k, sr := range s.series {
+		if sr.dirty || !onlyDirty {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		if a.project != b.project {
+			return strings.Compare(a.project, b.project)
+		}
+		return strings.Compare(a.service, b.service)
+	})
+
+	enc := json.NewEncoder(buf)
+	n := 0
+	var err error
+	for _, k := range keys {
+		sr := s.series[k]
+		sr.unsaved(func(i int, b bucket) {
+			if err == nil {
+				err = enc.Encode(entry{Project: sr.Project, Service: sr.Service, Tier: i, bucket: b})
+				n++
+			}
+		})
+	}
+	return n, err
+}
+
+// Record adds a sample of a service taken at at.
+func (s *Store) Record(project, service string, at time.Time, sample Sample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key{project, service}
+	sr, ok := s.series[k]
+	if !ok {
+		sr = newSeries(project, service)
+		s.series[k] = sr
+	}
+	sr.record(at, sample)
+}
+
+// tierFor returns the index of the finest tier that goes back span.
+func tierFor(span time.Duration) int {
+	i := 0
+	for i < len(tiers)-1 && tiers[i].retention < span {
+		i++
+	}
+	return i
+}
+
+// Step returns the time between the points Query returns for span.
+func Step(span time.Duration) time.Duration {
+	return tiers[tierFor(span)].step
+}
+
+// Query returns a service's history over the span before now, oldest first,
+// at the finest resolution that goes back that far.
+func (s *Store) Query(project, service string, span time.Duration, now time.Time) []Point {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sr, ok := s.series[key{project, service}]
+	if !ok {
+		return nil
+	}
+	i := tierFor(span)
+	cutoff := now.Add(-span).Unix()
+	var points []Point
+	for _, b := range sr.Tiers[i] {
+		if b.Start >= cutoff && b.Start <= now.Unix() {
+			points = append(points, b.point())
+		}
+	}
+	return points
+}
+
+// Services returns the services of a project that have history, sorted.
+func (s *Store) Services(project string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var services []string
+	for k, sr := range s.series {
+		if k.project == project && !sr.empty() {
+			services = append(services, k.service)
+		}
+	}
+	slices.Sort(services)
+	return services
+}
+
+// Restarts counts the restarts over a run of points. A count that drops,
+// such as after the project was restarted, starts counting again.
+func Restarts(points []Point) int {
+	total := 0
+	for i := 1; i < len(points); i++ {
+		if d := points[i].Restarts - points[i-1].Restarts; d > 0 {
+			total += d
+		} else if d < 0 {
+			total += points[i].Restarts
+		}
+	}
+	return total
+}
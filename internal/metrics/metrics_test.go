@@ -0,0 +1,220 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// base is aligned to every tier's step.
+var base = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRecordAveragesIntoBuckets(t *testing.T) {
+	s := NewStore()
+	s.Record("api", "db", base, Sample{CPU: 10, Mem: 100, Restarts: 1})
+	s.Record("api", "db", base.Add(2*time.Second), Sample{CPU: 20, Mem: 300, Restarts: 2})
+	s.Record("api", "db", base.Add(5*time.Second), Sample{CPU: 40, Mem: 500, Restarts: 2})
+
+	got := s.Query("api", "db", 5*time.Minute, base.Add(10*time.Second))
+	want := []Point{
+		{Time: time.Unix(base.Unix(), 0), CPU: 15, Mem: 200, Restarts: 2},
+		{Time: time.Unix(base.Unix()+5, 0), CPU: 40, Mem: 500, Restarts: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() = %+v, want %+v", got, want)
+	}
+}
+
+func TestQueryDownsamplesLongRanges(t *testing.T) {
+	s := NewStore()
+	// Two hours of samples every 10 seconds
+	for at := base; at.Before(base.Add(2 * time.Hour)); at = at.Add(10 * time.Second) {
+		s.Record("api", "web", at, Sample{CPU: 1, Mem: 1})
+	}
+	now := base.Add(2*time.Hour - time.Second)
+
+	if n := len(s.Query("api", "web", 5*time.Minute, now)); n < 30 || n > 31 {
+		t.Errorf("5m at 5s buckets = %d points, want about 30", n)
+	}
+	if n := len(s.Query("api", "web", time.Hour, now)); n < 360 || n > 361 {
+		t.Errorf("1h at 5s buckets = %d points, want about 360", n)
+	}
+	// Only an hour of fine buckets is kept, so a day comes from minutes
+	if n := len(s.Query("api", "web", 24*time.Hour, now)); n != 120 {
+		t.Errorf("24h at 1m buckets = %d points, want 120", n)
+	}
+}
+
+func TestRecordExpiresOldHistory(t *testing.T) {
+	s := NewStore()
+	s.Record("api", "web", base, Sample{CPU: 1})
+	s.Record("api", "web", base.Add(25*time.Hour), Sample{CPU: 2})
+
+	got := s.Query("api", "web", 24*time.Hour, base.Add(25*time.Hour))
+	if len(got) != 1 || got[0].CPU != 2 {
+		t.Errorf("Query() = %+v, want only the recent sample", got)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file: %v", err)
+	}
+	now := time.Now()
+	s.Record("api", "web", now, Sample{CPU: 5, Mem: 1024, Restarts: 1})
+	s.Record("api", "db", now, Sample{CPU: 1})
+	s.Record("blog", "hugo", now.Add(-48*time.Hour), Sample{CPU: 1}) // Ages out
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Services("api"); !reflect.DeepEqual(got, []string{"db", "web"}) {
+		t.Errorf("Services(api) = %v", got)
+	}
+	if got := loaded.Services("blog"); got != nil {
+		t.Errorf("expired history should be dropped, got %v", got)
+	}
+	points := loaded.Query("api", "web", 5*time.Minute, now)
+	if len(points) != 1 || points[0].Mem != 1024 || points[0].Restarts != 1 {
+		t.Errorf("Query() after load = %+v", points)
+	}
+}
+
+func TestSaveAppendsOnlyChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	s, _ := Load(path)
+	now := time.Now().Truncate(time.Minute)
+	for i := 0; i < 60; i++ {
+		s.Record("api", "web", now.Add(time.Duration(i)*time.Second), Sample{CPU: 1})
+		s.Record("api", "db", now.Add(time.Duration(i)*time.Second), Sample{CPU: 1})
+	}
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	first, _ := os.ReadFile(path)
+
+	// Nothing recorded: nothing written
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if again, _ := os.ReadFile(path); len(again) != len(first) {
+		t.Errorf("an idle save should not touch the file (%d -> %d bytes)", len(first), len(again))
+	}
+
+	// Only the recorded service is appended, not the whole history
+	s.Record("api", "web", now.Add(61*time.Second), Sample{CPU: 3})
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	after, _ := os.ReadFile(path)
+	appended := string(after[len(first):])
+	if string(after[:len(first)]) != string(first) {
+		t.Fatal("a save should append to the file")
+	}
+	if lines := strings.Count(appended, "\n"); lines > 4 || strings.Contains(appended, `"db"`) {
+		t.Errorf("appended more than the changed buckets:\n%s", appended)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := loaded.Query("api", "web", 5*time.Minute, now.Add(2*time.Minute))
+	want := s.Query("api", "web", 5*time.Minute, now.Add(2*time.Minute))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() after load = %+v, want %+v", got, want)
+	}
+}
+
+func TestSaveCompactsSupersededLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	s, _ := Load(path)
+	now := time.Now().Truncate(time.Minute)
+	// Every save rewrites the open minute bucket
+	for i := 0; i < 50; i++ {
+		s.Record("api", "web", now.Add(time.Duration(i)*time.Second), Sample{CPU: float64(i)})
+		if err := s.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	if lines := strings.Count(string(data), "\n"); lines > 2*(s.series[key{"api", "web"}].size())+1+4 {
+		t.Errorf("log should be compacted, has %d lines", lines)
+	}
+	loaded, _ := Load(path)
+	got := loaded.Query("api", "web", 5*time.Minute, now.Add(time.Minute))
+	want := s.Query("api", "web", 5*time.Minute, now.Add(time.Minute))
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Query() after load = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompactionKeepsOtherInstancesLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	mine, _ := Load(path)
+	other, _ := Load(path)
+	now := time.Now().Truncate(time.Minute)
+
+	mine.Record("api", "web", now, Sample{CPU: 1})
+	if err := mine.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	other.Record("blog", "hugo", now, Sample{CPU: 2})
+	if err := other.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// Enough saves of the open bucket to compact the log
+	for i := 1; i < 20; i++ {
+		mine.Record("api", "web", now.Add(time.Duration(i)*time.Second), Sample{CPU: 1})
+		if err := mine.Save(); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := loaded.Query("blog", "hugo", 5*time.Minute, now.Add(time.Minute)); len(got) != 1 || got[0].CPU != 2 {
+		t.Errorf("another instance's history should survive compaction, got %+v", got)
+	}
+	if got := loaded.Query("api", "web", 5*time.Minute, now.Add(time.Minute)); len(got) == 0 {
+		t.Error("own history should survive compaction")
+	}
+}
+
+func TestLoadCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err == nil {
+		t.Error("Load() of a corrupt file should report it")
+	}
+	s.Record("api", "web", time.Now(), Sample{})
+	if err := s.Save(); err != nil {
+		t.Errorf("a store that failed to load should still save: %v", err)
+	}
+}
+
+func TestRestarts(t *testing.T) {
+	points := []Point{{Restarts: 2}, {Restarts: 2}, {Restarts: 4}, {Restarts: 1}, {Restarts: 1}}
+	if got := Restarts(points); got != 3 {
+		t.Errorf("Restarts() = %d, want 3", got)
+	}
+	if got := Restarts(nil); got != 0 {
+		t.Errorf("Restarts(nil) = %d", got)
+	}
+}
@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+)
+
+// hookTimeout bounds how long a single backend may take to deliver an event.
+var hookTimeout = 10 * time.Second
+
+// Backend delivers health events to a notification target.
+type Backend interface {
+	Send(ctx context.Context, event health.Event) error
+}
+
+// Send delivers an event as a desktop notification, so Notifier can be used
+// as a Backend.
+func (n *Notifier) Send(ctx context.Context, event health.Event) error {
+	switch event.Type {
+	case health.EventServiceCrashed:
+		return n.ServiceCrashed(event.Project, event.Service, event.ExitCode)
+	case health.EventServiceRecovered:
+		return n.ServiceRecovered(event.Project, event.Service)
+	case health.EventServiceFlapping:
+		return n.ServiceFlapping(event.Project, event.Service)
+	default:
+		return nil
+	}
+}
+
+// WebhookPayload is the JSON body posted by WebhookBackend.
+type WebhookPayload struct {
+	Project   string    `json:"project"`
+	Service   string    `json:"service"`
+	Event     string    `json:"event"`
+	ExitCode  int       `json:"exit_code"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookBackend posts events as JSON to an HTTP endpoint.
+type WebhookBackend struct {
+	URL     string
+	Headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookBackend creates a webhook backend for the given URL.
+func NewWebhookBackend(url string, headers map[string]string) *WebhookBackend {
+	return &WebhookBackend{
+		URL:     url,
+		Headers: headers,
+		client:  &http.Client{Timeout: hookTimeout},
+	}
+}
+
+// Send posts the event to the webhook URL.
+func (w *WebhookBackend) Send(ctx context.Context, event health.Event) error {
+	body, err := json.Marshal(WebhookPayload{
+		Project:   event.Project,
+		Service:   event.Service,
+		Event:     event.Type.String(),
+		ExitCode:  event.ExitCode,
+		Timestamp: event.Timestamp,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: unexpected status %d", w.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// CommandBackend runs a shell command for each event. The event is passed in
+// the DEVDASH_PROJECT, DEVDASH_SERVICE, DEVDASH_EVENT, DEVDASH_EXIT_CODE and
+// DEVDASH_TIMESTAMP environment variables.
+type CommandBackend struct {
+	Command string
+}
+
+// NewCommandBackend creates a backend that runs command via sh -c.
+func NewCommandBackend(command string) *CommandBackend {
+	return &CommandBackend{Command: command}
+}
+
+// Send runs the command with the event in its environment.
+func (c *CommandBackend) Send(ctx context.Context, event health.Event) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Env = append(os.Environ(),
+		"DEVDASH_PROJECT="+event.Project,
+		"DEVDASH_SERVICE="+event.Service,
+		"DEVDASH_EVENT="+event.Type.String(),
+		"DEVDASH_EXIT_CODE="+strconv.Itoa(event.ExitCode),
+		"DEVDASH_TIMESTAMP="+event.Timestamp.Format(time.RFC3339),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("command %q: %v: %s", c.Command, err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// hookRoute pairs a backend with the event types it receives.
+type hookRoute struct {
+	backend Backend
+	events  []string
+}
+
+// Hooks fans events out to the webhook and command backends configured
+// under notifications.
+type Hooks struct {
+	routes []hookRoute
+}
+
+// NewHooks creates hooks for the configured webhooks and commands.
+func NewHooks(cfg config.NotificationsConfig) *Hooks {
+	h := &Hooks{}
+	for _, wh := range cfg.Webhooks {
+		h.Add(NewWebhookBackend(wh.URL, wh.Headers), wh.Events)
+	}
+	for _, c := range cfg.Commands {
+		h.Add(NewCommandBackend(c.Command), c.Events)
+	}
+	return h
+}
+
+// Add registers a backend for the given event types (all types if empty).
+func (h *Hooks) Add(backend Backend, events []string) {
+	h.routes = append(h.routes, hookRoute{backend: backend, events: events})
+}
+
+// Len returns the number of registered backends.
+func (h *Hooks) Len() int {
+	return len(h.routes)
+}
+
+// Send delivers the event to every backend subscribed to its type and
+// returns the combined delivery errors. Backends are sent to concurrently,
+// each with its own timeout, so a slow one can't hold up the others.
+func (h *Hooks) Send(ctx context.Context, event health.Event) error {
+	errs := make([]error, len(h.routes))
+	var wg sync.WaitGroup
+	for i, r := range h.routes {
+		if len(r.events) > 0 && !containsEvent(r.events, event.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+			defer cancel()
+			errs[i] = r.backend.Send(ctx, event)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
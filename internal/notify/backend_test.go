@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+)
+
+var crashEvent = health.Event{
+	Type:      health.EventServiceCrashed,
+	Project:   "api",
+	Service:   "postgres",
+	ExitCode:  137,
+	Timestamp: time.Date(2026, 1, 20, 14, 30, 0, 0, time.UTC),
+}
+
+func TestWebhookBackendPostsPayload(t *testing.T) {
+	var got WebhookPayload
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	backend := NewWebhookBackend(server.URL, map[string]string{"Authorization": "Bearer token"})
+	if err := backend.Send(context.Background(), crashEvent); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	want := WebhookPayload{
+		Project:   "api",
+		Service:   "postgres",
+		Event:     "crashed",
+		ExitCode:  137,
+		Timestamp: crashEvent.Timestamp,
+	}
+	if !got.Timestamp.Equal(want.Timestamp) {
+		t.Errorf("timestamp = %v, want %v", got.Timestamp, want.Timestamp)
+	}
+	got.Timestamp = want.Timestamp
+	if got != want {
+		t.Errorf("payload = %+v, want %+v", got, want)
+	}
+	if auth != "Bearer token" {
+		t.Errorf("Authorization header = %q", auth)
+	}
+}
+
+func TestWebhookBackendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewWebhookBackend(server.URL, nil).Send(context.Background(), crashEvent)
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestCommandBackendEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "event.txt")
+	backend := NewCommandBackend(`echo "$DEVDASH_PROJECT $DEVDASH_SERVICE $DEVDASH_EVENT $DEVDASH_EXIT_CODE $DEVDASH_TIMESTAMP" > ` + out)
+	if err := backend.Send(context.Background(), crashEvent); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("command did not run: %v", err)
+	}
+	want := "api postgres crashed 137 2026-01-20T14:30:00Z\n"
+	if string(data) != want {
+		t.Errorf("command saw %q, want %q", data, want)
+	}
+}
+
+func TestCommandBackendFailure(t *testing.T) {
+	err := NewCommandBackend("echo boom >&2; exit 3").Send(context.Background(), crashEvent)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected error with command output, got %v", err)
+	}
+}
+
+func TestHooksEventFilter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	hooks := NewHooks(config.NotificationsConfig{
+		Webhooks: []config.WebhookConfig{{URL: server.URL, Events: []string{"crashed"}}},
+	})
+	if hooks.Len() != 1 {
+		t.Fatalf("expected 1 backend, got %d", hooks.Len())
+	}
+
+	recovered := crashEvent
+	recovered.Type = health.EventServiceRecovered
+	if err := hooks.Send(context.Background(), recovered); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if err := hooks.Send(context.Background(), crashEvent); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("webhook received %d requests, want 1", requests)
+	}
+}
+
+func TestHooksJoinsErrors(t *testing.T) {
+	hooks := NewHooks(config.NotificationsConfig{
+		Commands: []config.CommandHookConfig{{Command: "exit 1"}, {Command: "exit 2"}},
+	})
+	err := hooks.Send(context.Background(), crashEvent)
+	if err == nil {
+		t.Fatal("expected error from failing commands")
+	}
+	if !strings.Contains(err.Error(), "exit 1") || !strings.Contains(err.Error(), "exit 2") {
+		t.Errorf("expected both failures in error, got %v", err)
+	}
+}
+
+// backendFunc adapts a function to Backend.
+type backendFunc func(ctx context.Context, event health.Event) error
+
+func (f backendFunc) Send(ctx context.Context, event health.Event) error { return f(ctx, event) }
+
+func TestHooksSlowBackendDoesNotStarveOthers(t *testing.T) {
+	defer func(d time.Duration) { hookTimeout = d }(hookTimeout)
+	hookTimeout = 100 * time.Millisecond
+
+	hooks := &Hooks{}
+	hooks.Add(backendFunc(func(ctx context.Context, event health.Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), nil)
+	delivered := make(chan error, 1)
+	hooks.Add(backendFunc(func(ctx context.Context, event health.Event) error {
+		delivered <- ctx.Err()
+		return nil
+	}), nil)
+
+	err := hooks.Send(context.Background(), crashEvent)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the slow backend to time out, got %v", err)
+	}
+	if err := <-delivered; err != nil {
+		t.Errorf("second backend got an expired context: %v", err)
+	}
+}
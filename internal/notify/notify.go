@@ -46,6 +46,16 @@ func (n *Notifier) ServiceRecovered(project, service string) error {
 	return beeep.Notify(title, body, "")
 }
 
+// ServiceFlapping sends a notification for a service stuck in a crash loop.
+func (n *Notifier) ServiceFlapping(project, service string) error {
+	if !n.enabled {
+		return nil
+	}
+	title := "acidBurn: Service Flapping"
+	body := fmt.Sprintf("%s in %s keeps crashing and restarting", service, project)
+	return beeep.Alert(title, body, "")
+}
+
 // ProjectStarted sends a notification when a project starts.
 func (n *Notifier) ProjectStarted(project string) error {
 	if !n.enabled {
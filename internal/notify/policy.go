@@ -0,0 +1,91 @@
+package notify
+
+import (
+	"strings"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+)
+
+// Decision describes where a health event should be surfaced.
+type Decision struct {
+	TUI    bool // Show an in-app toast
+	System bool // Send a desktop notification
+	Hooks  bool // Deliver to webhook and command backends
+}
+
+// Policy resolves notification settings for health events from the global
+// config and its per-project and per-service overrides.
+type Policy struct {
+	cfg config.NotificationsConfig
+}
+
+// NewPolicy creates a policy for the given notification settings.
+func NewPolicy(cfg config.NotificationsConfig) *Policy {
+	return &Policy{cfg: cfg}
+}
+
+// Evaluate decides how an event should be notified. When several overrides
+// match, the most specific one wins: exact names beat globs, globs beat an
+// omitted field or "*", and ties go to the override listed first.
+func (p *Policy) Evaluate(event health.Event) Decision {
+	d := Decision{
+		TUI:    p.cfg.TUIAlerts,
+		System: p.cfg.SystemEnabled,
+		Hooks:  true,
+	}
+	criticalOnly := p.cfg.CriticalOnly
+	var events []string
+
+	if o := p.match(event.Project, event.Service); o != nil {
+		// Overrides can only narrow the global switch, never re-enable it
+		if o.System != nil {
+			d.System = d.System && *o.System
+		}
+		criticalOnly = criticalOnly || o.CriticalOnly
+		events = o.Events
+	}
+
+	if criticalOnly && !IsCritical(event.Type) {
+		return Decision{}
+	}
+	if len(events) > 0 && !containsEvent(events, event.Type) {
+		return Decision{}
+	}
+	return d
+}
+
+// IsCritical reports whether an event type is delivered in critical-only mode.
+func IsCritical(t health.EventType) bool {
+	return t == health.EventServiceCrashed || t == health.EventServiceFlapping
+}
+
+// match returns the most specific override for a project and service.
+func (p *Policy) match(project, service string) *config.NotificationOverride {
+	var best *config.NotificationOverride
+	bestScore := -1
+	for i := range p.cfg.Overrides {
+		o := &p.cfg.Overrides[i]
+		svcScore, ok := config.MatchScore(o.Service, service)
+		if !ok {
+			continue
+		}
+		projScore, ok := config.MatchScore(o.Project, project)
+		if !ok {
+			continue
+		}
+		if score := svcScore + projScore; score > bestScore {
+			best, bestScore = o, score
+		}
+	}
+	return best
+}
+
+func containsEvent(events []string, t health.EventType) bool {
+	for _, e := range events {
+		if strings.EqualFold(e, t.String()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+)
+
+func event(t health.EventType, project, service string) health.Event {
+	return health.Event{Type: t, Project: project, Service: service}
+}
+
+func TestPolicyGlobalDefaults(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{SystemEnabled: true, TUIAlerts: true})
+
+	d := p.Evaluate(event(health.EventServiceCrashed, "api", "web"))
+	if !d.TUI || !d.System {
+		t.Errorf("crash should notify everywhere, got %+v", d)
+	}
+
+	p = NewPolicy(config.NotificationsConfig{SystemEnabled: false, TUIAlerts: true})
+	d = p.Evaluate(event(health.EventServiceCrashed, "api", "web"))
+	if !d.TUI || d.System {
+		t.Errorf("system notifications disabled globally, got %+v", d)
+	}
+}
+
+func TestPolicyCriticalOnly(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{SystemEnabled: true, TUIAlerts: true, CriticalOnly: true})
+
+	if d := p.Evaluate(event(health.EventServiceRecovered, "api", "web")); d.TUI || d.System || d.Hooks {
+		t.Errorf("recovery should be suppressed in critical-only mode, got %+v", d)
+	}
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "web")); !d.System || !d.Hooks {
+		t.Errorf("crash should still notify in critical-only mode, got %+v", d)
+	}
+}
+
+func TestPolicyServiceOverride(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{
+		SystemEnabled: true,
+		TUIAlerts:     true,
+		Overrides: []config.NotificationOverride{
+			{Service: "postgres", System: boolPtr(false)},
+			{Service: "redis*", System: boolPtr(true), CriticalOnly: true},
+		},
+	})
+
+	d := p.Evaluate(event(health.EventServiceCrashed, "api", "postgres"))
+	if d.System || !d.TUI {
+		t.Errorf("postgres override should silence desktop only, got %+v", d)
+	}
+
+	if d := p.Evaluate(event(health.EventServiceRecovered, "api", "redis-cache")); d.TUI || d.System {
+		t.Errorf("redis glob override is critical-only, got %+v", d)
+	}
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "redis-cache")); !d.System {
+		t.Errorf("redis crash should notify, got %+v", d)
+	}
+
+	if d := p.Evaluate(event(health.EventServiceRecovered, "api", "web")); !d.System {
+		t.Errorf("unmatched service should use global settings, got %+v", d)
+	}
+}
+
+func TestPolicyMostSpecificWins(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{
+		SystemEnabled: true,
+		TUIAlerts:     true,
+		Overrides: []config.NotificationOverride{
+			{Service: "*", System: boolPtr(false)},
+			{Project: "api", Service: "db*", System: boolPtr(false)},
+			{Project: "api", Service: "db", System: boolPtr(true)},
+		},
+	})
+
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "db")); !d.System {
+		t.Errorf("exact project and service override should win, got %+v", d)
+	}
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "db-replica")); d.System {
+		t.Errorf("project glob override should apply, got %+v", d)
+	}
+	if d := p.Evaluate(event(health.EventServiceCrashed, "web", "db")); d.System {
+		t.Errorf("catch-all override should apply to other projects, got %+v", d)
+	}
+}
+
+func TestPolicyProjectOverride(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{
+		SystemEnabled: true,
+		TUIAlerts:     true,
+		Overrides: []config.NotificationOverride{
+			{Project: "sandbox-*", System: boolPtr(false)},
+		},
+	})
+
+	if d := p.Evaluate(event(health.EventServiceCrashed, "sandbox-1", "web")); d.System {
+		t.Errorf("project override should silence desktop, got %+v", d)
+	}
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "web")); !d.System {
+		t.Errorf("other projects should be unaffected, got %+v", d)
+	}
+}
+
+func TestPolicyEventFilter(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{
+		SystemEnabled: true,
+		TUIAlerts:     true,
+		Overrides: []config.NotificationOverride{
+			{Service: "worker", System: boolPtr(true), Events: []string{"recovered"}},
+		},
+	})
+
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "worker")); d.TUI || d.System {
+		t.Errorf("crash is not in the event filter, got %+v", d)
+	}
+	if d := p.Evaluate(event(health.EventServiceRecovered, "api", "worker")); !d.TUI || !d.System {
+		t.Errorf("recovery is in the event filter, got %+v", d)
+	}
+}
+
+func TestPolicyOverrideWithoutSystemKeepsGlobal(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{
+		SystemEnabled: true,
+		TUIAlerts:     true,
+		Overrides:     []config.NotificationOverride{{Service: "api", Events: []string{"crashed"}}},
+	})
+	if d := p.Evaluate(event(health.EventServiceCrashed, "web", "api")); !d.System {
+		t.Error("override that only filters events should keep desktop notifications")
+	}
+}
+
+func TestPolicyOverrideCannotEnableSystem(t *testing.T) {
+	p := NewPolicy(config.NotificationsConfig{
+		SystemEnabled: false,
+		TUIAlerts:     true,
+		Overrides:     []config.NotificationOverride{{Service: "web", System: boolPtr(true)}},
+	})
+	if d := p.Evaluate(event(health.EventServiceCrashed, "api", "web")); d.System {
+		t.Errorf("global system switch should take precedence, got %+v", d)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
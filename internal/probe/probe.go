@@ -0,0 +1,143 @@
+// Package probe implements readiness checks for services.
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/infktd/devdash/internal/config"
+)
+
+// DefaultTimeout bounds a probe when the config sets no timeout.
+const DefaultTimeout = 2 * time.Second
+
+// Prober checks whether a service is ready.
+type Prober interface {
+	Probe(ctx context.Context) error
+}
+
+// TCP is ready when a connection to Address succeeds.
+type TCP struct {
+	Address string
+}
+
+// Probe dials the address.
+func (t TCP) Probe(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", t.Address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// HTTP is ready when a GET of URL returns a 2xx status.
+type HTTP struct {
+	URL string
+}
+
+// Probe requests the URL.
+func (h HTTP) Probe(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.URL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("GET %s: status %d", h.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// Command is ready when the shell command exits 0.
+type Command struct {
+	Command string
+	Dir     string
+}
+
+// Probe runs the command via sh -c in Dir.
+func (c Command) Probe(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Dir = c.Dir
+	// Don't wait on output held open by children after a timeout kill
+	cmd.WaitDelay = 100 * time.Millisecond
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	return nil
+}
+
+// ErrNoProbe is returned by New when a probe config sets no check.
+var ErrNoProbe = errors.New("probe has no tcp, http or command check")
+
+// New creates the prober described by cfg. Commands run in dir.
+func New(cfg config.ProbeConfig, dir string) (Prober, error) {
+	kinds := 0
+	var p Prober
+	if cfg.TCP != "" {
+		kinds++
+		p = TCP{Address: cfg.TCP}
+	}
+	if cfg.HTTP != "" {
+		kinds++
+		p = HTTP{URL: cfg.HTTP}
+	}
+	if cfg.Command != "" {
+		kinds++
+		p = Command{Command: cfg.Command, Dir: dir}
+	}
+	switch kinds {
+	case 0:
+		return nil, ErrNoProbe
+	case 1:
+		return p, nil
+	default:
+		return nil, fmt.Errorf("probe for %q sets more than one of tcp, http and command", cfg.Service)
+	}
+}
+
+// For returns the most specific probe config matching a service, or nil.
+// Ties go to the probe listed first.
+func For(probes []config.ProbeConfig, project, service string) *config.ProbeConfig {
+	var best *config.ProbeConfig
+	bestScore := -1
+	for i := range probes {
+		p := &probes[i]
+		svcScore, ok := config.MatchScore(p.Service, service)
+		if !ok {
+			continue
+		}
+		projScore, ok := config.MatchScore(p.Project, project)
+		if !ok {
+			continue
+		}
+		if score := svcScore + projScore; score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	return best
+}
+
+// Run executes the probe described by cfg with its timeout.
+func Run(ctx context.Context, cfg config.ProbeConfig, dir string) error {
+	p, err := New(cfg, dir)
+	if err != nil {
+		return err
+	}
+	timeout := DefaultTimeout
+	if cfg.Timeout > 0 {
+		timeout = time.Duration(cfg.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return p.Probe(ctx)
+}
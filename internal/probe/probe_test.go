@@ -0,0 +1,98 @@
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/infktd/devdash/internal/config"
+)
+
+func TestTCPProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	addr := listener.Addr().String()
+
+	if err := Run(context.Background(), config.ProbeConfig{TCP: addr}, ""); err != nil {
+		t.Errorf("open port should be ready: %v", err)
+	}
+
+	listener.Close()
+	if err := Run(context.Background(), config.ProbeConfig{TCP: addr}, ""); err == nil {
+		t.Error("closed port should not be ready")
+	}
+}
+
+func TestHTTPProbe(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := config.ProbeConfig{HTTP: server.URL + "/health"}
+	if err := Run(context.Background(), cfg, ""); err != nil {
+		t.Errorf("2xx should be ready: %v", err)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := Run(context.Background(), cfg, ""); err == nil {
+		t.Error("503 should not be ready")
+	}
+}
+
+func TestCommandProbeRunsInDir(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.ProbeConfig{Command: "test -f ready"}
+
+	if err := Run(context.Background(), cfg, dir); err == nil {
+		t.Error("command should fail before the file exists")
+	}
+	os.WriteFile(filepath.Join(dir, "ready"), nil, 0644)
+	if err := Run(context.Background(), cfg, dir); err != nil {
+		t.Errorf("command should succeed in project dir: %v", err)
+	}
+}
+
+func TestCommandProbeTimeout(t *testing.T) {
+	cfg := config.ProbeConfig{Command: "sleep 5", Timeout: 1}
+	if err := Run(context.Background(), cfg, ""); err == nil {
+		t.Error("slow command should time out")
+	}
+}
+
+func TestNewRejectsInvalidConfig(t *testing.T) {
+	if _, err := New(config.ProbeConfig{Service: "web"}, ""); err != ErrNoProbe {
+		t.Errorf("expected ErrNoProbe, got %v", err)
+	}
+	if _, err := New(config.ProbeConfig{Service: "web", TCP: ":80", HTTP: "http://x"}, ""); err == nil {
+		t.Error("expected error for multiple checks")
+	}
+}
+
+func TestForMostSpecific(t *testing.T) {
+	probes := []config.ProbeConfig{
+		{Service: "*", TCP: "any"},
+		{Service: "web", HTTP: "web"},
+		{Project: "api", Service: "web", Command: "api-web"},
+	}
+
+	if p := For(probes, "api", "web"); p == nil || p.Command != "api-web" {
+		t.Errorf("expected project+service probe, got %+v", p)
+	}
+	if p := For(probes, "shop", "web"); p == nil || p.HTTP != "web" {
+		t.Errorf("expected service probe, got %+v", p)
+	}
+	if p := For(probes, "shop", "db"); p == nil || p.TCP != "any" {
+		t.Errorf("expected catch-all probe, got %+v", p)
+	}
+	if p := For(nil, "shop", "db"); p != nil {
+		t.Errorf("expected no probe, got %+v", p)
+	}
+}
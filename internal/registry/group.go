@@ -0,0 +1,220 @@
+package registry
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DefaultGroupTimeout is how long a group waits for each project to be
+// running when Wait is set and Timeout isn't.
+const DefaultGroupTimeout = 120 // Seconds
+
+// Group is a named set of projects that run together, such as the repos
+// of one product.
+type Group struct {
+	Name     string              `yaml:"name"`
+	Projects []string            `yaml:"projects"`          // Project paths, in start order
+	After    map[string][]string `yaml:"after,omitempty"`   // Project path -> paths that must start first
+	Wait     bool                `yaml:"wait,omitempty"`    // Wait for each project to be running before starting the next
+	Timeout  int                 `yaml:"timeout,omitempty"` // Seconds to wait per project
+}
+
+// AddGroup creates a group of the projects at paths, or replaces the
+// projects of an existing group with that name.
+func (r *Registry) AddGroup(name string, paths []string) *Group {
+	if g := r.FindGroup(name); g != nil {
+		g.Projects = paths
+		return g
+	}
+	g := &Group{Name: name, Projects: paths}
+	r.Groups = append(r.Groups, g)
+	return g
+}
+
+// FindGroup returns a group by name.
+func (r *Registry) FindGroup(name string) *Group {
+	for _, g := range r.Groups {
+		if g.Name == name {
+			return g
+		}
+	}
+	return nil
+}
+
+// RemoveGroup removes a group from the registry.
+func (r *Registry) RemoveGroup(name string) bool {
+	for i, g := range r.Groups {
+		if g.Name == name {
+			r.Groups = append(r.Groups[:i], r.Groups[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Snapshot returns a registry holding deep copies of g and its projects,
+// for work that runs alongside changes to r such as a reload.
+func (r *Registry) Snapshot(g *Group) (*Registry, *Group) {
+	group := cloneGroup(g)
+	out := &Registry{Groups: []*Group{group}}
+	for _, path := range g.Projects {
+		if p := r.FindByPath(path); p != nil {
+			out.Projects = append(out.Projects, cloneProject(p))
+		}
+	}
+	return out, group
+}
+
+// GroupsOf returns the groups the project at path belongs to.
+func (r *Registry) GroupsOf(path string) []*Group {
+	var groups []*Group
+	for _, g := range r.Groups {
+		if g.Contains(path) {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// Contains reports whether the project at path is in the group.
+func (g *Group) Contains(path string) bool {
+	return slices.Contains(g.Projects, path)
+}
+
+// removeProject drops path from the group and its dependencies.
+func (g *Group) removeProject(path string) {
+	g.Projects = slices.DeleteFunc(g.Projects, func(p string) bool { return p == path })
+	delete(g.After, path)
+	for p, deps := range g.After {
+		g.After[p] = slices.DeleteFunc(deps, func(d string) bool { return d == path })
+	}
+}
+
+// renameProject replaces path with newPath in the group and its
+// dependencies.
+func (g *Group) renameProject(path, newPath string) {
+	rename := func(p string) string {
+		if p == path {
+			return newPath
+		}
+		return p
+	}
+	for i, p := range g.Projects {
+		g.Projects[i] = rename(p)
+	}
+	if deps, ok := g.After[path]; ok {
+		delete(g.After, path)
+		g.After[newPath] = deps
+	}
+	for p, deps := range g.After {
+		for i, d := range deps {
+			deps[i] = rename(d)
+		}
+		g.After[p] = deps
+	}
+}
+
+// StartOrder returns the group's projects in the order to start them:
+// list order, except that a project comes after the projects it starts
+// after. It fails if a dependency isn't in the group or the dependencies
+// form a cycle.
+func (g *Group) StartOrder() ([]string, error) {
+	for p, deps := range g.After {
+		if !g.Contains(p) {
+			return nil, fmt.Errorf("group %s: %s has dependencies but is not in the group", g.Name, p)
+		}
+		for _, dep := range deps {
+			if !g.Contains(dep) {
+				return nil, fmt.Errorf("group %s: %s starts after %s, which is not in the group", g.Name, p, dep)
+			}
+		}
+	}
+
+	var order []string
+	placed := make(map[string]bool)
+	for len(order) < len(g.Projects) {
+		progress := false
+		for _, p := range g.Projects {
+			if placed[p] || !allPlaced(g.After[p], placed) {
+				continue
+			}
+			order = append(order, p)
+			placed[p] = true
+			progress = true
+			break // Restart from the top to keep list order where possible
+		}
+		if !progress {
+			var stuck []string
+			for _, p := range g.Projects {
+				if !placed[p] {
+					stuck = append(stuck, p)
+				}
+			}
+			return nil, fmt.Errorf("group %s: dependency cycle between %s", g.Name, strings.Join(stuck, ", "))
+		}
+	}
+	return order, nil
+}
+
+// StopOrder returns the group's projects in the order to stop them, the
+// reverse of StartOrder.
+func (g *Group) StopOrder() ([]string, error) {
+	order, err := g.StartOrder()
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(order)
+	return order, nil
+}
+
+func allPlaced(paths []string, placed map[string]bool) bool {
+	for _, p := range paths {
+		if !placed[p] {
+			return false
+		}
+	}
+	return true
+}
+
+// AggregateState combines the states of a group's projects: running when
+// all of them are, idle when none are active, and degraded when only some
+// are up.
+func AggregateState(states []ProjectState) ProjectState {
+	if len(states) == 0 {
+		return StateIdle
+	}
+
+	active, running, starting := 0, 0, 0
+	missing, stale := false, false
+	for _, s := range states {
+		if s.IsActive() {
+			active++
+		}
+		switch s {
+		case StateRunning:
+			running++
+		case StateStarting:
+			starting++
+		case StateMissing:
+			missing = true
+		case StateStale:
+			stale = true
+		}
+	}
+
+	switch {
+	case running == len(states):
+		return StateRunning
+	case running+starting == len(states):
+		return StateStarting
+	case active > 0:
+		return StateDegraded
+	case missing:
+		return StateMissing
+	case stale:
+		return StateStale
+	default:
+		return StateIdle
+	}
+}
@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRegistryGroups(t *testing.T) {
+	reg := &Registry{}
+	reg.AddProject("/src/api")
+	reg.AddProject("/src/web")
+
+	g := reg.AddGroup("product", []string{"/src/web", "/src/api"})
+	if reg.FindGroup("product") != g {
+		t.Fatal("FindGroup() should return the added group")
+	}
+	if again := reg.AddGroup("product", []string{"/src/api"}); again != g || len(reg.Groups) != 1 {
+		t.Error("AddGroup() with an existing name should replace its projects")
+	}
+	if got := reg.GroupsOf("/src/api"); len(got) != 1 || got[0] != g {
+		t.Errorf("GroupsOf(/src/api) = %v", got)
+	}
+	if got := reg.GroupsOf("/src/web"); len(got) != 0 {
+		t.Errorf("GroupsOf(/src/web) = %v, want none", got)
+	}
+
+	if !reg.RemoveGroup("product") || reg.FindGroup("product") != nil {
+		t.Error("RemoveGroup() should remove the group")
+	}
+	if reg.RemoveGroup("product") {
+		t.Error("RemoveGroup() of a missing group should return false")
+	}
+}
+
+func TestRemoveProjectLeavesGroups(t *testing.T) {
+	reg := &Registry{}
+	reg.AddProject("/src/api")
+	reg.AddProject("/src/auth")
+	g := reg.AddGroup("product", []string{"/src/api", "/src/auth"})
+	g.After = map[string][]string{"/src/api": {"/src/auth"}}
+
+	reg.RemoveProject("/src/auth")
+	if !reflect.DeepEqual(g.Projects, []string{"/src/api"}) {
+		t.Errorf("group projects = %v", g.Projects)
+	}
+	if _, err := g.StartOrder(); err != nil {
+		t.Errorf("dependencies on a removed project should go too: %v", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	reg := &Registry{}
+	reg.AddProject("/src/api")
+	reg.AddProject("/src/web")
+	reg.AddProject("/src/other")
+	g := reg.AddGroup("product", []string{"/src/api", "/src/web"})
+
+	snap, sg := reg.Snapshot(g)
+	if len(snap.Projects) != 2 || snap.FindGroup("product") != sg {
+		t.Fatalf("snapshot should hold the group and its projects, got %+v", snap)
+	}
+
+	// Changes to the registry don't reach the snapshot
+	reg.FindByPath("/src/api").Name = "renamed"
+	g.Projects[0] = "/src/other"
+	if snap.FindByPath("/src/api").Name != "api" || sg.Projects[0] != "/src/api" {
+		t.Error("snapshot should not share state with the registry")
+	}
+}
+
+func TestGroupStartOrder(t *testing.T) {
+	g := &Group{
+		Name:     "product",
+		Projects: []string{"frontend", "api", "auth", "worker"},
+		After: map[string][]string{
+			"frontend": {"api"},
+			"api":      {"auth"},
+		},
+	}
+
+	order, err := g.StartOrder()
+	if err != nil {
+		t.Fatalf("StartOrder() error: %v", err)
+	}
+	if want := []string{"auth", "api", "frontend", "worker"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("StartOrder() = %v, want %v", order, want)
+	}
+	stop, _ := g.StopOrder()
+	if want := []string{"worker", "frontend", "api", "auth"}; !reflect.DeepEqual(stop, want) {
+		t.Errorf("StopOrder() = %v, want %v", stop, want)
+	}
+
+	g.After["auth"] = []string{"frontend"}
+	if _, err := g.StartOrder(); err == nil {
+		t.Error("StartOrder() should reject a cycle")
+	}
+
+	g.After = map[string][]string{"api": {"elsewhere"}}
+	if _, err := g.StartOrder(); err == nil {
+		t.Error("StartOrder() should reject a dependency outside the group")
+	}
+}
+
+func TestAggregateState(t *testing.T) {
+	tests := []struct {
+		states []ProjectState
+		want   ProjectState
+	}{
+		{nil, StateIdle},
+		{[]ProjectState{StateRunning, StateRunning}, StateRunning},
+		{[]ProjectState{StateRunning, StateStarting}, StateStarting},
+		{[]ProjectState{StateRunning, StateIdle}, StateDegraded},
+		{[]ProjectState{StateDegraded, StateRunning}, StateDegraded},
+		{[]ProjectState{StateIdle, StateIdle}, StateIdle},
+		{[]ProjectState{StateIdle, StateStale}, StateStale},
+		{[]ProjectState{StateStale, StateMissing}, StateMissing},
+	}
+	for _, tt := range tests {
+		if got := AggregateState(tt.states); got != tt.want {
+			t.Errorf("AggregateState(%v) = %v, want %v", tt.states, got, tt.want)
+		}
+	}
+}
+
+func TestGroupsRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+	reg := &Registry{}
+	reg.AddProject("/src/api")
+	g := reg.AddGroup("product", []string{"/src/api"})
+	g.Wait = true
+	g.Timeout = 30
+
+	if err := Save(path, reg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(loaded.Groups) != 1 || !reflect.DeepEqual(loaded.Groups[0], g) {
+		t.Errorf("loaded groups = %+v, want %+v", loaded.Groups, g)
+	}
+}
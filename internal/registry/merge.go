@@ -0,0 +1,189 @@
+package registry
+
+import (
+	"reflect"
+	"slices"
+)
+
+// merge returns disk with the changes made in mine since base applied on
+// top, so concurrent writers keep each other's renames, hides and new
+// projects. Where both sides changed the same field, mine wins. A nil base
+// means mine was never loaded, so none of its projects were deleted on disk.
+func merge(base, mine, disk *Registry) *Registry {
+	if base == nil {
+		base = &Registry{}
+	}
+	out := &Registry{}
+
+	baseProjects := indexProjects(base.Projects)
+	mineProjects := indexProjects(mine.Projects)
+	for _, d := range disk.Projects {
+		k := projectKey(d)
+		b, inBase := baseProjects[k]
+		m, inMine := mineProjects[k]
+		switch {
+		case inMine && inBase:
+			out.Projects = append(out.Projects, mergeProject(b, m, d))
+		case inMine:
+			// Added on both sides
+			out.Projects = append(out.Projects, mergeProject(d, m, d))
+		case !inBase:
+			// Added on disk
+			out.Projects = append(out.Projects, cloneProject(d))
+		}
+		// In base but not mine: deleted here
+	}
+	diskProjects := indexProjects(disk.Projects)
+	for _, m := range mine.Projects {
+		k := projectKey(m)
+		if _, onDisk := diskProjects[k]; onDisk {
+			continue
+		}
+		if _, inBase := baseProjects[k]; !inBase {
+			// Added here; in base but not on disk means deleted on disk
+			out.Projects = append(out.Projects, cloneProject(m))
+		}
+	}
+
+	baseGroups := indexGroups(base.Groups)
+	mineGroups := indexGroups(mine.Groups)
+	for _, d := range disk.Groups {
+		b, inBase := baseGroups[d.Name]
+		m, inMine := mineGroups[d.Name]
+		switch {
+		case inMine && (!inBase || !reflect.DeepEqual(b, m)):
+			out.Groups = append(out.Groups, cloneGroup(m))
+		case inMine || !inBase:
+			out.Groups = append(out.Groups, cloneGroup(d))
+		}
+	}
+	diskGroups := indexGroups(disk.Groups)
+	for _, m := range mine.Groups {
+		_, onDisk := diskGroups[m.Name]
+		_, inBase := baseGroups[m.Name]
+		if !onDisk && !inBase {
+			out.Groups = append(out.Groups, cloneGroup(m))
+		}
+	}
+
+	// Drop group members deleted by either side
+	for _, g := range out.Groups {
+		for _, path := range slices.Clone(g.Projects) {
+			if out.FindByPath(path) == nil {
+				g.removeProject(path)
+			}
+		}
+	}
+	return out
+}
+
+// mergeProject merges one project's fields three ways. LastActive keeps
+// the latest time rather than the latest writer.
+func mergeProject(base, mine, disk *Project) *Project {
+	out := cloneProject(disk)
+	if mine.Path != base.Path {
+		out.Path = mine.Path
+	}
+	if mine.Name != base.Name {
+		out.Name = mine.Name
+	}
+	if mine.Kind != base.Kind || mine.Root != base.Root {
+		out.Kind, out.Root = mine.Kind, mine.Root
+	}
+	if mine.Remote != base.Remote || mine.Fingerprint != base.Fingerprint {
+		out.Remote, out.Fingerprint = mine.Remote, mine.Fingerprint
+	}
+	if mine.Hidden != base.Hidden {
+		out.Hidden = mine.Hidden
+	}
+	if mine.Favorite != base.Favorite {
+		out.Favorite = mine.Favorite
+	}
+	if !slices.Equal(mine.Tags, base.Tags) {
+		out.Tags = slices.Clone(mine.Tags)
+	}
+	if mine.LastActive.After(out.LastActive) {
+		out.LastActive = mine.LastActive
+	}
+	return out
+}
+
+// replaceWith makes r hold other's contents, updating projects and groups
+// in place where they survive so callers' pointers stay valid.
+func (r *Registry) replaceWith(other *Registry) {
+	projects := indexProjects(r.Projects)
+	r.Projects = nil
+	for _, p := range other.Projects {
+		if existing, ok := projects[projectKey(p)]; ok {
+			*existing = *p
+			p = existing
+		}
+		r.Projects = append(r.Projects, p)
+	}
+
+	groups := indexGroups(r.Groups)
+	r.Groups = nil
+	for _, g := range other.Groups {
+		if existing, ok := groups[g.Name]; ok {
+			*existing = *g
+			g = existing
+		}
+		r.Groups = append(r.Groups, g)
+	}
+}
+
+// clone returns a deep copy of the registry's projects and groups.
+func (r *Registry) clone() *Registry {
+	out := &Registry{}
+	for _, p := range r.Projects {
+		out.Projects = append(out.Projects, cloneProject(p))
+	}
+	for _, g := range r.Groups {
+		out.Groups = append(out.Groups, cloneGroup(g))
+	}
+	return out
+}
+
+func cloneProject(p *Project) *Project {
+	c := *p
+	c.Tags = slices.Clone(p.Tags)
+	return &c
+}
+
+func cloneGroup(g *Group) *Group {
+	c := *g
+	c.Projects = slices.Clone(g.Projects)
+	if g.After != nil {
+		c.After = make(map[string][]string, len(g.After))
+		for k, v := range g.After {
+			c.After[k] = slices.Clone(v)
+		}
+	}
+	return &c
+}
+
+// projectKey identifies a project across copies of the registry. IDs are
+// derived from the path a project was first found at, so two instances
+// that discover the same project agree on it.
+func projectKey(p *Project) string {
+	if p.ID != "" {
+		return p.ID
+	}
+	return "path:" + p.Path
+}
+
+func indexProjects(projects []*Project) map[string]*Project {
+	index := make(map[string]*Project, len(projects))
+	for _, p := range projects {
+		index[projectKey(p)] = p
+	}
+	return index
+}
+
+func indexGroups(groups []*Group) map[string]*Group {
+	index := make(map[string]*Group, len(groups))
+	for _, g := range groups {
+		index[g.Name] = g
+	}
+	return index
+}
@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// twoInstances saves a registry with api and web to a temp file, then loads
+// it twice, as two devdash processes would.
+func twoInstances(t *testing.T) (path string, a, b *Registry) {
+	t.Helper()
+	path = filepath.Join(t.TempDir(), "projects.yaml")
+	reg := &Registry{}
+	reg.AddProject("/src/api")
+	reg.AddProject("/src/web")
+	if err := Save(path, reg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	a, _ = Load(path)
+	b, _ = Load(path)
+	return path, a, b
+}
+
+func TestSaveKeepsConcurrentChanges(t *testing.T) {
+	path, a, b := twoInstances(t)
+
+	a.FindByPath("/src/api").Name = "billing"
+	a.SetLayout("/src/api", KindFlake, "")
+	a.AddProject("/src/docs")
+	if err := Save(path, a); err != nil {
+		t.Fatalf("Save(a) error = %v", err)
+	}
+
+	b.ToggleHidden("/src/api")
+	b.RemoveProject("/src/web")
+	if err := Save(path, b); err != nil {
+		t.Fatalf("Save(b) error = %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	api := got.FindByPath("/src/api")
+	if api == nil || api.Name != "billing" || api.Kind != KindFlake || !api.Hidden {
+		t.Errorf("api should keep a's rename and layout and b's hide, got %+v", api)
+	}
+	if got.FindByPath("/src/docs") == nil {
+		t.Error("a's new project was lost")
+	}
+	if got.FindByPath("/src/web") != nil {
+		t.Error("b's removal was undone")
+	}
+}
+
+func TestSaveUpdatesInPlace(t *testing.T) {
+	path, a, b := twoInstances(t)
+	api := b.FindByPath("/src/api")
+
+	a.FindByPath("/src/api").Name = "billing"
+	Save(path, a)
+	b.AddProject("/src/docs")
+	if err := Save(path, b); err != nil {
+		t.Fatalf("Save(b) error = %v", err)
+	}
+
+	if b.FindByPath("/src/api") != api {
+		t.Error("Save should keep existing project pointers")
+	}
+	if api.Name != "billing" {
+		t.Errorf("b should pick up a's rename on save, got %q", api.Name)
+	}
+}
+
+func TestSaveMergesGroups(t *testing.T) {
+	path, a, b := twoInstances(t)
+
+	a.AddGroup("product", []string{"/src/api", "/src/web"})
+	Save(path, a)
+	b.RemoveProject("/src/web")
+	if err := Save(path, b); err != nil {
+		t.Fatalf("Save(b) error = %v", err)
+	}
+
+	got, _ := Load(path)
+	g := got.FindGroup("product")
+	if g == nil {
+		t.Fatal("a's group was lost")
+	}
+	if !reflect.DeepEqual(g.Projects, []string{"/src/api"}) {
+		t.Errorf("group should drop the removed project, got %v", g.Projects)
+	}
+}
+
+func TestMergeLastActiveKeepsLatest(t *testing.T) {
+	earlier := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	base := &Project{ID: "1", Path: "/p", LastActive: earlier}
+	mine := &Project{ID: "1", Path: "/p", LastActive: earlier}
+	disk := &Project{ID: "1", Path: "/p", LastActive: later}
+
+	if got := mergeProject(base, mine, disk); !got.LastActive.Equal(later) {
+		t.Errorf("LastActive = %v, want %v", got.LastActive, later)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path, a, b := twoInstances(t)
+
+	if changed, err := b.Reload(path); err != nil || changed {
+		t.Fatalf("Reload() = %v, %v; want no change", changed, err)
+	}
+
+	// Make sure the write lands on a new modification time
+	time.Sleep(10 * time.Millisecond)
+	a.AddProject("/src/docs")
+	a.ToggleHidden("/src/api")
+	Save(path, a)
+	b.FindByPath("/src/web").Favorite = true // Unsaved
+
+	changed, err := b.Reload(path)
+	if err != nil || !changed {
+		t.Fatalf("Reload() = %v, %v; want a change", changed, err)
+	}
+	if b.FindByPath("/src/docs") == nil || !b.FindByPath("/src/api").Hidden {
+		t.Error("Reload should pick up a's changes")
+	}
+	if !b.FindByPath("/src/web").Favorite {
+		t.Error("Reload should keep b's unsaved changes")
+	}
+
+	if err := Save(path, b); err != nil {
+		t.Fatalf("Save(b) error = %v", err)
+	}
+	got, _ := Load(path)
+	if len(got.Projects) != 3 || !got.FindByPath("/src/web").Favorite {
+		t.Errorf("unexpected registry after saving the reloaded copy: %+v", got.Projects)
+	}
+}
+
+func TestConcurrentSaves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reg, err := Load(path)
+			if err != nil {
+				t.Errorf("Load() error = %v", err)
+				return
+			}
+			reg.AddProject(filepath.Join("/src", string(rune('a'+i))))
+			if err := Save(path, reg); err != nil {
+				t.Errorf("Save() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(got.Projects) != 8 {
+		t.Errorf("expected every instance's project to survive, got %d", len(got.Projects))
+	}
+}
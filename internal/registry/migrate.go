@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/infktd/devdash/internal/schema"
+)
+
+// SchemaVersion is the registry file version this devdash writes.
+const SchemaVersion = 1
+
+// migrations upgrade registry files one version at a time; migrations[i]
+// takes a file from version i to i+1.
+var migrations = []schema.Migration{
+	{
+		// Early registries could hold projects without IDs, which merging
+		// concurrent saves relies on
+		Description: "add version field and project IDs",
+		Apply: func(doc *yaml.Node) error {
+			projects := schema.Lookup(doc, "projects")
+			if projects == nil || projects.Kind != yaml.SequenceNode {
+				return nil
+			}
+			for _, p := range projects.Content {
+				path := schema.Lookup(p, "path")
+				if path == nil || path.Value == "" {
+					continue
+				}
+				if id := schema.Lookup(p, "id"); id == nil || id.Value == "" {
+					schema.SetString(p, "id", projectID(path.Value))
+				}
+			}
+			return nil
+		},
+	},
+}
@@ -2,10 +2,16 @@
 package registry
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/infktd/devdash/internal/fsutil"
+	"github.com/infktd/devdash/internal/schema"
 )
 
 const (
@@ -13,9 +19,17 @@ const (
 	registryFile = "projects.yaml"
 )
 
-// Registry holds discovered projects.
+// Registry holds discovered projects and the groups they're organized in.
 type Registry struct {
+	Version  int        `yaml:"version"`
 	Projects []*Project `yaml:"projects"`
+	Groups   []*Group   `yaml:"groups,omitempty"`
+
+	// base is the registry as last read from or written to disk, so Save
+	// can tell this instance's changes from other writers'.
+	base     *Registry
+	modTime  time.Time
+	warnings []string // Problems found by Load
 }
 
 // Path returns the default registry file path.
@@ -33,36 +47,128 @@ func Path() string {
 	return filepath.Join(configHome, registryDir, registryFile)
 }
 
-// Load reads the registry from path.
+// Load reads the registry from path. Files from older versions are
+// upgraded in place first. Like Save, it holds the file's lock, so the
+// upgrade can't race another instance's save.
 func Load(path string) (*Registry, error) {
-	data, err := os.ReadFile(path)
-	if os.IsNotExist(err) {
-		return &Registry{Projects: []*Project{}}, nil
+	lock, err := fsutil.Lock(path)
+	if err != nil {
+		return nil, err
 	}
+	defer lock.Unlock()
+
+	var notes []string
+	if data, err := os.ReadFile(path); err == nil {
+		_, upgrade, err := schema.UpgradeLocked(path, data, migrations)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if upgrade != nil {
+			notes = append(notes, upgrade.String())
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	reg, data, modTime, err := read(path)
 	if err != nil {
 		return nil, err
 	}
+	reg.base = reg.clone()
+	reg.modTime = modTime
+	reg.warnings = notes
+	for _, k := range schema.UnknownKeys(data, reg) {
+		reg.warnings = append(reg.warnings, k.String())
+	}
+	return reg, nil
+}
+
+// Warnings returns problems Load found that didn't stop it, such as
+// unknown keys, and notes about upgrades it made.
+func (r *Registry) Warnings() []string {
+	return r.warnings
+}
+
+// read parses the registry at path, returning it with the raw file and its
+// modification time. A missing file is an empty registry.
+func read(path string) (*Registry, []byte, time.Time, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return &Registry{Projects: []*Project{}}, nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, time.Time{}, err
+	}
 
 	var reg Registry
 	if err := yaml.Unmarshal(data, &reg); err != nil {
-		return nil, err
+		return nil, nil, time.Time{}, err
 	}
-	return &reg, nil
+	return &reg, data, info.ModTime(), nil
 }
 
-// Save writes the registry to path.
+// Save writes the registry to path. Another process may have changed the
+// file since reg was loaded, so Save locks it, merges reg's changes into
+// what's on disk and writes the result atomically. reg is updated to the
+// merged registry; its projects and groups keep their identity.
 func Save(path string, reg *Registry) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	lock, err := fsutil.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+	disk, _, _, err := read(path)
+	if err != nil {
 		return err
 	}
+	merged := merge(reg.base, reg, disk)
+	merged.Version = SchemaVersion
 
-	data, err := yaml.Marshal(reg)
+	data, err := yaml.Marshal(merged)
 	if err != nil {
 		return err
 	}
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
 
-	return os.WriteFile(path, data, 0644)
+	reg.Version = merged.Version
+	reg.replaceWith(merged)
+	reg.base = merged.clone()
+	if info, err := os.Stat(path); err == nil {
+		reg.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// Reload merges changes another process saved to path since reg was last
+// loaded or saved, keeping reg's own unsaved changes. It reports whether
+// the file had changed.
+func (r *Registry) Reload(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info.ModTime().Equal(r.modTime) {
+		return false, nil
+	}
+
+	disk, _, modTime, err := read(path)
+	if err != nil {
+		return false, err
+	}
+	r.replaceWith(merge(r.base, r, disk))
+	r.base = disk
+	r.modTime = modTime
+	return true, nil
 }
 
 // AddProject adds a project if not already present.
@@ -87,11 +193,14 @@ func (r *Registry) FindByPath(path string) *Project {
 	return nil
 }
 
-// RemoveProject removes a project from the registry.
+// RemoveProject removes a project from the registry and its groups.
 func (r *Registry) RemoveProject(path string) bool {
 	for i, p := range r.Projects {
 		if p.Path == path {
 			r.Projects = append(r.Projects[:i], r.Projects[i+1:]...)
+			for _, g := range r.Groups {
+				g.removeProject(path)
+			}
 			return true
 		}
 	}
@@ -107,3 +216,101 @@ func (r *Registry) ToggleHidden(path string) bool {
 	}
 	return false
 }
+
+// ToggleFavorite toggles whether a project is pinned as a favorite.
+func (r *Registry) ToggleFavorite(path string) bool {
+	p := r.FindByPath(path)
+	if p != nil {
+		p.Favorite = !p.Favorite
+		return true
+	}
+	return false
+}
+
+// SetLayout records a project's layout as found by a scan. It reports
+// whether the layout changed.
+func (r *Registry) SetLayout(path string, kind Kind, root string) bool {
+	p := r.FindByPath(path)
+	if p == nil || (p.Kind == kind && p.Root == root) {
+		return false
+	}
+	p.Kind, p.Root = kind, root
+	return true
+}
+
+// SetIdentity records what recognizes a project after it's moved, as
+// found by a scan. It reports whether anything changed.
+func (r *Registry) SetIdentity(path, remote, fingerprint string) bool {
+	p := r.FindByPath(path)
+	if p == nil || (p.Remote == remote && p.Fingerprint == fingerprint) {
+		return false
+	}
+	p.Remote, p.Fingerprint = remote, fingerprint
+	return true
+}
+
+// FindMoved returns the project that a project found with remote and
+// fingerprint was moved from: the one project whose directory is gone and
+// whose remote, or failing that fingerprint, matches. It returns nil when
+// no project or more than one matches, so a copy is never taken for a move.
+func (r *Registry) FindMoved(remote, fingerprint string) *Project {
+	var gone []*Project
+	for _, p := range r.Projects {
+		if _, err := os.Stat(p.Path); os.IsNotExist(err) {
+			gone = append(gone, p)
+		}
+	}
+	match := func(key func(*Project) string, want string) (*Project, bool) {
+		if want == "" {
+			return nil, false
+		}
+		var found *Project
+		for _, p := range gone {
+			if key(p) != want {
+				continue
+			}
+			if found != nil {
+				return nil, true // Ambiguous
+			}
+			found = p
+		}
+		return found, found != nil
+	}
+	if p, ok := match(func(p *Project) string { return p.Remote }, remote); ok {
+		return p
+	}
+	p, _ := match(func(p *Project) string { return p.Fingerprint }, fingerprint)
+	return p
+}
+
+// Relocate moves the project at path to newPath, keeping its ID, name,
+// tags and group memberships.
+func (r *Registry) Relocate(path, newPath string) bool {
+	p := r.FindByPath(path)
+	if p == nil || r.FindByPath(newPath) != nil {
+		return false
+	}
+	p.Path = newPath
+	for _, g := range r.Groups {
+		g.renameProject(path, newPath)
+	}
+	return true
+}
+
+// SetTags replaces a project's tags. Tags are trimmed, and empty and
+// duplicate (ignoring case) tags are dropped.
+func (r *Registry) SetTags(path string, tags []string) bool {
+	p := r.FindByPath(path)
+	if p == nil {
+		return false
+	}
+	p.Tags = nil
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || p.HasTag(tag) {
+			continue
+		}
+		p.Tags = append(p.Tags, tag)
+	}
+	return true
+}
@@ -1,9 +1,13 @@
 package registry
 
 import (
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 )
 
@@ -303,6 +307,122 @@ func TestRegistryToggleHidden(t *testing.T) {
 	}
 }
 
+func TestRegistryToggleFavorite(t *testing.T) {
+	reg := &Registry{}
+	p := reg.AddProject("/test/path1")
+
+	if !reg.ToggleFavorite("/test/path1") || !p.Favorite {
+		t.Error("project should be a favorite after toggle")
+	}
+	reg.ToggleFavorite("/test/path1")
+	if p.Favorite {
+		t.Error("project should not be a favorite after second toggle")
+	}
+	if reg.ToggleFavorite("/nonexistent") {
+		t.Error("ToggleFavorite should return false for nonexistent project")
+	}
+}
+
+func TestRegistrySetTags(t *testing.T) {
+	reg := &Registry{}
+	p := reg.AddProject("/test/path1")
+
+	if !reg.SetTags("/test/path1", []string{" backend ", "", "API", "api", "go"}) {
+		t.Fatal("SetTags should return true for existing project")
+	}
+	if want := []string{"backend", "API", "go"}; !reflect.DeepEqual(p.Tags, want) {
+		t.Errorf("Tags = %v, want %v", p.Tags, want)
+	}
+	if !p.HasTag("api") || p.HasTag("frontend") {
+		t.Error("HasTag should match tags ignoring case")
+	}
+	if reg.SetTags("/nonexistent", []string{"x"}) {
+		t.Error("SetTags should return false for nonexistent project")
+	}
+}
+
+func TestRegistrySetLayout(t *testing.T) {
+	reg := &Registry{}
+	p := reg.AddProject("/mono/api")
+	if p.Kind != KindStandalone || p.Dir() != "/mono/api" {
+		t.Fatalf("new project = %+v, want a standalone project run in its own directory", p)
+	}
+
+	if !reg.SetLayout("/mono/api", KindImported, "/mono") {
+		t.Fatal("SetLayout should report a changed layout")
+	}
+	if p.Dir() != "/mono" || p.SocketPath() != "/mono/.devenv/run/pc.sock" {
+		t.Errorf("an imported project should run in its root, got Dir() = %q", p.Dir())
+	}
+	if reg.SetLayout("/mono/api", KindImported, "/mono") {
+		t.Error("SetLayout should report an unchanged layout")
+	}
+	if reg.SetLayout("/nonexistent", KindFlake, "") {
+		t.Error("SetLayout should return false for nonexistent project")
+	}
+}
+
+func TestRegistryFindMoved(t *testing.T) {
+	present := t.TempDir()
+	reg := &Registry{}
+	reg.AddProject(present)
+	reg.SetIdentity(present, "git@example.com:acme/api.git", "f1")
+	reg.AddProject("/gone/api")
+	reg.SetIdentity("/gone/api", "git@example.com:acme/api.git", "f1")
+	reg.AddProject("/gone/web")
+	reg.SetIdentity("/gone/web", "git@example.com:acme/web.git", "f2")
+	reg.AddProject("/gone/copy1")
+	reg.SetIdentity("/gone/copy1", "", "f3")
+	reg.AddProject("/gone/copy2")
+	reg.SetIdentity("/gone/copy2", "", "f3")
+
+	tests := []struct {
+		remote, fingerprint string
+		want                string
+	}{
+		{"git@example.com:acme/api.git", "changed", "/gone/api"}, // Projects that still exist aren't moves
+		{"", "f2", "/gone/web"},                                  // Fingerprint without a remote
+		{"git@example.com:acme/other.git", "f2", "/gone/web"},    // Fingerprint when no remote matches
+		{"", "f3", ""}, // Ambiguous
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if p := reg.FindMoved(tt.remote, tt.fingerprint); p != nil {
+			got = p.Path
+		}
+		if got != tt.want {
+			t.Errorf("FindMoved(%q, %q) = %q, want %q", tt.remote, tt.fingerprint, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryRelocate(t *testing.T) {
+	reg := &Registry{}
+	api := reg.AddProject("/old/api")
+	api.Tags = []string{"backend"}
+	id := api.ID
+	reg.AddProject("/src/db")
+	g := reg.AddGroup("shop", []string{"/src/db", "/old/api"})
+	g.After = map[string][]string{"/old/api": {"/src/db"}}
+
+	if !reg.Relocate("/old/api", "/new/api") {
+		t.Fatal("Relocate should return true for existing project")
+	}
+	if api.Path != "/new/api" || api.ID != id || api.Name != "api" || !api.HasTag("backend") {
+		t.Errorf("relocated project = %+v, want the new path with the same ID, name and tags", api)
+	}
+	if !reflect.DeepEqual(g.Projects, []string{"/src/db", "/new/api"}) {
+		t.Errorf("group projects = %v", g.Projects)
+	}
+	if !reflect.DeepEqual(g.After, map[string][]string{"/new/api": {"/src/db"}}) {
+		t.Errorf("group dependencies = %v", g.After)
+	}
+	if reg.Relocate("/new/api", "/src/db") {
+		t.Error("Relocate onto another project should fail")
+	}
+}
+
 func TestProjectStateString(t *testing.T) {
 	tests := []struct {
 		state ProjectState
@@ -313,6 +433,7 @@ func TestProjectStateString(t *testing.T) {
 		{StateDegraded, "degraded"},
 		{StateStale, "stale"},
 		{StateMissing, "missing"},
+		{StateStarting, "starting"},
 		{ProjectState(999), "unknown"},
 	}
 
@@ -325,3 +446,140 @@ func TestProjectStateString(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectStateWithReadiness(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "devdash-registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Serve a fake process-compose API on the project's socket
+	runDir := filepath.Join(dir, ".devenv", "run")
+	os.MkdirAll(runDir, 0755)
+	listener, err := net.Listen("unix", filepath.Join(runDir, "pc.sock"))
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	defer listener.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"name": "web", "is_running": true}, {"name": "db", "is_running": true}]}`))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	p := NewProject(dir)
+	if got := p.DetectState(); got != StateRunning {
+		t.Errorf("DetectState() = %v, want running", got)
+	}
+
+	notReady := func(service string) bool { return service != "web" }
+	if got := p.DetectStateWithReadiness(notReady); got != StateStarting {
+		t.Errorf("DetectStateWithReadiness() = %v, want starting", got)
+	}
+
+	allReady := func(string) bool { return true }
+	if got := p.DetectStateWithReadiness(allReady); got != StateRunning {
+		t.Errorf("DetectStateWithReadiness() = %v, want running", got)
+	}
+}
+
+func TestDetectStateCountsFinishedOneShots(t *testing.T) {
+	dir, err := os.MkdirTemp("/tmp", "devdash-registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	runDir := filepath.Join(dir, ".devenv", "run")
+	os.MkdirAll(runDir, 0755)
+	listener, err := net.Listen("unix", filepath.Join(runDir, "pc.sock"))
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	defer listener.Close()
+
+	var migrate atomic.Value
+	migrate.Store(`{"name": "migrate", "status": "Completed", "exit_code": 0}`)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/processes", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": [{"name": "web", "is_running": true}, ` + migrate.Load().(string) + `]}`))
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	p := NewProject(dir)
+	if got := p.DetectState(); got != StateRunning {
+		t.Errorf("DetectState() with a finished one-shot = %v, want running", got)
+	}
+
+	migrate.Store(`{"name": "migrate", "status": "Error", "exit_code": 1}`)
+	if got := p.DetectState(); got != StateDegraded {
+		t.Errorf("DetectState() with a failed one-shot = %v, want degraded", got)
+	}
+}
+
+func TestProjectStateIsActive(t *testing.T) {
+	for _, s := range []ProjectState{StateRunning, StateDegraded, StateStarting} {
+		if !s.IsActive() {
+			t.Errorf("%v should be active", s)
+		}
+	}
+	for _, s := range []ProjectState{StateIdle, StateStale, StateMissing} {
+		if s.IsActive() {
+			t.Errorf("%v should not be active", s)
+		}
+	}
+}
+
+func TestLoadUpgradesUnversionedRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+	original := "projects:\n  - path: /src/api\n    name: api\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if reg.Version != SchemaVersion {
+		t.Errorf("Version = %d, want %d", reg.Version, SchemaVersion)
+	}
+	if api := reg.FindByPath("/src/api"); api == nil || api.ID != NewProject("/src/api").ID {
+		t.Errorf("the upgrade should give projects their path IDs, got %+v", api)
+	}
+	if len(reg.Warnings()) != 1 {
+		t.Errorf("Warnings() = %v, want an upgrade note", reg.Warnings())
+	}
+	if backup, err := os.ReadFile(path + ".v0.bak"); err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v", backup, err)
+	}
+}
+
+func TestLoadWarnsAboutUnknownRegistryKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "projects.yaml")
+	data := "version: 1\nprojects:\n  - id: abc\n    path: /src/api\n    favourite: true\n"
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []string{"line 5: unknown key projects[0].favourite"}
+	if !reflect.DeepEqual(reg.Warnings(), want) {
+		t.Errorf("Warnings() = %v, want %v", reg.Warnings(), want)
+	}
+}
+
+func TestMigrationsMatchSchemaVersion(t *testing.T) {
+	if len(migrations) != SchemaVersion {
+		t.Errorf("%d migrations for schema version %d", len(migrations), SchemaVersion)
+	}
+}
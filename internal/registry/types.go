@@ -6,6 +6,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/infktd/devdash/internal/compose"
@@ -20,6 +21,7 @@ const (
 	StateDegraded
 	StateStale
 	StateMissing
+	StateStarting // Processes running but readiness probes not yet passing
 )
 
 func (s ProjectState) String() string {
@@ -34,43 +36,94 @@ func (s ProjectState) String() string {
 		return "stale"
 	case StateMissing:
 		return "missing"
+	case StateStarting:
+		return "starting"
 	default:
 		return "unknown"
 	}
 }
 
+// Kind is a project's layout, which decides how devenv is invoked for it.
+type Kind string
+
+const (
+	KindStandalone Kind = "standalone" // devenv.nix or devenv.yaml, run with devenv
+	KindFlake      Kind = "flake"      // flake.nix using devenv, run through nix develop
+	KindImported   Kind = "imported"   // Imported by another project's devenv.yaml, run from its root
+)
+
 // Project represents a devenv project in the registry.
 type Project struct {
-	ID         string    `yaml:"id"`
-	Path       string    `yaml:"path"`
-	Name       string    `yaml:"name"`
-	Hidden     bool      `yaml:"hidden"`
-	LastActive time.Time `yaml:"last_active"`
+	ID          string    `yaml:"id"`
+	Path        string    `yaml:"path"`
+	Name        string    `yaml:"name"`
+	Kind        Kind      `yaml:"kind,omitempty"`        // Empty for projects registered before layouts were detected, treated as standalone
+	Root        string    `yaml:"root,omitempty"`        // Project whose devenv runs an imported project
+	Remote      string    `yaml:"remote,omitempty"`      // Git remote and the project's directory in it, for recognizing moves
+	Fingerprint string    `yaml:"fingerprint,omitempty"` // Hash of the devenv files, for recognizing moves
+	Hidden      bool      `yaml:"hidden"`
+	Favorite    bool      `yaml:"favorite,omitempty"`
+	Tags        []string  `yaml:"tags,omitempty"`
+	LastActive  time.Time `yaml:"last_active"`
 }
 
 // NewProject creates a new Project from a path.
 func NewProject(path string) *Project {
-	// Generate ID from path hash
-	hash := sha256.Sum256([]byte(path))
-	id := hex.EncodeToString(hash[:8])
-
 	return &Project{
-		ID:         id,
+		ID:         projectID(path),
 		Path:       path,
 		Name:       filepath.Base(path),
+		Kind:       KindStandalone,
 		Hidden:     false,
 		LastActive: time.Now(),
 	}
 }
 
+// projectID derives a project's ID from the path it was first found at.
+func projectID(path string) string {
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:8])
+}
+
+// HasTag reports whether the project has tag, ignoring case.
+func (p *Project) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir returns the directory devenv runs in for the project: its root for
+// imported projects, otherwise its own path.
+func (p *Project) Dir() string {
+	if p.Kind == KindImported && p.Root != "" {
+		return p.Root
+	}
+	return p.Path
+}
+
 // SocketPath returns the path to the process-compose socket.
 // devenv creates a symlink at .devenv/run pointing to /run/user/$UID/devenv-$HASH
 func (p *Project) SocketPath() string {
-	return filepath.Join(p.Path, ".devenv", "run", "pc.sock")
+	return filepath.Join(p.Dir(), ".devenv", "run", "pc.sock")
+}
+
+// IsActive reports whether the project's process-compose is up.
+func (s ProjectState) IsActive() bool {
+	return s == StateRunning || s == StateDegraded || s == StateStarting
 }
 
 // DetectState checks the project's current state.
 func (p *Project) DetectState() ProjectState {
+	return p.DetectStateWithReadiness(nil)
+}
+
+// DetectStateWithReadiness is like DetectState, but reports StateStarting
+// while every process runs and ready returns false for any of them. A nil
+// ready treats all running processes as ready.
+func (p *Project) DetectStateWithReadiness(ready func(service string) bool) ProjectState {
 	// Check if path exists
 	if _, err := os.Stat(p.Path); os.IsNotExist(err) {
 		return StateMissing
@@ -83,7 +136,7 @@ func (p *Project) DetectState() ProjectState {
 	if err == nil {
 		conn.Close()
 		// Socket is reachable, query API to check service states
-		return p.checkServiceStates()
+		return p.checkServiceStates(ready)
 	}
 
 	// Check if socket file exists (stale)
@@ -95,7 +148,7 @@ func (p *Project) DetectState() ProjectState {
 }
 
 // checkServiceStates queries the compose API to determine if the project is running or degraded.
-func (p *Project) checkServiceStates() ProjectState {
+func (p *Project) checkServiceStates(ready func(service string) bool) ProjectState {
 	client := compose.NewClient(p.SocketPath())
 	if err := client.Connect(); err != nil {
 		// Can't connect, consider it idle
@@ -108,13 +161,16 @@ func (p *Project) checkServiceStates() ProjectState {
 		return StateRunning
 	}
 
-	// Count running and total processes
+	// Count running, finished one-shot and total processes
 	runningCount := 0
+	succeededCount := 0
 	totalCount := len(status.Processes)
 
 	for _, proc := range status.Processes {
 		if proc.IsRunning {
 			runningCount++
+		} else if proc.Succeeded() {
+			succeededCount++
 		}
 	}
 
@@ -123,8 +179,16 @@ func (p *Project) checkServiceStates() ProjectState {
 		return StateIdle
 	}
 
-	// If all processes running, fully operational
-	if runningCount == totalCount {
+	// If all processes running (or done), fully operational once they pass
+	// their probes
+	if runningCount > 0 && runningCount+succeededCount == totalCount {
+		if ready != nil {
+			for _, proc := range status.Processes {
+				if proc.IsRunning && !ready(proc.Name) {
+					return StateStarting
+				}
+			}
+		}
 		return StateRunning
 	}
 
@@ -139,7 +203,7 @@ func (p *Project) checkServiceStates() ProjectState {
 
 // Repair cleans up stale socket files and symlinks.
 func (p *Project) Repair() error {
-	runDir := filepath.Join(p.Path, ".devenv", "run")
+	runDir := filepath.Join(p.Dir(), ".devenv", "run")
 
 	// Remove the entire run directory (contains socket and symlink)
 	if err := os.RemoveAll(runDir); err != nil {
@@ -0,0 +1,238 @@
+// Package restart decides when crashed services should be restarted.
+package restart
+
+import (
+	"sync"
+	"time"
+
+	"github.com/infktd/devdash/internal/config"
+)
+
+// Defaults for policy fields left unset in config.
+const (
+	DefaultMaxAttempts  = 5
+	DefaultWindow       = 5 * time.Minute
+	DefaultInitialDelay = time.Second
+	DefaultMaxDelay     = time.Minute
+)
+
+// Action is what the supervisor wants done about a crash.
+type Action int
+
+const (
+	ActionNone    Action = iota // No policy, or restarts are on hold
+	ActionRestart               // Restart after Decision.Delay
+	ActionGiveUp                // Too many crashes; stop restarting
+)
+
+// Decision is the supervisor's response to a crash.
+type Decision struct {
+	Action  Action
+	Delay   time.Duration
+	Attempt int // Crashes within the window, including this one
+}
+
+// serviceState tracks restart history for one service.
+type serviceState struct {
+	crashes []time.Time
+	gaveUp  bool
+	held    bool
+	pending int // Attempt of the scheduled restart, 0 when none
+}
+
+// Supervisor applies restart policies to crash events.
+type Supervisor struct {
+	policies []config.RestartPolicy
+	states   map[string]*serviceState // key: "project:service"
+	mu       sync.Mutex
+}
+
+// NewSupervisor creates a supervisor for the given policies.
+func NewSupervisor(policies []config.RestartPolicy) *Supervisor {
+	return &Supervisor{
+		policies: policies,
+		states:   make(map[string]*serviceState),
+	}
+}
+
+// SetPolicies replaces the restart policies. Crash history and holds are
+// kept, so a config reload doesn't reset services mid-restart.
+func (s *Supervisor) SetPolicies(policies []config.RestartPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.policies = policies
+}
+
+func key(project, service string) string {
+	return project + ":" + service
+}
+
+// PolicyFor returns the most specific policy matching a service, or nil.
+// Ties go to the policy listed first.
+func (s *Supervisor) PolicyFor(project, service string) *config.RestartPolicy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.policyFor(project, service)
+}
+
+// policyFor is PolicyFor for callers that hold the lock.
+func (s *Supervisor) policyFor(project, service string) *config.RestartPolicy {
+	var best *config.RestartPolicy
+	bestScore := -1
+	for i := range s.policies {
+		p := &s.policies[i]
+		svcScore, ok := config.MatchScore(p.Service, service)
+		if !ok {
+			continue
+		}
+		projScore, ok := config.MatchScore(p.Project, project)
+		if !ok {
+			continue
+		}
+		if score := svcScore + projScore; score > bestScore {
+			best, bestScore = p, score
+		}
+	}
+	return best
+}
+
+// Crashed records a crash at now and decides whether to restart the service.
+// Once a service crashes more than MaxAttempts times within Window the
+// supervisor gives up until the service is seen running again.
+func (s *Supervisor) Crashed(project, service string, now time.Time) Decision {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	policy := s.policyFor(project, service)
+	if policy == nil || policy.Policy == "" || policy.Policy == config.RestartNever {
+		return Decision{Action: ActionNone}
+	}
+
+	st := s.state(project, service)
+	if st.gaveUp || st.held {
+		return Decision{Action: ActionNone}
+	}
+
+	// Keep only crashes inside the window
+	window := seconds(policy.Window, DefaultWindow)
+	recent := st.crashes[:0]
+	for _, t := range st.crashes {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	st.crashes = append(recent, now)
+	attempt := len(st.crashes)
+
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if attempt > maxAttempts {
+		st.gaveUp = true
+		st.pending = 0
+		return Decision{Action: ActionGiveUp, Attempt: attempt}
+	}
+
+	st.pending = attempt
+	return Decision{
+		Action:  ActionRestart,
+		Delay:   delay(policy, attempt),
+		Attempt: attempt,
+	}
+}
+
+// Recovered notes that a service is running again. This clears a give-up or
+// hold so a manually restarted service is supervised afresh.
+func (s *Supervisor) Recovered(project, service string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, ok := s.states[key(project, service)]
+	if !ok {
+		return
+	}
+	st.pending = 0
+	if st.gaveUp || st.held {
+		delete(s.states, key(project, service))
+	}
+}
+
+// Pending reports whether the restart scheduled for attempt is still
+// wanted: the service has not been held, given up on, recovered or crashed
+// again since.
+func (s *Supervisor) Pending(project, service string, attempt int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if projectHold, ok := s.states[key(project, "*")]; ok && projectHold.held {
+		return false
+	}
+	st, ok := s.states[key(project, service)]
+	if !ok {
+		return false
+	}
+	return !st.gaveUp && !st.held && st.pending == attempt
+}
+
+// Hold suspends restarts for a service until it recovers, e.g. after the
+// user stopped it on purpose.
+func (s *Supervisor) Hold(project, service string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state(project, service).held = true
+}
+
+// HoldProject suspends restarts for every tracked and future service of a
+// project until ReleaseProject is called.
+func (s *Supervisor) HoldProject(project string) {
+	s.Hold(project, "*")
+}
+
+// ReleaseProject resumes restarts for a project held with HoldProject.
+func (s *Supervisor) ReleaseProject(project string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, key(project, "*"))
+}
+
+// state returns the state for a service, creating it if needed. A held
+// project holds all of its services. Caller must hold the lock.
+func (s *Supervisor) state(project, service string) *serviceState {
+	if projectHold, ok := s.states[key(project, "*")]; ok && projectHold.held {
+		return projectHold
+	}
+	k := key(project, service)
+	st, ok := s.states[k]
+	if !ok {
+		st = &serviceState{}
+		s.states[k] = st
+	}
+	return st
+}
+
+// delay returns how long to wait before restart attempt n (1-based).
+func delay(policy *config.RestartPolicy, attempt int) time.Duration {
+	initial := seconds(policy.InitialDelay, DefaultInitialDelay)
+	if policy.Policy != config.RestartBackoff {
+		return initial
+	}
+
+	maxDelay := seconds(policy.MaxDelay, DefaultMaxDelay)
+	d := initial
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	return d
+}
+
+// seconds converts a config value in seconds, using def when unset.
+func seconds(v int, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return time.Duration(v) * time.Second
+}
@@ -0,0 +1,208 @@
+package restart
+
+import (
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/config"
+)
+
+var t0 = time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC)
+
+func TestCrashedWithoutPolicy(t *testing.T) {
+	s := NewSupervisor(nil)
+	if d := s.Crashed("api", "web", t0); d.Action != ActionNone {
+		t.Errorf("expected no action without a policy, got %+v", d)
+	}
+
+	s = NewSupervisor([]config.RestartPolicy{{Service: "web", Policy: config.RestartNever}})
+	if d := s.Crashed("api", "web", t0); d.Action != ActionNone {
+		t.Errorf("expected no action for never policy, got %+v", d)
+	}
+}
+
+func TestOnFailureGivesUpAfterMaxAttempts(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{
+		Service:      "worker",
+		Policy:       config.RestartOnFailure,
+		MaxAttempts:  2,
+		Window:       60,
+		InitialDelay: 3,
+	}})
+
+	for i := 1; i <= 2; i++ {
+		d := s.Crashed("api", "worker", t0.Add(time.Duration(i)*time.Second))
+		if d.Action != ActionRestart || d.Delay != 3*time.Second || d.Attempt != i {
+			t.Fatalf("crash %d: unexpected decision %+v", i, d)
+		}
+	}
+
+	d := s.Crashed("api", "worker", t0.Add(3*time.Second))
+	if d.Action != ActionGiveUp || d.Attempt != 3 {
+		t.Fatalf("expected give up on third crash, got %+v", d)
+	}
+
+	// Stays given up until the service is seen running again
+	if d := s.Crashed("api", "worker", t0.Add(4*time.Second)); d.Action != ActionNone {
+		t.Errorf("expected no action after giving up, got %+v", d)
+	}
+	s.Recovered("api", "worker")
+	if d := s.Crashed("api", "worker", t0.Add(5*time.Second)); d.Action != ActionRestart || d.Attempt != 1 {
+		t.Errorf("expected fresh supervision after recovery, got %+v", d)
+	}
+}
+
+func TestCrashesOutsideWindowAreForgotten(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{
+		Service:     "worker",
+		Policy:      config.RestartOnFailure,
+		MaxAttempts: 1,
+		Window:      10,
+	}})
+
+	s.Crashed("api", "worker", t0)
+	d := s.Crashed("api", "worker", t0.Add(time.Minute))
+	if d.Action != ActionRestart || d.Attempt != 1 {
+		t.Errorf("old crash should have left the window, got %+v", d)
+	}
+}
+
+func TestRecoveryDoesNotResetWindow(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{Service: "worker", Policy: config.RestartOnFailure, MaxAttempts: 1}})
+
+	s.Crashed("api", "worker", t0)
+	s.Recovered("api", "worker") // our own restart brought it back
+	if d := s.Crashed("api", "worker", t0.Add(time.Second)); d.Action != ActionGiveUp {
+		t.Errorf("crash loop should still give up, got %+v", d)
+	}
+}
+
+func TestBackoffDelays(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{
+		Service:      "watcher",
+		Policy:       config.RestartBackoff,
+		MaxAttempts:  10,
+		InitialDelay: 1,
+		MaxDelay:     5,
+	}})
+
+	want := []time.Duration{1, 2, 4, 5, 5}
+	for i, w := range want {
+		d := s.Crashed("api", "watcher", t0.Add(time.Duration(i)*time.Second))
+		if d.Delay != w*time.Second {
+			t.Errorf("attempt %d delay = %v, want %v", i+1, d.Delay, w*time.Second)
+		}
+	}
+}
+
+func TestPolicyForMostSpecific(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{
+		{Service: "*", Policy: config.RestartNever},
+		{Service: "queue-*", Policy: config.RestartBackoff},
+		{Project: "api", Service: "queue-mail", Policy: config.RestartOnFailure},
+	})
+
+	tests := []struct {
+		project, service, want string
+	}{
+		{"api", "queue-mail", config.RestartOnFailure},
+		{"web", "queue-mail", config.RestartBackoff},
+		{"api", "postgres", config.RestartNever},
+	}
+	for _, tt := range tests {
+		p := s.PolicyFor(tt.project, tt.service)
+		if p == nil || p.Policy != tt.want {
+			t.Errorf("PolicyFor(%q, %q) = %+v, want %s", tt.project, tt.service, p, tt.want)
+		}
+	}
+}
+
+func TestHold(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{Service: "*", Policy: config.RestartOnFailure}})
+
+	s.Hold("api", "web")
+	if d := s.Crashed("api", "web", t0); d.Action != ActionNone {
+		t.Errorf("held service should not restart, got %+v", d)
+	}
+	s.Recovered("api", "web")
+	if d := s.Crashed("api", "web", t0); d.Action != ActionRestart {
+		t.Errorf("recovery should release the hold, got %+v", d)
+	}
+}
+
+func TestPending(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{Service: "*", Policy: config.RestartOnFailure, MaxAttempts: 2}})
+
+	if s.Pending("api", "web", 1) {
+		t.Error("nothing is pending before a crash")
+	}
+	d := s.Crashed("api", "web", t0)
+	if !s.Pending("api", "web", d.Attempt) {
+		t.Error("scheduled restart should be pending")
+	}
+	s.Recovered("api", "web")
+	if s.Pending("api", "web", d.Attempt) {
+		t.Error("recovery should cancel the pending restart")
+	}
+
+	d = s.Crashed("api", "web", t0.Add(time.Second))
+	s.Hold("api", "web")
+	if s.Pending("api", "web", d.Attempt) {
+		t.Error("hold should cancel the pending restart")
+	}
+	s.Recovered("api", "web")
+
+	d = s.Crashed("api", "web", t0.Add(2*time.Second))
+	s.HoldProject("api")
+	if s.Pending("api", "web", d.Attempt) {
+		t.Error("project hold should cancel the pending restart")
+	}
+	s.ReleaseProject("api")
+
+	s.Crashed("api", "worker", t0)
+	s.Crashed("api", "worker", t0.Add(time.Second))
+	if s.Pending("api", "worker", 1) {
+		t.Error("a newer crash should supersede the earlier restart")
+	}
+	s.Crashed("api", "worker", t0.Add(2*time.Second))
+	if s.Pending("api", "worker", 2) {
+		t.Error("nothing is pending after giving up")
+	}
+}
+
+func TestHoldProject(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{Service: "*", Policy: config.RestartOnFailure}})
+
+	s.HoldProject("api")
+	if d := s.Crashed("api", "web", t0); d.Action != ActionNone {
+		t.Errorf("services of a held project should not restart, got %+v", d)
+	}
+	if d := s.Crashed("other", "web", t0); d.Action != ActionRestart {
+		t.Errorf("other projects are unaffected, got %+v", d)
+	}
+
+	s.ReleaseProject("api")
+	if d := s.Crashed("api", "web", t0); d.Action != ActionRestart {
+		t.Errorf("released project should restart again, got %+v", d)
+	}
+}
+
+func TestSetPoliciesKeepsState(t *testing.T) {
+	s := NewSupervisor([]config.RestartPolicy{{Service: "*", Policy: config.RestartOnFailure, MaxAttempts: 3}})
+
+	s.Crashed("api", "worker", t0)
+	s.HoldProject("web")
+	s.SetPolicies([]config.RestartPolicy{{Service: "*", Policy: config.RestartOnFailure, MaxAttempts: 3}})
+
+	if d := s.Crashed("api", "worker", t0.Add(time.Second)); d.Attempt != 2 {
+		t.Errorf("crash history should survive new policies, got attempt %d", d.Attempt)
+	}
+	if d := s.Crashed("web", "worker", t0); d.Action != ActionNone {
+		t.Errorf("project hold should survive new policies, got %+v", d)
+	}
+
+	s.SetPolicies(nil)
+	if d := s.Crashed("api", "worker", t0.Add(2*time.Second)); d.Action != ActionNone {
+		t.Errorf("new policies should apply, got %+v", d)
+	}
+}
@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/infktd/devdash/internal/fsutil"
+)
+
+const (
+	cacheDir  = "devdash"
+	cacheFile = "scan-cache.json"
+
+	// cacheVersion changes whenever listing does, dropping older caches.
+	cacheVersion = 2
+)
+
+// CachePath returns the default scan cache file path.
+func CachePath() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			stateHome = filepath.Join(".local", "state")
+		} else {
+			stateHome = filepath.Join(home, ".local", "state")
+		}
+	}
+	return filepath.Join(stateHome, cacheDir, cacheFile)
+}
+
+// listing is what a scan needs from a directory. Adding or removing an
+// entry changes a directory's modification time, so a listing stays valid
+// while the time does.
+type listing struct {
+	ModTime    time.Time `json:"mtime"`
+	Dirs       []string  `json:"dirs,omitempty"` // Subdirectory names
+	Devenv     bool      `json:"devenv,omitempty"`
+	DevenvYAML bool      `json:"devenv_yaml,omitempty"`
+	Flake      bool      `json:"flake,omitempty"`
+	Gitignore  bool      `json:"gitignore,omitempty"`
+}
+
+// cacheFileData is the cache as saved.
+type cacheFileData struct {
+	Version int                `json:"version"`
+	Dirs    map[string]listing `json:"dirs"`
+}
+
+// Cache remembers directory listings between scans, so a rescan only reads
+// the directories that changed. It's safe for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	dirs    map[string]listing
+	visited map[string]bool
+}
+
+// NewCache returns an empty cache.
+func NewCache() *Cache {
+	return &Cache{dirs: make(map[string]listing)}
+}
+
+// LoadCache reads the cache at path. A missing or unreadable cache is
+// empty; the scan just reads every directory again.
+func LoadCache(path string) *Cache {
+	c := NewCache()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var saved cacheFileData
+	if err := json.Unmarshal(data, &saved); err == nil && saved.Version == cacheVersion && saved.Dirs != nil {
+		c.dirs = saved.Dirs
+	}
+	return c
+}
+
+// Save writes the cache to path.
+func (c *Cache) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.Marshal(cacheFileData{Version: cacheVersion, Dirs: c.dirs})
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFile(path, data, 0644)
+}
+
+// get returns the listing of dir if it's still valid for modTime.
+func (c *Cache) get(dir string, modTime time.Time) (listing, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.visited[dir] = true
+	l, ok := c.dirs[dir]
+	return l, ok && l.ModTime.Equal(modTime)
+}
+
+func (c *Cache) put(dir string, l listing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.visited[dir] = true
+	c.dirs[dir] = l
+}
+
+// begin starts tracking which directories a scan visits.
+func (c *Cache) begin() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.visited = make(map[string]bool)
+}
+
+// prune drops the directories the last scan didn't visit, such as deleted
+// ones, so the cache doesn't grow forever.
+func (c *Cache) prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for dir := range c.dirs {
+		if !c.visited[dir] {
+			delete(c.dirs, dir)
+		}
+	}
+}
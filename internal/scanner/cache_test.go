@@ -0,0 +1,62 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheReusesUnchangedListings(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeProject(t, filepath.Join(tmpDir, "app"))
+	cachePath := filepath.Join(t.TempDir(), "scan-cache.json")
+
+	cache := LoadCache(cachePath)
+	if _, err := ScanWith(context.Background(), []string{tmpDir}, Options{MaxDepth: 3, Cache: cache}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(cachePath); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	// Fake a listing for the unchanged root: the scan should trust it
+	cache = LoadCache(cachePath)
+	info, _ := os.Stat(tmpDir)
+	cache.dirs[tmpDir] = listing{ModTime: info.ModTime(), Devenv: true}
+	projects, _ := ScanWith(context.Background(), []string{tmpDir}, Options{MaxDepth: 3, Cache: cache})
+	if len(projects) != 1 || projects[0].Path != tmpDir {
+		t.Errorf("projects = %v, want the cached listing", projects)
+	}
+	if _, ok := cache.dirs[filepath.Join(tmpDir, "app")]; ok {
+		t.Error("directories the scan no longer reaches should be pruned")
+	}
+}
+
+func TestCacheRereadsChangedDirectories(t *testing.T) {
+	tmpDir := t.TempDir()
+	cache := NewCache()
+	projects, _ := ScanWith(context.Background(), []string{tmpDir}, Options{MaxDepth: 3, Cache: cache})
+	if len(projects) != 0 {
+		t.Fatalf("projects = %v, want none", projects)
+	}
+
+	writeProject(t, filepath.Join(tmpDir, "app"))
+	// Make sure the root's mtime moves on filesystems with coarse timestamps
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(tmpDir, later, later)
+
+	projects, _ = ScanWith(context.Background(), []string{tmpDir}, Options{MaxDepth: 3, Cache: cache})
+	if len(projects) != 1 {
+		t.Errorf("projects = %v, want the new project", projects)
+	}
+}
+
+func TestLoadCacheIgnoresCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan-cache.json")
+	os.WriteFile(path, []byte("{not json"), 0644)
+	if c := LoadCache(path); c == nil || len(c.dirs) != 0 {
+		t.Error("a corrupt cache should load empty")
+	}
+}
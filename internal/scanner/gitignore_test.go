@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	base := "/src"
+	var rules ignoreRules
+	for _, line := range []string{"build/", "/dist", "*.tmp", "docs/**/gen", "!keep.tmp", "\\#literal", ""} {
+		if r, ok := parseIgnoreLine(base, line); ok {
+			rules = append(rules, r)
+		}
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"/src/build", true, true},
+		{"/src/app/build", true, true},
+		{"/src/build", false, false}, // Trailing slash only matches directories
+		{"/src/dist", true, true},
+		{"/src/app/dist", true, false}, // Leading slash anchors to the base
+		{"/src/a.tmp", true, true},
+		{"/src/keep.tmp", true, false}, // Negated
+		{"/src/docs/gen", true, true},
+		{"/src/docs/api/v1/gen", true, true},
+		{"/src/#literal", true, true},
+		{"/other/build", true, false}, // Outside the base
+	}
+	for _, tt := range tests {
+		if got := rules.ignored(filepath.FromSlash(tt.path), tt.isDir); got != tt.want {
+			t.Errorf("ignored(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fingerprintFiles are hashed into a project's fingerprint.
+var fingerprintFiles = []string{devenvNix, devenvYAML, "devenv.lock", flakeNix, "flake.lock"}
+
+// identify fills in what recognizes h's project after it's moved.
+func identify(h *Hit) {
+	h.Remote = gitRemote(h.Path)
+	h.Fingerprint = fingerprint(h.Path)
+}
+
+// fingerprint hashes the devenv files in dir, which move along with it.
+func fingerprint(dir string) string {
+	hash := sha256.New()
+	found := false
+	for _, name := range fingerprintFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		found = true
+		hash.Write([]byte(name + "\x00"))
+		hash.Write(data)
+		hash.Write([]byte{0})
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
+}
+
+// gitRemote returns the origin URL of the repository holding dir, followed
+// by dir's place in it for projects below the top, such as
+// git@github.com:acme/shop.git#services/api. It returns "" outside a
+// repository or without an origin.
+func gitRemote(dir string) string {
+	for top := dir; ; {
+		if gitDir := findGitDir(top); gitDir != "" {
+			url := originURL(gitDir)
+			if url == "" {
+				return ""
+			}
+			if rel, err := filepath.Rel(top, dir); err == nil && rel != "." {
+				url += "#" + filepath.ToSlash(rel)
+			}
+			return url
+		}
+		parent := filepath.Dir(top)
+		if parent == top {
+			return ""
+		}
+		top = parent
+	}
+}
+
+// findGitDir returns the git directory of a repository whose top is dir,
+// following the .git files of worktrees and submodules.
+func findGitDir(dir string) string {
+	path := filepath.Join(dir, ".git")
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return path
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	// Worktrees share the main repository's config
+	if common, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		c := strings.TrimSpace(string(common))
+		if !filepath.IsAbs(c) {
+			c = filepath.Join(gitDir, c)
+		}
+		return filepath.Clean(c)
+	}
+	return gitDir
+}
+
+// originURL reads the origin remote's URL from a git directory's config.
+func originURL(gitDir string) string {
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inOrigin := false
+	lines := bufio.NewScanner(f)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
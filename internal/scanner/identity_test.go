@@ -0,0 +1,61 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const gitConfig = `[core]
+	bare = false
+[remote "upstream"]
+	url = https://example.com/fork.git
+[remote "origin"]
+	url = git@example.com:acme/shop.git
+	fetch = +refs/heads/*:refs/remotes/origin/*
+`
+
+func TestGitRemote(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".git", "config"), gitConfig)
+	os.MkdirAll(filepath.Join(repo, "services", "api"), 0755)
+
+	if got := gitRemote(repo); got != "git@example.com:acme/shop.git" {
+		t.Errorf("gitRemote(top) = %q", got)
+	}
+	if got, want := gitRemote(filepath.Join(repo, "services", "api")), "git@example.com:acme/shop.git#services/api"; got != want {
+		t.Errorf("gitRemote(subdir) = %q, want %q", got, want)
+	}
+	if got := gitRemote(t.TempDir()); got != "" {
+		t.Errorf("gitRemote outside a repository = %q, want empty", got)
+	}
+}
+
+func TestGitRemoteFollowsWorktrees(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".git", "config"), gitConfig)
+	writeFile(t, filepath.Join(repo, ".git", "worktrees", "wt", "commondir"), "../..\n")
+
+	wt := t.TempDir()
+	writeFile(t, filepath.Join(wt, ".git"), "gitdir: "+filepath.Join(repo, ".git", "worktrees", "wt")+"\n")
+	if got := gitRemote(wt); got != "git@example.com:acme/shop.git" {
+		t.Errorf("gitRemote(worktree) = %q", got)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(a, "devenv.nix"), "{ services.postgres.enable = true; }")
+	writeFile(t, filepath.Join(b, "devenv.nix"), "{ services.postgres.enable = true; }")
+
+	if fingerprint(a) == "" || fingerprint(a) != fingerprint(b) {
+		t.Error("identical devenv files should have the same fingerprint")
+	}
+	writeFile(t, filepath.Join(b, "devenv.lock"), "{}")
+	if fingerprint(a) == fingerprint(b) {
+		t.Error("a different lock file should change the fingerprint")
+	}
+	if got := fingerprint(t.TempDir()); got != "" {
+		t.Errorf("fingerprint without devenv files = %q, want empty", got)
+	}
+}
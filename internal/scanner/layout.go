@@ -0,0 +1,103 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// Files that mark a directory as a devenv project.
+const (
+	devenvNix  = "devenv.nix"
+	devenvYAML = "devenv.yaml"
+	flakeNix   = "flake.nix"
+)
+
+// flakeMarkers are the ways a flake.nix builds a devenv shell.
+var flakeMarkers = [][]byte{
+	[]byte("devenv.lib.mkShell"),
+	[]byte("devenv.flakeModule"),
+}
+
+// Hit is a project found by a scan.
+type Hit struct {
+	Path        string
+	Kind        registry.Kind
+	Root        string // Project whose devenv runs this one, for KindImported
+	Remote      string // Git remote and the project's place in the repository
+	Fingerprint string // Hash of the project's devenv files
+}
+
+// importedDir is a directory a devenv.yaml imports, with the project
+// importing it.
+type importedDir struct {
+	dir  string
+	root string
+}
+
+// classify returns the project in dir, if any. imported are the local
+// imports of the projects above dir.
+func classify(dir string, l listing, imported []importedDir) (Hit, bool) {
+	if l.Flake && isDevenvFlake(dir) {
+		return Hit{Path: dir, Kind: registry.KindFlake}, true
+	}
+	if !l.Devenv && !l.DevenvYAML {
+		return Hit{}, false
+	}
+	// The innermost importer wins, as it's the one a monorepo runs
+	for i := len(imported) - 1; i >= 0; i-- {
+		if imported[i].dir == dir {
+			return Hit{Path: dir, Kind: registry.KindImported, Root: imported[i].root}, true
+		}
+	}
+	return Hit{Path: dir, Kind: registry.KindStandalone}, true
+}
+
+// isDevenvFlake reports whether the flake in dir builds a devenv shell,
+// rather than being a plain Nix flake.
+func isDevenvFlake(dir string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, flakeNix))
+	if err != nil {
+		return false
+	}
+	for _, marker := range flakeMarkers {
+		if bytes.Contains(data, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// localImports returns the directories dir's devenv.yaml imports from the
+// filesystem, such as ./services/api. Imports of flake inputs are skipped.
+func localImports(dir string) []importedDir {
+	data, err := os.ReadFile(filepath.Join(dir, devenvYAML))
+	if err != nil {
+		return nil
+	}
+	var doc struct {
+		Imports []string `yaml:"imports"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	var dirs []importedDir
+	for _, imp := range doc.Imports {
+		imp = strings.TrimPrefix(imp, "path:")
+		switch {
+		case filepath.IsAbs(imp):
+		case strings.HasPrefix(imp, "./"), strings.HasPrefix(imp, "../"):
+			imp = filepath.Join(dir, imp)
+		default:
+			continue // An input, like devenv/languages
+		}
+		dirs = append(dirs, importedDir{dir: filepath.Clean(imp), root: dir})
+	}
+	return dirs
+}
@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanClassifiesLayouts(t *testing.T) {
+	tmpDir := t.TempDir()
+	join := func(parts ...string) string { return filepath.Join(append([]string{tmpDir}, parts...)...) }
+
+	writeFile(t, join("plain", "devenv.nix"), "{}")
+	writeFile(t, join("yaml-only", "devenv.yaml"), "inputs: {}\n")
+	writeFile(t, join("flake", "flake.nix"), "{ outputs = { devenv, ... }: { devShells.x.default = devenv.lib.mkShell { }; }; }")
+	writeFile(t, join("nix-flake", "flake.nix"), "{ outputs = { ... }: { }; }")
+	writeFile(t, join("mono", "devenv.yaml"), "imports:\n  - ./services/api\n  - devenv/languages\n")
+	writeFile(t, join("mono", "devenv.nix"), "{}")
+	writeFile(t, join("mono", "services", "api", "devenv.nix"), "{}")
+	writeFile(t, join("mono", "services", "web", "devenv.nix"), "{}")
+
+	hits, err := ScanWith(context.Background(), []string{tmpDir}, Options{MaxDepth: 3})
+	if err != nil {
+		t.Fatalf("ScanWith() error: %v", err)
+	}
+	want := []Hit{
+		{Path: join("flake"), Kind: registry.KindFlake},
+		{Path: join("mono"), Kind: registry.KindStandalone},
+		{Path: join("mono", "services", "api"), Kind: registry.KindImported, Root: join("mono")},
+		{Path: join("mono", "services", "web"), Kind: registry.KindStandalone},
+		{Path: join("plain"), Kind: registry.KindStandalone},
+		{Path: join("yaml-only"), Kind: registry.KindStandalone},
+	}
+	for i := range hits {
+		hits[i].Remote, hits[i].Fingerprint = "", "" // Covered by identity_test.go
+	}
+	if !reflect.DeepEqual(hits, want) {
+		t.Errorf("hits =\n%v\nwant\n%v", hits, want)
+	}
+}
+
+func TestLocalImports(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "devenv.yaml"), "imports:\n  - ./a\n  - path:./b/\n  - ../c\n  - /abs/d\n  - nixpkgs\n")
+
+	var got []string
+	for _, imp := range localImports(dir) {
+		if imp.root != dir {
+			t.Errorf("root = %q, want %q", imp.root, dir)
+		}
+		got = append(got, imp.dir)
+	}
+	want := []string{
+		filepath.Join(dir, "a"),
+		filepath.Join(dir, "b"),
+		filepath.Join(filepath.Dir(dir), "c"),
+		"/abs/d",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("localImports() = %v, want %v", got, want)
+	}
+}
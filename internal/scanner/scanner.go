@@ -2,29 +2,81 @@
 package scanner
 
 import (
-	"io/fs"
+	"context"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 )
 
-// Directories to skip during scanning.
-var excludedDirs = map[string]bool{
-	"node_modules": true,
-	".git":         true,
-	".direnv":      true,
-	"dist":         true,
-	"target":       true,
-	"vendor":       true,
-	".venv":        true,
-	"__pycache__":  true,
+// DefaultExcludes are the directories skipped unless configured otherwise.
+var DefaultExcludes = []string{
+	"node_modules", ".git", ".direnv", ".devenv", "dist", "target", "vendor", ".venv", "__pycache__",
 }
 
-// Scan searches paths for directories containing devenv.nix.
+// Options tunes a scan.
+type Options struct {
+	MaxDepth int      // How deep to recurse (1 = immediate children only)
+	Exclude  []string // Directory globs to skip; nil means DefaultExcludes
+	Cache    *Cache   // Listings from earlier scans; nil reads every directory
+	Workers  int      // Directories read at once; 0 picks from the CPU count
+
+	// OnDir, if set, is called with each directory the scan looks in, such
+	// as for watching them. Like found, it's never called concurrently.
+	OnDir func(dir string)
+}
+
+// Scan searches paths for devenv projects, returning their directories.
 // maxDepth limits how deep to recurse (1 = immediate children only).
 func Scan(paths []string, maxDepth int) ([]string, error) {
-	var projects []string
-	seen := make(map[string]bool)
+	hits, err := ScanWith(context.Background(), paths, Options{MaxDepth: maxDepth})
+	projects := make([]string, len(hits))
+	for i, h := range hits {
+		projects[i] = h.Path
+	}
+	return projects, err
+}
+
+// ScanWith is like Scan with options, returning the projects sorted by path
+// along with their layouts.
+func ScanWith(ctx context.Context, paths []string, opts Options) ([]Hit, error) {
+	var hits []Hit
+	err := Walk(ctx, paths, opts, func(h Hit) {
+		hits = append(hits, h)
+	})
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Path < hits[j].Path })
+	return hits, err
+}
+
+// Walk searches paths for devenv projects, calling found for each project
+// as soon as it's discovered. A project is a directory with a devenv.nix or
+// devenv.yaml, or a flake.nix that uses devenv; directories imported by a
+// devenv.yaml above them are reported as imported by it. Roots and subdirectories are
+// read concurrently, but found is never called concurrently. Directories
+// matching opts.Exclude or ignored by a .gitignore are skipped. Walk returns
+// early with ctx's error if ctx is cancelled.
+func Walk(ctx context.Context, paths []string, opts Options, found func(Hit)) error {
+	w := &walker{
+		ctx:     ctx,
+		opts:    opts,
+		exclude: opts.Exclude,
+		cache:   opts.Cache,
+		seen:    make(map[string]bool),
+		found:   found,
+	}
+	if w.exclude == nil {
+		w.exclude = DefaultExcludes
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 4 * runtime.NumCPU()
+	}
+	w.sem = make(chan struct{}, workers)
+	if w.cache != nil {
+		w.cache.begin()
+	}
 
 	for _, root := range paths {
 		// Expand ~ if present
@@ -35,55 +87,150 @@ func Scan(paths []string, maxDepth int) ([]string, error) {
 			}
 			root = filepath.Join(home, root[2:])
 		}
+		w.wg.Add(1)
+		go w.visit(filepath.Clean(root), 0, nil, nil)
+	}
+	w.wg.Wait()
 
-		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return nil // Skip inaccessible paths
-			}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if w.cache != nil {
+		w.cache.prune()
+	}
+	return nil
+}
 
-			// Calculate depth relative to root
-			rel, err := filepath.Rel(root, path)
-			if err != nil {
-				return nil // Skip paths with invalid relative paths
-			}
-			depth := len(strings.Split(rel, string(os.PathSeparator)))
-			if rel == "." {
-				depth = 0
-			}
+// walker is the state of one Walk.
+type walker struct {
+	ctx     context.Context
+	opts    Options
+	exclude []string
+	cache   *Cache
+	sem     chan struct{} // Limits concurrent directory reads
+	wg      sync.WaitGroup
 
-			// For directories, skip if too deep
-			if d.IsDir() {
-				if depth > maxDepth {
-					return fs.SkipDir
-				}
-				// Skip excluded directories
-				if excludedDirs[d.Name()] {
-					return fs.SkipDir
-				}
-				return nil
-			}
+	mu    sync.Mutex
+	seen  map[string]bool
+	found func(Hit)
+}
 
-			// For files, check depth of parent directory (depth - 1)
-			parentDepth := depth - 1
-			if parentDepth > maxDepth {
-				return nil
-			}
+// visit scans dir, at depth below its root, and its subdirectories. rules
+// are the .gitignore rules of dir's ancestors, and imported the directories
+// their devenv.yaml files import.
+func (w *walker) visit(dir string, depth int, rules ignoreRules, imported []importedDir) {
+	defer w.wg.Done()
+	if w.ctx.Err() != nil {
+		return
+	}
 
-			// Check for devenv.nix
-			if d.Name() == "devenv.nix" {
-				projectPath := filepath.Dir(path)
-				if !seen[projectPath] {
-					seen[projectPath] = true
-					projects = append(projects, projectPath)
+	w.sem <- struct{}{}
+	l, ok := w.list(dir)
+	var hit Hit
+	found := false
+	if ok {
+		if hit, found = classify(dir, l, imported); found {
+			identify(&hit)
+		}
+		// Copy so sibling directories don't share the appended entries
+		if l.Gitignore && depth < w.opts.MaxDepth {
+			rules = append(rules[:len(rules):len(rules)], readGitignore(dir)...)
+		}
+		if l.DevenvYAML && depth < w.opts.MaxDepth {
+			imported = append(imported[:len(imported):len(imported)], localImports(dir)...)
+		}
+	}
+	<-w.sem
+	if !ok {
+		return // Skip inaccessible paths
+	}
+
+	if w.opts.OnDir != nil {
+		w.mu.Lock()
+		w.opts.OnDir(dir)
+		w.mu.Unlock()
+	}
+	if found {
+		w.report(hit)
+	}
+	if depth >= w.opts.MaxDepth {
+		return
+	}
+	for _, name := range l.Dirs {
+		child := filepath.Join(dir, name)
+		if w.excluded(name, child) || rules.ignored(child, true) {
+			continue
+		}
+		w.wg.Add(1)
+		go w.visit(child, depth+1, rules, imported)
+	}
+}
+
+// list returns what the scan needs from dir, from the cache if dir hasn't
+// changed since it was cached.
+func (w *walker) list(dir string) (listing, bool) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return listing{}, false
+	}
+	if w.cache != nil {
+		if l, ok := w.cache.get(dir, info.ModTime()); ok {
+			return l, true
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return listing{}, false
+	}
+	l := listing{ModTime: info.ModTime()}
+	for _, e := range entries {
+		switch {
+		case e.IsDir():
+			l.Dirs = append(l.Dirs, e.Name())
+		case e.Name() == devenvNix:
+			l.Devenv = true
+		case e.Name() == devenvYAML:
+			l.DevenvYAML = true
+		case e.Name() == flakeNix:
+			l.Flake = true
+		case e.Name() == ".gitignore":
+			l.Gitignore = true
+		}
+	}
+	if w.cache != nil {
+		w.cache.put(dir, l)
+	}
+	return l, true
+}
+
+// excluded reports whether a directory matches the exclude list. Patterns
+// with a slash match the whole path, others the directory name.
+func (w *walker) excluded(name, path string) bool {
+	for _, pattern := range w.exclude {
+		target := name
+		if strings.Contains(pattern, "/") {
+			target = path
+			if rest, ok := strings.CutPrefix(pattern, "~/"); ok {
+				if home, err := os.UserHomeDir(); err == nil {
+					pattern = filepath.Join(home, rest)
 				}
 			}
-
-			return nil
-		})
-		if err != nil {
-			return projects, err
+		}
+		if ok, _ := filepath.Match(pattern, target); ok {
+			return true
 		}
 	}
+	return false
+}
 
-	return projects, nil
+// report passes a newly found project to the callback.
+func (w *walker) report(h Hit) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.seen[h.Path] {
+		return
+	}
+	w.seen[h.Path] = true
+	w.found(h)
 }
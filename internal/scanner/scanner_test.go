@@ -1,8 +1,12 @@
 package scanner
 
 import (
+	"context"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -58,3 +62,116 @@ func TestScanSkipsExcludedDirs(t *testing.T) {
 		t.Errorf("Expected 0 projects (node_modules excluded), got %d", len(projects))
 	}
 }
+
+func writeProject(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "devenv.nix"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanWithExcludes(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeProject(t, filepath.Join(tmpDir, "app"))
+	writeProject(t, filepath.Join(tmpDir, "archive", "old"))
+	writeProject(t, filepath.Join(tmpDir, "work", "tmp-scratch"))
+	writeProject(t, filepath.Join(tmpDir, "node_modules", "pkg"))
+
+	hits, err := ScanWith(context.Background(), []string{tmpDir}, Options{
+		MaxDepth: 3,
+		Exclude:  []string{"tmp-*", filepath.Join(tmpDir, "archive")},
+	})
+	if err != nil {
+		t.Fatalf("ScanWith() error: %v", err)
+	}
+	var projects []string
+	for _, h := range hits {
+		projects = append(projects, h.Path)
+	}
+	want := []string{filepath.Join(tmpDir, "app"), filepath.Join(tmpDir, "node_modules", "pkg")}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("projects = %v, want %v (a configured list replaces the defaults)", projects, want)
+	}
+}
+
+func TestScanHonorsGitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.WriteFile(filepath.Join(tmpDir, ".gitignore"), []byte("# build output\nout/\n/generated\n"), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "repo"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "repo", ".gitignore"), []byte("fixtures\n!fixtures/keep\n"), 0644)
+	writeProject(t, filepath.Join(tmpDir, "repo", "app"))
+	writeProject(t, filepath.Join(tmpDir, "repo", "out", "copy"))
+	writeProject(t, filepath.Join(tmpDir, "generated"))
+	writeProject(t, filepath.Join(tmpDir, "repo", "generated"))
+	writeProject(t, filepath.Join(tmpDir, "repo", "fixtures"))
+
+	projects, _ := Scan([]string{tmpDir}, 3)
+	want := []string{
+		filepath.Join(tmpDir, "repo", "app"),
+		filepath.Join(tmpDir, "repo", "generated"), // /generated only matches at the top
+	}
+	if !reflect.DeepEqual(projects, want) {
+		t.Errorf("projects = %v, want %v", projects, want)
+	}
+}
+
+func TestWalkScansRootsTogether(t *testing.T) {
+	var roots []string
+	var want []string
+	for i := 0; i < 5; i++ {
+		root := t.TempDir()
+		writeProject(t, root) // A root can be a project itself
+		writeProject(t, filepath.Join(root, "nested"))
+		roots = append(roots, root)
+		want = append(want, root, filepath.Join(root, "nested"))
+	}
+	roots = append(roots, roots[0]) // Listed twice
+
+	var got []string
+	err := Walk(context.Background(), roots, Options{MaxDepth: 2, Workers: 2}, func(h Hit) {
+		got = append(got, h.Path) // Never called concurrently
+	})
+	if err != nil {
+		t.Fatalf("Walk() error: %v", err)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("found %v, want %v", got, want)
+	}
+}
+
+func TestWalkStopsWhenCancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeProject(t, filepath.Join(tmpDir, "app"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Walk(ctx, []string{tmpDir}, Options{MaxDepth: 3}, func(Hit) {
+		t.Error("a cancelled walk shouldn't find anything")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Walk() = %v, want context.Canceled", err)
+	}
+}
+
+func TestWalkReportsDirs(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeProject(t, filepath.Join(tmpDir, "app"))
+	os.MkdirAll(filepath.Join(tmpDir, "app", ".devenv", "state"), 0755)
+	os.MkdirAll(filepath.Join(tmpDir, "a", "b", "c"), 0755)
+
+	var dirs []string
+	opts := Options{MaxDepth: 2, OnDir: func(dir string) { dirs = append(dirs, dir) }}
+	if err := Walk(context.Background(), []string{tmpDir}, opts, func(Hit) {}); err != nil {
+		t.Fatalf("Walk() error: %v", err)
+	}
+	sort.Strings(dirs)
+	want := []string{tmpDir, filepath.Join(tmpDir, "a"), filepath.Join(tmpDir, "a", "b"), filepath.Join(tmpDir, "app")}
+	if !reflect.DeepEqual(dirs, want) {
+		t.Errorf("dirs = %v, want %v (excluded and too deep directories left out)", dirs, want)
+	}
+}
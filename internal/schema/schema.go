@@ -0,0 +1,302 @@
+// Package schema versions devdash's YAML files. It upgrades files written
+// by older versions in place, keeping a backup, and reports keys that the
+// current version doesn't know about.
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/infktd/devdash/internal/fsutil"
+)
+
+// ErrTooNew is returned for files written by a newer devdash.
+var ErrTooNew = errors.New("file was written by a newer devdash")
+
+// Migration upgrades a file by one version. Apply edits the file's
+// top-level mapping node in place, so comments and key order survive.
+type Migration struct {
+	Description string
+	Apply       func(doc *yaml.Node) error
+}
+
+// Result describes an upgrade.
+type Result struct {
+	From, To int
+	Backup   string // Copy of the file before the upgrade
+}
+
+// String describes the upgrade for the user.
+func (r Result) String() string {
+	return fmt.Sprintf("upgraded from version %d to %d (backup: %s)", r.From, r.To, r.Backup)
+}
+
+// Version returns the version field of a YAML document, 0 if it has none.
+func Version(data []byte) (int, error) {
+	var head struct {
+		Version int `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &head); err != nil {
+		return 0, err
+	}
+	return head.Version, nil
+}
+
+// Upgrade brings the file at path, whose contents are data, up to the
+// current version, len(migrations); migrations[i] upgrades version i to
+// i+1. The original is copied to path.v<N>.bak before the upgraded file is
+// written. It returns the upgraded contents, and a nil Result if the file
+// was already current. The file is locked with fsutil.Lock while it is
+// upgraded.
+func Upgrade(path string, data []byte, migrations []Migration) ([]byte, *Result, error) {
+	current, err := check(data, migrations)
+	if err != nil || current {
+		return data, nil, err
+	}
+
+	lock, err := fsutil.Lock(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer lock.Unlock()
+
+	// Another instance may have upgraded the file while we waited
+	if onDisk, err := os.ReadFile(path); err == nil {
+		data = onDisk
+	}
+	return UpgradeLocked(path, data, migrations)
+}
+
+// UpgradeLocked is like Upgrade for callers that already hold
+// fsutil.Lock(path), such as to read the upgraded file under the same lock.
+func UpgradeLocked(path string, data []byte, migrations []Migration) ([]byte, *Result, error) {
+	current, err := check(data, migrations)
+	if err != nil || current {
+		return data, nil, err
+	}
+	version, _ := Version(data)
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, err
+	}
+	if root.Kind == 0 {
+		// Empty file
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("expected a mapping at the top level, got %s", doc.Tag)
+	}
+	for v := version; v < len(migrations); v++ {
+		if err := migrations[v].Apply(doc); err != nil {
+			return nil, nil, fmt.Errorf("migrating to version %d (%s): %w", v+1, migrations[v].Description, err)
+		}
+	}
+	setVersion(doc, len(migrations))
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return nil, nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	upgraded := buf.Bytes()
+	backup := fmt.Sprintf("%s.v%d.bak", path, version)
+	if err := fsutil.WriteFile(backup, data, 0644); err != nil {
+		return nil, nil, err
+	}
+	if err := fsutil.WriteFile(path, upgraded, 0644); err != nil {
+		return nil, nil, err
+	}
+	return upgraded, &Result{From: version, To: len(migrations), Backup: backup}, nil
+}
+
+// check reports whether data is at the current version, len(migrations),
+// failing if it is newer.
+func check(data []byte, migrations []Migration) (bool, error) {
+	version, err := Version(data)
+	if err != nil {
+		return false, err
+	}
+	if version > len(migrations) {
+		return false, fmt.Errorf("%w: version %d, this one supports up to %d", ErrTooNew, version, len(migrations))
+	}
+	return version == len(migrations), nil
+}
+
+// setVersion sets the version key of doc, adding it as the first key if the
+// file has none.
+func setVersion(doc *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if v := Lookup(doc, "version"); v != nil {
+		*v = *value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "version"}
+	doc.Content = append([]*yaml.Node{key, value}, doc.Content...)
+}
+
+// Lookup returns the value of key in mapping node m, or nil.
+func Lookup(m *yaml.Node, key string) *yaml.Node {
+	if m == nil || m.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// SetString sets key in mapping node m to a string value, appending the key
+// if m doesn't have it.
+func SetString(m *yaml.Node, key, value string) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if v := Lookup(m, key); v != nil {
+		*v = *node
+		return
+	}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, node)
+}
+
+// Key is a key in a YAML file, by its dotted path (such as ui.theme or
+// probes[1].tcp) and position.
+type Key struct {
+	Path         string
+	Line, Column int
+}
+
+// String describes k as an unknown key.
+func (k Key) String() string {
+	return fmt.Sprintf("line %d: unknown key %s", k.Line, k.Path)
+}
+
+// UnknownKeys lists the keys in data that don't map to a field of v, a
+// pointer to the struct data decodes into.
+func UnknownKeys(data []byte, v any) []Key {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	var out []Key
+	walk(&root, reflect.TypeOf(v), "", &out)
+	return out
+}
+
+// Positions maps the path of every value in data, in the form Key uses, to
+// the position of the value.
+func Positions(data []byte) map[string]Key {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	positions := make(map[string]Key)
+	index(&root, "", positions)
+	return positions
+}
+
+func index(node *yaml.Node, path string, positions map[string]Key) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, n := range node.Content {
+			index(n, path, positions)
+		}
+		return
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			index(node.Content[i+1], join(path, node.Content[i].Value), positions)
+		}
+	case yaml.SequenceNode:
+		for i, n := range node.Content {
+			index(n, fmt.Sprintf("%s[%d]", path, i), positions)
+		}
+	}
+	positions[path] = Key{Path: path, Line: node.Line, Column: node.Column}
+}
+
+func walk(node *yaml.Node, t reflect.Type, path string, out *[]Key) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.DocumentNode {
+		for _, n := range node.Content {
+			walk(n, t, path, out)
+		}
+		return
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode:
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keyPath := join(path, key.Value)
+			ft, ok := fields[key.Value]
+			if !ok {
+				*out = append(*out, Key{Path: keyPath, Line: key.Line, Column: key.Column})
+				continue
+			}
+			walk(value, ft, keyPath, out)
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			walk(node.Content[i+1], t.Elem(), join(path, node.Content[i].Value), out)
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && node.Kind == yaml.SequenceNode:
+		for i, n := range node.Content {
+			walk(n, t.Elem(), fmt.Sprintf("%s[%d]", path, i), out)
+		}
+	}
+}
+
+// yamlFields maps the YAML keys of struct t to their field types, the way
+// yaml.v3 names them.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for k, v := range yamlFields(ft) {
+					fields[k] = v
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
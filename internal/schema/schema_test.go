@@ -0,0 +1,194 @@
+package schema
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+var testMigrations = []Migration{
+	{Description: "add version field", Apply: func(doc *yaml.Node) error { return nil }},
+	{Description: "rename width to sidebar_width", Apply: func(doc *yaml.Node) error {
+		for i := 0; i+1 < len(doc.Content); i += 2 {
+			if doc.Content[i].Value == "width" {
+				doc.Content[i].Value = "sidebar_width"
+			}
+		}
+		return nil
+	}},
+}
+
+func writeFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestUpgrade(t *testing.T) {
+	original := "width: 30\n"
+	path := writeFile(t, original)
+
+	data, result, err := Upgrade(path, []byte(original), testMigrations)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if result == nil || result.From != 0 || result.To != 2 {
+		t.Fatalf("Upgrade() result = %+v, want 0 -> 2", result)
+	}
+	if !strings.Contains(string(data), "sidebar_width: 30") || !strings.Contains(string(data), "version: 2") {
+		t.Errorf("unexpected upgraded file:\n%s", data)
+	}
+
+	onDisk, _ := os.ReadFile(path)
+	if string(onDisk) != string(data) {
+		t.Error("the upgraded file should be written in place")
+	}
+	backup, err := os.ReadFile(path + ".v0.bak")
+	if err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v; want the original", backup, err)
+	}
+
+	// Upgrading again is a no-op
+	again, result, err := Upgrade(path, data, testMigrations)
+	if err != nil || result != nil || string(again) != string(data) {
+		t.Errorf("second Upgrade() = %q, %+v, %v; want unchanged", again, result, err)
+	}
+}
+
+func TestUpgradeKeepsCommentsAndOrder(t *testing.T) {
+	original := `# devdash config
+ui:
+  theme: nord # my favourite
+# notifications:
+#   critical_only: true
+width: 30
+`
+	path := writeFile(t, original)
+
+	data, _, err := Upgrade(path, []byte(original), testMigrations)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	want := `version: 2
+# devdash config
+ui:
+  theme: nord # my favourite
+# notifications:
+#   critical_only: true
+sidebar_width: 30
+`
+	if string(data) != want {
+		t.Errorf("upgraded file:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestUpgradeFromMiddleVersion(t *testing.T) {
+	original := "version: 1\nwidth: 30\n"
+	path := writeFile(t, original)
+
+	_, result, err := Upgrade(path, []byte(original), testMigrations)
+	if err != nil {
+		t.Fatalf("Upgrade() error = %v", err)
+	}
+	if result.From != 1 || !strings.HasSuffix(result.Backup, ".v1.bak") {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestUpgradeTooNew(t *testing.T) {
+	data := "version: 3\n"
+	path := writeFile(t, data)
+
+	_, _, err := Upgrade(path, []byte(data), testMigrations)
+	if !errors.Is(err, ErrTooNew) {
+		t.Errorf("Upgrade() error = %v, want ErrTooNew", err)
+	}
+}
+
+func TestUpgradeFailedMigration(t *testing.T) {
+	data := "a: 1\n"
+	path := writeFile(t, data)
+	failing := []Migration{{Description: "fail", Apply: func(*yaml.Node) error {
+		return errors.New("boom")
+	}}}
+
+	if _, _, err := Upgrade(path, []byte(data), failing); err == nil {
+		t.Fatal("Upgrade() should fail")
+	}
+	if onDisk, _ := os.ReadFile(path); string(onDisk) != data {
+		t.Error("a failed upgrade should leave the file alone")
+	}
+}
+
+type testConfig struct {
+	Version int `yaml:"version"`
+	UI      struct {
+		Theme string `yaml:"theme"`
+	} `yaml:"ui"`
+	Headers map[string]struct {
+		Value string `yaml:"value"`
+	} `yaml:"headers"`
+	Probes []struct {
+		Service string `yaml:"service"`
+	} `yaml:"probes"`
+	Ignored string `yaml:"-"`
+}
+
+func TestUnknownKeys(t *testing.T) {
+	data := `version: 1
+ui:
+  theme: nord
+  them: typo
+headers:
+  auth:
+    value: x
+    vale: y
+probes:
+  - service: web
+  - servce: api
+Ignored: x
+extra: true
+`
+	var got []string
+	for _, k := range UnknownKeys([]byte(data), &testConfig{}) {
+		got = append(got, k.String())
+	}
+	want := []string{
+		"line 4: unknown key ui.them",
+		"line 8: unknown key headers.auth.vale",
+		"line 11: unknown key probes[1].servce",
+		"line 12: unknown key Ignored",
+		"line 13: unknown key extra",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownKeys() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestPositions(t *testing.T) {
+	data := `ui:
+  theme: nordd
+scan_paths:
+  - ~/code
+  - ~/missing
+`
+	positions := Positions([]byte(data))
+	tests := map[string]Key{
+		"ui.theme":      {Path: "ui.theme", Line: 2, Column: 10},
+		"scan_paths[1]": {Path: "scan_paths[1]", Line: 5, Column: 5},
+		"ui":            {Path: "ui", Line: 2, Column: 3},
+	}
+	for path, want := range tests {
+		if got := positions[path]; got != want {
+			t.Errorf("Positions()[%q] = %+v, want %+v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+// Package stack starts, stops and restarts project groups as a whole.
+//
+// Projects start in the group's start order and stop in reverse. With the
+// group's wait gate set, each project must be running before the next one
+// starts. Each project must be down before the next one stops.
+package stack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/infktd/devdash/internal/devenv"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// DefaultPollInterval is how often the wait gate checks a project's state.
+const DefaultPollInterval = time.Second
+
+// Results of a step, as reported to OnStep and in Result.
+const (
+	Starting       = "starting"
+	Waiting        = "waiting"
+	Started        = "started"
+	AlreadyRunning = "already running"
+	Stopping       = "stopping"
+	Stopped        = "stopped"
+	NotRunning     = "not running"
+)
+
+// Result is the outcome of an action on one project of a group.
+type Result struct {
+	Project *registry.Project
+	Result  string
+}
+
+// Runner runs group actions. The zero value uses devenv and real project
+// state.
+type Runner struct {
+	// StartProject starts a project; defaults to devenv.Up.
+	StartProject func(p *registry.Project) error
+	// StopProject stops a project; defaults to devenv.Shutdown.
+	StopProject func(p *registry.Project) error
+	// State reports a project's state; defaults to Project.DetectState.
+	State func(p *registry.Project) registry.ProjectState
+	// PollInterval is how often the wait gate checks state.
+	PollInterval time.Duration
+	// OnStep, if set, is called before each step with the project and
+	// one of Starting, Waiting or Stopping.
+	OnStep func(p *registry.Project, step string)
+}
+
+// Start starts every project of g that isn't running, in start order.
+// It stops at the first failure and returns the results so far.
+func (r *Runner) Start(ctx context.Context, reg *registry.Registry, g *registry.Group) ([]Result, error) {
+	projects, err := resolve(reg, g, (*registry.Group).StartOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, p := range projects {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result := AlreadyRunning
+		if !r.state(p).IsActive() {
+			r.step(p, Starting)
+			if err := r.start(p); err != nil {
+				return results, fmt.Errorf("starting %s: %w", p.Name, err)
+			}
+			result = Started
+		}
+		if g.Wait {
+			r.step(p, Waiting)
+			if err := r.waitRunning(ctx, p, groupTimeout(g)); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, Result{Project: p, Result: result})
+	}
+	return results, nil
+}
+
+// Stop stops every running project of g, in reverse start order.
+// It stops at the first failure and returns the results so far.
+func (r *Runner) Stop(ctx context.Context, reg *registry.Registry, g *registry.Group) ([]Result, error) {
+	projects, err := resolve(reg, g, (*registry.Group).StopOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, p := range projects {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result := NotRunning
+		if r.state(p).IsActive() {
+			r.step(p, Stopping)
+			if err := r.stop(p); err != nil {
+				return results, fmt.Errorf("stopping %s: %w", p.Name, err)
+			}
+			// Shutdown returns before process-compose exits
+			if err := r.waitStopped(ctx, p, groupTimeout(g)); err != nil {
+				return results, err
+			}
+			result = Stopped
+		}
+		results = append(results, Result{Project: p, Result: result})
+	}
+	return results, nil
+}
+
+// Restart stops and then starts the whole group. The results are those
+// of the start.
+func (r *Runner) Restart(ctx context.Context, reg *registry.Registry, g *registry.Group) ([]Result, error) {
+	if results, err := r.Stop(ctx, reg, g); err != nil {
+		return results, err
+	}
+	return r.Start(ctx, reg, g)
+}
+
+// resolve looks up the group's projects in the order given by order.
+func resolve(reg *registry.Registry, g *registry.Group, order func(*registry.Group) ([]string, error)) ([]*registry.Project, error) {
+	paths, err := order(g)
+	if err != nil {
+		return nil, err
+	}
+	projects := make([]*registry.Project, 0, len(paths))
+	for _, path := range paths {
+		p := reg.FindByPath(path)
+		if p == nil {
+			return nil, fmt.Errorf("group %s: project %s is not in the registry", g.Name, path)
+		}
+		projects = append(projects, p)
+	}
+	return projects, nil
+}
+
+// waitRunning polls until p is fully running, timeout passes or ctx is done.
+func (r *Runner) waitRunning(ctx context.Context, p *registry.Project, timeout time.Duration) error {
+	return r.wait(ctx, p, timeout, "running", func(s registry.ProjectState) bool {
+		return s == registry.StateRunning
+	})
+}
+
+// waitStopped polls until p is no longer active, timeout passes or ctx is
+// done.
+func (r *Runner) waitStopped(ctx context.Context, p *registry.Project, timeout time.Duration) error {
+	return r.wait(ctx, p, timeout, "stopped", func(s registry.ProjectState) bool {
+		return !s.IsActive()
+	})
+}
+
+// wait polls p's state until done reports true, timeout passes or ctx is
+// done. want describes the awaited state in the timeout error.
+func (r *Runner) wait(ctx context.Context, p *registry.Project, timeout time.Duration, want string, done func(registry.ProjectState) bool) error {
+	interval := r.PollInterval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		state := r.state(p)
+		if done(state) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%s not %s after %s (%s)", p.Name, want, timeout, state)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func groupTimeout(g *registry.Group) time.Duration {
+	if g.Timeout > 0 {
+		return time.Duration(g.Timeout) * time.Second
+	}
+	return registry.DefaultGroupTimeout * time.Second
+}
+
+func (r *Runner) step(p *registry.Project, step string) {
+	if r.OnStep != nil {
+		r.OnStep(p, step)
+	}
+}
+
+func (r *Runner) start(p *registry.Project) error {
+	if r.StartProject != nil {
+		return r.StartProject(p)
+	}
+	return devenv.Up(p.Dir(), p.Kind)
+}
+
+func (r *Runner) stop(p *registry.Project) error {
+	if r.StopProject != nil {
+		return r.StopProject(p)
+	}
+	return devenv.Shutdown(p.SocketPath(), p.Dir(), p.Kind)
+}
+
+func (r *Runner) state(p *registry.Project) registry.ProjectState {
+	if r.State != nil {
+		return r.State(p)
+	}
+	return p.DetectState()
+}
@@ -0,0 +1,248 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// fakeStack records actions and keeps project states in memory.
+type fakeStack struct {
+	mu      sync.Mutex
+	states  map[string]registry.ProjectState
+	actions []string
+	// pending makes a started project report Starting for that many polls
+	pending int
+	polls   map[string]int
+	// lingering keeps a stopped project Running for that many polls, the
+	// way process-compose outlives its shutdown request
+	lingering int
+	stopping  map[string]int
+	failOn    string
+}
+
+func newFakeStack() *fakeStack {
+	return &fakeStack{
+		states:   make(map[string]registry.ProjectState),
+		polls:    make(map[string]int),
+		stopping: make(map[string]int),
+	}
+}
+
+func (f *fakeStack) runner() *Runner {
+	return &Runner{
+		StartProject: func(p *registry.Project) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if p.Name == f.failOn {
+				return errors.New("boom")
+			}
+			f.actions = append(f.actions, "start "+p.Name)
+			f.states[p.Path] = registry.StateStarting
+			return nil
+		},
+		StopProject: func(p *registry.Project) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.actions = append(f.actions, "stop "+p.Name)
+			if f.lingering > 0 {
+				f.stopping[p.Path] = f.lingering
+			} else {
+				f.states[p.Path] = registry.StateIdle
+			}
+			return nil
+		},
+		State: func(p *registry.Project) registry.ProjectState {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if n, ok := f.stopping[p.Path]; ok {
+				if n > 0 {
+					f.stopping[p.Path]--
+					return f.states[p.Path]
+				}
+				delete(f.stopping, p.Path)
+				f.states[p.Path] = registry.StateIdle
+			}
+			if f.states[p.Path] == registry.StateStarting {
+				f.polls[p.Path]++
+				if f.polls[p.Path] > f.pending {
+					f.states[p.Path] = registry.StateRunning
+				}
+			}
+			return f.states[p.Path]
+		},
+		PollInterval: time.Millisecond,
+	}
+}
+
+// testGroup is frontend, api, auth, with api starting after auth.
+func testGroup() (*registry.Registry, *registry.Group) {
+	reg := &registry.Registry{}
+	for _, name := range []string{"frontend", "api", "auth"} {
+		reg.AddProject("/src/" + name)
+	}
+	g := reg.AddGroup("product", []string{"/src/frontend", "/src/api", "/src/auth"})
+	g.After = map[string][]string{"/src/api": {"/src/auth"}}
+	return reg, g
+}
+
+func names(results []Result) []string {
+	var out []string
+	for _, r := range results {
+		out = append(out, r.Project.Name+" "+r.Result)
+	}
+	return out
+}
+
+func TestStartFollowsStartOrder(t *testing.T) {
+	reg, g := testGroup()
+	f := newFakeStack()
+	f.states["/src/frontend"] = registry.StateRunning
+
+	results, err := f.runner().Start(context.Background(), reg, g)
+	if err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	want := []string{"frontend already running", "auth started", "api started"}
+	if !reflect.DeepEqual(names(results), want) {
+		t.Errorf("results = %v, want %v", names(results), want)
+	}
+	if !reflect.DeepEqual(f.actions, []string{"start auth", "start api"}) {
+		t.Errorf("actions = %v", f.actions)
+	}
+}
+
+func TestStartWaitsForEachProject(t *testing.T) {
+	reg, g := testGroup()
+	g.Wait = true
+	f := newFakeStack()
+	f.pending = 3
+
+	var steps []string
+	r := f.runner()
+	r.OnStep = func(p *registry.Project, step string) {
+		steps = append(steps, step+" "+p.Name)
+	}
+	if _, err := r.Start(context.Background(), reg, g); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	want := []string{
+		"starting frontend", "waiting frontend",
+		"starting auth", "waiting auth",
+		"starting api", "waiting api",
+	}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("steps = %v, want %v", steps, want)
+	}
+	for path, state := range f.states {
+		if state != registry.StateRunning {
+			t.Errorf("%s is %v after a waited start", path, state)
+		}
+	}
+}
+
+func TestStartWaitTimesOut(t *testing.T) {
+	reg, g := testGroup()
+	g.Wait = true
+	g.Timeout = 1
+	f := newFakeStack()
+	f.pending = 1 << 30 // Never becomes running
+
+	r := f.runner()
+	r.PollInterval = 100 * time.Millisecond
+	results, err := r.Start(context.Background(), reg, g)
+	if err == nil || !strings.Contains(err.Error(), "frontend not running") {
+		t.Fatalf("Start() error = %v, want a timeout for frontend", err)
+	}
+	if len(results) != 0 || len(f.actions) != 1 {
+		t.Errorf("nothing after frontend should start, got actions %v", f.actions)
+	}
+}
+
+func TestStartStopsAtFirstFailure(t *testing.T) {
+	reg, g := testGroup()
+	f := newFakeStack()
+	f.failOn = "auth"
+
+	results, err := f.runner().Start(context.Background(), reg, g)
+	if err == nil || !strings.Contains(err.Error(), "starting auth") {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !reflect.DeepEqual(names(results), []string{"frontend started"}) {
+		t.Errorf("results = %v", names(results))
+	}
+}
+
+func TestStopAndRestart(t *testing.T) {
+	reg, g := testGroup()
+	f := newFakeStack()
+	for _, p := range g.Projects {
+		f.states[p] = registry.StateRunning
+	}
+	f.states["/src/frontend"] = registry.StateIdle
+
+	results, err := f.runner().Stop(context.Background(), reg, g)
+	if err != nil {
+		t.Fatalf("Stop() error: %v", err)
+	}
+	want := []string{"api stopped", "auth stopped", "frontend not running"}
+	if !reflect.DeepEqual(names(results), want) {
+		t.Errorf("results = %v, want %v", names(results), want)
+	}
+
+	f.actions = nil
+	f.states["/src/auth"] = registry.StateRunning
+	if _, err := f.runner().Restart(context.Background(), reg, g); err != nil {
+		t.Fatalf("Restart() error: %v", err)
+	}
+	wantActions := []string{"stop auth", "start frontend", "start auth", "start api"}
+	if !reflect.DeepEqual(f.actions, wantActions) {
+		t.Errorf("actions = %v, want %v", f.actions, wantActions)
+	}
+}
+
+func TestRestartWaitsForShutdown(t *testing.T) {
+	reg, g := testGroup()
+	f := newFakeStack()
+	f.lingering = 3
+	for _, p := range g.Projects {
+		f.states[p] = registry.StateRunning
+	}
+
+	results, err := f.runner().Restart(context.Background(), reg, g)
+	if err != nil {
+		t.Fatalf("Restart() error: %v", err)
+	}
+	want := []string{"frontend started", "auth started", "api started"}
+	if !reflect.DeepEqual(names(results), want) {
+		t.Errorf("results = %v, want %v", names(results), want)
+	}
+}
+
+func TestStopTimesOutWhileStillRunning(t *testing.T) {
+	reg, g := testGroup()
+	g.Timeout = 1
+	f := newFakeStack()
+	f.lingering = 1 << 30
+	f.states["/src/api"] = registry.StateRunning
+
+	r := f.runner()
+	r.PollInterval = 100 * time.Millisecond
+	if _, err := r.Stop(context.Background(), reg, g); err == nil || !strings.Contains(err.Error(), "api not stopped") {
+		t.Errorf("Stop() error = %v, want a timeout", err)
+	}
+}
+
+func TestStartUnknownProject(t *testing.T) {
+	reg, g := testGroup()
+	g.Projects = append(g.Projects, "/src/gone")
+	if _, err := newFakeStack().runner().Start(context.Background(), reg, g); err == nil {
+		t.Error("Start() should fail for a project missing from the registry")
+	}
+}
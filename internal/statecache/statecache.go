@@ -0,0 +1,304 @@
+// Package statecache keeps the states of registered projects up to date in
+// the background. Each project is probed at an interval that depends on
+// its state, so running projects stay current while idle and missing ones
+// cost next to nothing, and only changes are delivered.
+package statecache
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// DefaultWorkers is how many projects are probed at once.
+const DefaultWorkers = 8
+
+// Intervals is how often projects are probed, by state.
+type Intervals struct {
+	Active  time.Duration // Running, degraded and starting projects
+	Stale   time.Duration
+	Idle    time.Duration
+	Missing time.Duration
+}
+
+// IntervalsFor derives probe intervals from the polling intervals: active
+// projects at the focused rate, idle and stale ones at the background rate,
+// and missing ones three times slower than that.
+func IntervalsFor(focused, background time.Duration) Intervals {
+	return Intervals{
+		Active:  focused,
+		Stale:   background,
+		Idle:    background,
+		Missing: 3 * background,
+	}
+}
+
+// minInterval keeps zero intervals from probing in a busy loop.
+const minInterval = 100 * time.Millisecond
+
+func (iv Intervals) of(state registry.ProjectState) time.Duration {
+	return max(iv.pick(state), minInterval)
+}
+
+func (iv Intervals) pick(state registry.ProjectState) time.Duration {
+	switch {
+	case state.IsActive():
+		return iv.Active
+	case state == registry.StateStale:
+		return iv.Stale
+	case state == registry.StateMissing:
+		return iv.Missing
+	default:
+		return iv.Idle
+	}
+}
+
+// Result is what a probe found out about a project.
+type Result struct {
+	State registry.ProjectState
+	// Services are the project's processes when all of them are running,
+	// for holding it in StateStarting until they pass readiness probes.
+	Services []string
+}
+
+// Equal reports whether two results are the same.
+func (r Result) Equal(o Result) bool {
+	return r.State == o.State && slices.Equal(r.Services, o.Services)
+}
+
+// Diff maps the paths of projects whose state changed to their new result.
+type Diff map[string]Result
+
+// Probe checks a project's state. It's the default probe of a Cache.
+func Probe(p *registry.Project) Result {
+	var r Result
+	// ready is called for every process once they're all running
+	r.State = p.DetectStateWithReadiness(func(service string) bool {
+		r.Services = append(r.Services, service)
+		return true
+	})
+	slices.Sort(r.Services)
+	return r
+}
+
+// entry is the cache's view of one project.
+type entry struct {
+	project  registry.Project // A copy, so probes never race with edits
+	result   Result
+	probed   bool // result holds a probe's result
+	due      time.Time
+	inFlight bool
+	again    bool // Probe again once the probe in flight is done
+}
+
+// probed is a finished probe.
+type probed struct {
+	path    string
+	project registry.Project
+	result  Result
+}
+
+// Cache probes projects off the caller's goroutine and delivers changes to
+// their states. Create it with New, set the projects with SetProjects and
+// call Run. Its methods are safe for concurrent use.
+type Cache struct {
+	probe   func(p *registry.Project) Result
+	workers int
+
+	mu        sync.Mutex
+	intervals Intervals
+	entries   map[string]*entry
+	wake      chan struct{}
+	changes   chan Diff
+}
+
+// New returns a cache probing at intervals with probe, or Probe if nil.
+func New(intervals Intervals, probe func(p *registry.Project) Result) *Cache {
+	if probe == nil {
+		probe = Probe
+	}
+	return &Cache{
+		probe:     probe,
+		workers:   DefaultWorkers,
+		intervals: intervals,
+		entries:   make(map[string]*entry),
+		wake:      make(chan struct{}, 1),
+		changes:   make(chan Diff),
+	}
+}
+
+// Changes delivers the states that changed since the last receive, keyed
+// by project path. Changes that pile up while nobody receives are merged,
+// so a slow receiver only sees the latest state of each project.
+func (c *Cache) Changes() <-chan Diff {
+	return c.changes
+}
+
+// SetIntervals changes the probe intervals, such as after a config reload.
+// They apply from each project's next probe.
+func (c *Cache) SetIntervals(intervals Intervals) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.intervals = intervals
+}
+
+// SetProjects sets the projects to probe. New projects are probed right
+// away, as are projects whose directory changed, such as after a move.
+func (c *Cache) SetProjects(projects []*registry.Project) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool, len(projects))
+	added := false
+	for _, p := range projects {
+		seen[p.Path] = true
+		e, ok := c.entries[p.Path]
+		if !ok {
+			c.entries[p.Path] = &entry{project: *p}
+			added = true
+			continue
+		}
+		if e.project.Dir() != p.Dir() {
+			e.refresh()
+			added = true
+		}
+		e.project = *p
+	}
+	for path := range c.entries {
+		if !seen[path] {
+			delete(c.entries, path)
+		}
+	}
+	if added {
+		c.poke()
+	}
+}
+
+// Refresh probes the project at path as soon as possible, such as after
+// starting or stopping it. Its result is delivered even if unchanged, for
+// callers that assumed a state in the meantime.
+func (c *Cache) Refresh(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[path]; ok {
+		e.refresh()
+		e.probed = false
+		c.poke()
+	}
+}
+
+// refresh makes the project due now, or right after the probe in flight,
+// which may have started before whatever prompted the refresh.
+func (e *entry) refresh() {
+	e.due = time.Time{}
+	if e.inFlight {
+		e.again = true
+	}
+}
+
+// poke wakes Run to look at the schedule again. c.mu must be held.
+func (c *Cache) poke() {
+	select {
+	case c.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run probes projects as they come due until ctx is done.
+func (c *Cache) Run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	results := make(chan probed)
+	sem := make(chan struct{}, c.workers)
+	pending := make(Diff)
+
+	for {
+		var out chan<- Diff
+		if len(pending) > 0 {
+			out = c.changes
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.wake:
+		case <-timer.C:
+		case out <- pending:
+			pending = make(Diff)
+			continue
+		case r := <-results:
+			c.finish(r, pending)
+		}
+
+		next := c.startDue(ctx, sem, results)
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(next)
+	}
+}
+
+// startDue starts probing the projects that are due and returns how long
+// until the next one is.
+func (c *Cache) startDue(ctx context.Context, sem chan struct{}, results chan<- probed) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	next := time.Hour
+	for path, e := range c.entries {
+		if e.inFlight {
+			continue
+		}
+		if wait := e.due.Sub(now); wait > 0 {
+			next = min(next, wait)
+			continue
+		}
+		e.inFlight = true
+		project := e.project
+		go func() {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			result := c.probe(&project)
+			<-sem
+			select {
+			case results <- probed{path: path, project: project, result: result}:
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return next
+}
+
+// finish records a probe's result, adding it to pending if it changed.
+func (c *Cache) finish(r probed, pending Diff) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[r.path]
+	if !ok {
+		return // Removed while probing
+	}
+	e.inFlight = false
+	if e.again {
+		e.again = false
+		e.due = time.Time{}
+	} else {
+		e.due = time.Now().Add(c.intervals.of(r.result.State))
+	}
+	if e.project.Dir() != r.project.Dir() {
+		return // Moved while probing
+	}
+	if !e.probed || !e.result.Equal(r.result) {
+		pending[r.path] = r.result
+	}
+	e.result, e.probed = r.result, true
+}
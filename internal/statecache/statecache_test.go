@@ -0,0 +1,177 @@
+package statecache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// fakeProbe returns settable states and counts probes per project.
+type fakeProbe struct {
+	mu     sync.Mutex
+	states map[string]registry.ProjectState
+	counts map[string]int
+}
+
+func newFakeProbe() *fakeProbe {
+	return &fakeProbe{states: make(map[string]registry.ProjectState), counts: make(map[string]int)}
+}
+
+func (f *fakeProbe) probe(p *registry.Project) Result {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.counts[p.Path]++
+	return Result{State: f.states[p.Path]}
+}
+
+func (f *fakeProbe) set(path string, state registry.ProjectState) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.states[path] = state
+}
+
+func (f *fakeProbe) count(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.counts[path]
+}
+
+func start(t *testing.T, c *Cache) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go c.Run(ctx)
+}
+
+// receive waits for changes until want is satisfied.
+func receive(t *testing.T, c *Cache, want Diff) {
+	t.Helper()
+	got := make(Diff)
+	deadline := time.After(2 * time.Second)
+	for {
+		done := true
+		for path, r := range want {
+			if g, ok := got[path]; !ok || !g.Equal(r) {
+				done = false
+			}
+		}
+		if done {
+			return
+		}
+		select {
+		case d := <-c.Changes():
+			for path, r := range d {
+				got[path] = r
+			}
+		case <-deadline:
+			t.Fatalf("changes = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestCacheDeliversChanges(t *testing.T) {
+	f := newFakeProbe()
+	f.set("/a", registry.StateRunning)
+	c := New(Intervals{Active: 20 * time.Millisecond, Idle: time.Hour, Stale: time.Hour, Missing: time.Hour}, f.probe)
+	c.SetProjects([]*registry.Project{{Path: "/a"}, {Path: "/b"}})
+	start(t, c)
+
+	// Every project's first result is a change
+	receive(t, c, Diff{"/a": {State: registry.StateRunning}, "/b": {State: registry.StateIdle}})
+
+	f.set("/a", registry.StateDegraded)
+	receive(t, c, Diff{"/a": {State: registry.StateDegraded}})
+
+	// Unchanged states aren't delivered again
+	select {
+	case d := <-c.Changes():
+		t.Errorf("unexpected changes %v", d)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestCacheBacksOffByState(t *testing.T) {
+	f := newFakeProbe()
+	f.set("/running", registry.StateRunning)
+	f.set("/missing", registry.StateMissing)
+	c := New(Intervals{Active: 20 * time.Millisecond, Idle: time.Hour, Stale: time.Hour, Missing: time.Hour}, f.probe)
+	c.SetProjects([]*registry.Project{{Path: "/running"}, {Path: "/missing"}})
+	start(t, c)
+	go func() {
+		for range c.Changes() {
+		}
+	}()
+
+	time.Sleep(450 * time.Millisecond)
+	if n := f.count("/running"); n < 3 {
+		t.Errorf("running project probed %d times, want it probed often", n)
+	}
+	if n := f.count("/missing"); n != 1 {
+		t.Errorf("missing project probed %d times, want once", n)
+	}
+}
+
+func TestCacheRefreshAndMoves(t *testing.T) {
+	f := newFakeProbe()
+	c := New(Intervals{Active: time.Hour, Idle: time.Hour, Stale: time.Hour, Missing: time.Hour}, f.probe)
+	p := &registry.Project{Path: "/a"}
+	c.SetProjects([]*registry.Project{p})
+	start(t, c)
+	receive(t, c, Diff{"/a": {State: registry.StateIdle}})
+
+	f.set("/a", registry.StateRunning)
+	c.Refresh("/a")
+	receive(t, c, Diff{"/a": {State: registry.StateRunning}})
+
+	// A refresh is delivered even if the state didn't change
+	c.Refresh("/a")
+	receive(t, c, Diff{"/a": {State: registry.StateRunning}})
+
+	// A project whose directory changed is probed again right away
+	moved := *p
+	moved.Kind, moved.Root = registry.KindImported, "/mono"
+	f.set("/a", registry.StateIdle)
+	c.SetProjects([]*registry.Project{&moved})
+	receive(t, c, Diff{"/a": {State: registry.StateIdle}})
+
+	// Removed projects are dropped
+	c.SetProjects(nil)
+	c.Refresh("/a")
+	time.Sleep(50 * time.Millisecond)
+	if n := f.count("/a"); n != 4 {
+		t.Errorf("probed %d times, want 4", n)
+	}
+}
+
+func TestIntervalsFor(t *testing.T) {
+	iv := IntervalsFor(2*time.Second, 10*time.Second)
+	tests := []struct {
+		state registry.ProjectState
+		want  time.Duration
+	}{
+		{registry.StateRunning, 2 * time.Second},
+		{registry.StateStarting, 2 * time.Second},
+		{registry.StateIdle, 10 * time.Second},
+		{registry.StateStale, 10 * time.Second},
+		{registry.StateMissing, 30 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := iv.of(tt.state); got != tt.want {
+			t.Errorf("interval of %v = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+	if got := (Intervals{}).of(registry.StateIdle); got != minInterval {
+		t.Errorf("zero interval = %v, want %v", got, minInterval)
+	}
+}
+
+func TestProbeListsServicesOfRunningProjects(t *testing.T) {
+	// Without a socket the project is idle and has no services
+	r := Probe(&registry.Project{Path: t.TempDir()})
+	if r.State != registry.StateIdle || r.Services != nil {
+		t.Errorf("Probe() = %+v, want idle without services", r)
+	}
+}
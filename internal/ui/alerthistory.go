@@ -1,11 +1,37 @@
 package ui
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/infktd/devdash/internal/fsutil"
+)
+
+const (
+	alertsDir  = "devdash"
+	alertsFile = "alerts.jsonl"
 )
 
+// AlertHistoryPath returns the default alert history file path.
+func AlertHistoryPath() string {
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			stateHome = filepath.Join(".local", "state")
+		} else {
+			stateHome = filepath.Join(home, ".local", "state")
+		}
+	}
+	return filepath.Join(stateHome, alertsDir, alertsFile)
+}
+
 // AlertType represents the type of alert.
 type AlertType int
 
@@ -16,6 +42,13 @@ const (
 	AlertProjectStopped
 	AlertCritical
 	AlertInfo
+	AlertServiceRestarted
+	AlertServiceFlapping
+	AlertServiceReady
+	AlertServiceNotReady
+
+	// maxAlertType is the last defined alert type
+	maxAlertType = AlertServiceNotReady
 )
 
 func (t AlertType) String() string {
@@ -32,25 +65,58 @@ func (t AlertType) String() string {
 		return "critical"
 	case AlertInfo:
 		return "info"
+	case AlertServiceRestarted:
+		return "restart"
+	case AlertServiceFlapping:
+		return "flapping"
+	case AlertServiceReady:
+		return "ready"
+	case AlertServiceNotReady:
+		return "not-ready"
 	default:
 		return "unknown"
 	}
 }
 
+// MarshalText encodes the alert type by name.
+func (t AlertType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText decodes an alert type name.
+func (t *AlertType) UnmarshalText(text []byte) error {
+	for candidate := AlertServiceCrashed; candidate <= maxAlertType; candidate++ {
+		if candidate.String() == string(text) {
+			*t = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown alert type %q", text)
+}
+
 // Alert represents a stored alert.
 type Alert struct {
-	Type      AlertType
-	Project   string
-	Service   string // May be empty for project-level alerts
-	Message   string
-	Timestamp time.Time
+	Type      AlertType `json:"type"`
+	Project   string    `json:"project"`
+	Service   string    `json:"service,omitempty"` // May be empty for project-level alerts
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
-// AlertHistory stores past alerts.
+// AlertHistory stores past alerts, optionally persisted to a JSONL file.
+// Added alerts are kept in memory and written by Flush, so callers on the
+// UI goroutine never wait on the disk.
 type AlertHistory struct {
 	alerts   []Alert
 	capacity int
 	mu       sync.RWMutex
+
+	// Persistence (disabled when path is empty)
+	path      string
+	maxBytes  int64
+	retention time.Duration
+	unsaved   []Alert    // Added since the last flush
+	fileMu    sync.Mutex // Serializes flushes
 }
 
 // NewAlertHistory creates a new alert history with given capacity.
@@ -61,11 +127,79 @@ func NewAlertHistory(capacity int) *AlertHistory {
 	}
 }
 
-// Add appends an alert, removing oldest if at capacity.
+// LoadAlertHistory creates an alert history backed by the JSONL file at path.
+// Alerts older than retention (if positive) are dropped on load and when the
+// file is rotated to path.1 once it grows past maxBytes. The returned history
+// is usable even when loading fails.
+func LoadAlertHistory(path string, capacity int, retention time.Duration, maxBytes int64) (*AlertHistory, error) {
+	h := NewAlertHistory(capacity)
+	h.path = path
+	h.maxBytes = maxBytes
+	h.retention = retention
+
+	cutoff := h.cutoff()
+
+	// Read the rotated file first so alerts stay in chronological order
+	var loadErr error
+	for _, file := range []string{path + ".1", path} {
+		alerts, err := readAlerts(file)
+		if err != nil && !os.IsNotExist(err) {
+			loadErr = err
+			continue
+		}
+		for _, alert := range alerts {
+			if alert.Timestamp.Before(cutoff) {
+				continue
+			}
+			h.push(alert)
+		}
+	}
+	return h, loadErr
+}
+
+// cutoff returns the time before which alerts are dropped, or the zero
+// time when they are kept forever.
+func (h *AlertHistory) cutoff() time.Time {
+	if h.retention <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-h.retention)
+}
+
+// readAlerts reads alerts from a JSONL file, skipping malformed lines.
+func readAlerts(path string) ([]Alert, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var alerts []Alert
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var alert Alert
+		if err := json.Unmarshal(scanner.Bytes(), &alert); err != nil {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, scanner.Err()
+}
+
+// Add appends an alert, removing oldest if at capacity. Persisted
+// histories write it on the next Flush.
 func (h *AlertHistory) Add(alert Alert) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	h.push(alert)
+	if h.path != "" {
+		h.unsaved = append(h.unsaved, alert)
+	}
+}
+
+// push appends an alert in memory. Caller must hold the lock.
+func (h *AlertHistory) push(alert Alert) {
 	if len(h.alerts) >= h.capacity {
 		// Remove oldest (first element)
 		h.alerts = h.alerts[1:]
@@ -73,6 +207,103 @@ func (h *AlertHistory) Add(alert Alert) {
 	h.alerts = append(h.alerts, alert)
 }
 
+// Flush appends the alerts added since the last flush to the history
+// file, rotating it when full. Alerts that fail to write are retried on
+// the next flush.
+func (h *AlertHistory) Flush() error {
+	h.fileMu.Lock()
+	defer h.fileMu.Unlock()
+
+	h.mu.Lock()
+	alerts := h.unsaved
+	h.unsaved = nil
+	h.mu.Unlock()
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	if err := h.persist(alerts); err != nil {
+		h.mu.Lock()
+		h.unsaved = append(alerts, h.unsaved...)
+		h.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// persist appends alerts to the history file. Caller must hold fileMu.
+func (h *AlertHistory) persist(alerts []Alert) error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return err
+	}
+	if err := h.rotate(); err != nil {
+		return err
+	}
+
+	data, err := encodeAlerts(alerts)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(data)
+	return err
+}
+
+// rotate moves a full history file to path.1, dropping alerts past the
+// retention. Caller must hold fileMu.
+func (h *AlertHistory) rotate() error {
+	if h.maxBytes <= 0 {
+		return nil
+	}
+	info, err := os.Stat(h.path)
+	if err != nil || info.Size() < h.maxBytes {
+		return nil
+	}
+
+	cutoff := h.cutoff()
+	if cutoff.IsZero() {
+		return os.Rename(h.path, h.path+".1")
+	}
+
+	alerts, err := readAlerts(h.path)
+	if err != nil {
+		return err
+	}
+	kept := alerts[:0]
+	for _, alert := range alerts {
+		if !alert.Timestamp.Before(cutoff) {
+			kept = append(kept, alert)
+		}
+	}
+	data, err := encodeAlerts(kept)
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(h.path+".1", data, 0644); err != nil {
+		return err
+	}
+	return os.Remove(h.path)
+}
+
+// encodeAlerts encodes alerts as JSONL.
+func encodeAlerts(alerts []Alert) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, alert := range alerts {
+		data, err := json.Marshal(alert)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(append(data, '\n'))
+	}
+	return buf.Bytes(), nil
+}
+
 // AddServiceCrashed is a convenience method.
 func (h *AlertHistory) AddServiceCrashed(project, service string, exitCode int) {
 	h.Add(Alert{
@@ -132,9 +363,16 @@ func (h *AlertHistory) Len() int {
 	return len(h.alerts)
 }
 
-// Clear removes all alerts.
+// Clear removes all alerts, including persisted ones.
 func (h *AlertHistory) Clear() {
+	h.fileMu.Lock()
+	defer h.fileMu.Unlock()
 	h.mu.Lock()
 	defer h.mu.Unlock()
 	h.alerts = make([]Alert, 0, h.capacity)
+	h.unsaved = nil
+	if h.path != "" {
+		os.Remove(h.path)
+		os.Remove(h.path + ".1")
+	}
 }
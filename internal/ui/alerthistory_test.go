@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -125,3 +127,120 @@ func TestAlertHistoryConvenienceMethods(t *testing.T) {
 		t.Error("second should be recovered")
 	}
 }
+
+func TestAlertHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+
+	h, err := LoadAlertHistory(path, 10, 0, 0)
+	if err != nil {
+		t.Fatalf("LoadAlertHistory() error: %v", err)
+	}
+	h.Add(Alert{Type: AlertServiceCrashed, Project: "api", Service: "postgres", Message: "exited with code 1", Timestamp: time.Now()})
+	h.Add(Alert{Type: AlertProjectStarted, Project: "api", Message: "started", Timestamp: time.Now()})
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+
+	reloaded, err := LoadAlertHistory(path, 10, 0, 0)
+	if err != nil {
+		t.Fatalf("LoadAlertHistory() error: %v", err)
+	}
+	all := reloaded.All()
+	if len(all) != 2 {
+		t.Fatalf("expected 2 persisted alerts, got %d", len(all))
+	}
+	if all[0].Type != AlertServiceCrashed || all[0].Service != "postgres" {
+		t.Errorf("unexpected first alert: %+v", all[0])
+	}
+	if all[1].Type != AlertProjectStarted {
+		t.Errorf("unexpected second alert: %+v", all[1])
+	}
+}
+
+func TestAlertHistoryRetention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	h, _ := LoadAlertHistory(path, 10, 0, 0)
+	h.Add(Alert{Type: AlertInfo, Message: "old", Timestamp: time.Now().Add(-48 * time.Hour)})
+	h.Add(Alert{Type: AlertInfo, Message: "new", Timestamp: time.Now()})
+	h.Flush()
+
+	reloaded, _ := LoadAlertHistory(path, 10, 24*time.Hour, 0)
+	all := reloaded.All()
+	if len(all) != 1 || all[0].Message != "new" {
+		t.Errorf("expected only the recent alert, got %+v", all)
+	}
+}
+
+func TestAlertHistoryRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	h, _ := LoadAlertHistory(path, 100, 0, 200)
+	for i := 0; i < 10; i++ {
+		h.Add(Alert{Type: AlertInfo, Message: fmt.Sprintf("msg %d", i), Timestamp: time.Now()})
+		h.Flush()
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("expected rotated file: %v", err)
+	}
+	info, _ := os.Stat(path)
+	if info.Size() > 400 {
+		t.Errorf("active file should stay near the size limit, got %d bytes", info.Size())
+	}
+
+	// The newest alerts are still loaded in order after rotation
+	reloaded, _ := LoadAlertHistory(path, 100, 0, 200)
+	all := reloaded.All()
+	if len(all) == 0 || all[len(all)-1].Message != "msg 9" {
+		t.Errorf("expected newest alert last, got %+v", all)
+	}
+}
+
+func TestAlertHistoryRotationDropsExpiredAlerts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	h, _ := LoadAlertHistory(path, 100, 24*time.Hour, 200)
+	h.Add(Alert{Type: AlertInfo, Message: "old", Timestamp: time.Now().Add(-48 * time.Hour)})
+	for i := 0; i < 3; i++ {
+		h.Add(Alert{Type: AlertInfo, Message: fmt.Sprintf("msg %d", i), Timestamp: time.Now()})
+	}
+	h.Flush()
+
+	// The next write rotates the full file
+	h.Add(Alert{Type: AlertInfo, Message: "next", Timestamp: time.Now()})
+	if err := h.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+
+	rotated, err := readAlerts(path + ".1")
+	if err != nil {
+		t.Fatalf("expected rotated file: %v", err)
+	}
+	if len(rotated) != 3 || rotated[0].Message != "msg 0" {
+		t.Errorf("rotation should drop alerts past the retention, got %+v", rotated)
+	}
+}
+
+func TestAlertHistoryClearRemovesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	h, _ := LoadAlertHistory(path, 10, 0, 0)
+	h.Add(Alert{Type: AlertInfo, Timestamp: time.Now()})
+	h.Flush()
+	h.Clear()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("Clear() should remove the history file")
+	}
+}
+
+func TestAlertTypeText(t *testing.T) {
+	for typ := AlertServiceCrashed; typ <= maxAlertType; typ++ {
+		text, _ := typ.MarshalText()
+		var got AlertType
+		if err := got.UnmarshalText(text); err != nil || got != typ {
+			t.Errorf("round trip of %v failed: got %v, err %v", typ, got, err)
+		}
+	}
+	var bad AlertType
+	if err := bad.UnmarshalText([]byte("bogus")); err == nil {
+		t.Error("UnmarshalText should reject unknown types")
+	}
+}
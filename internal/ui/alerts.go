@@ -2,6 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,6 +17,12 @@ type AlertsPanel struct {
 	visible bool
 	width   int
 	height  int
+
+	// Filters (empty / unset shows everything)
+	projectFilter string
+	serviceFilter string
+	typeFilter    AlertType
+	filterByType  bool
 }
 
 // NewAlertsPanel creates an alerts panel.
@@ -59,15 +68,128 @@ func (a *AlertsPanel) Update(msg tea.Msg) (*AlertsPanel, tea.Cmd) {
 		return a, nil
 	}
 
-	// Close on Esc or H
 	switch keyMsg.String() {
 	case "esc", "H":
+		// Close on Esc or H
 		a.visible = false
+	case "p":
+		a.projectFilter = nextValue(a.distinct(func(al Alert) string { return al.Project }, false), a.projectFilter)
+		a.serviceFilter = ""
+	case "s":
+		a.serviceFilter = nextValue(a.distinct(func(al Alert) string { return al.Service }, true), a.serviceFilter)
+	case "t":
+		a.cycleTypeFilter()
+	case "c":
+		a.ClearFilters()
 	}
 
 	return a, nil
 }
 
+// ClearFilters removes all project, service and type filters.
+func (a *AlertsPanel) ClearFilters() {
+	a.projectFilter = ""
+	a.serviceFilter = ""
+	a.filterByType = false
+}
+
+// distinct returns the sorted non-empty values of field across stored alerts,
+// optionally limited to the current project filter.
+func (a *AlertsPanel) distinct(field func(Alert) string, withinProject bool) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, alert := range a.alerts.All() {
+		if withinProject && a.projectFilter != "" && alert.Project != a.projectFilter {
+			continue
+		}
+		v := field(alert)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// nextValue cycles through values, with "" (no filter) before the first one.
+func nextValue(values []string, current string) string {
+	if current == "" {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	for i, v := range values {
+		if v == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return ""
+}
+
+// cycleTypeFilter steps through the alert types present in the history.
+func (a *AlertsPanel) cycleTypeFilter() {
+	present := make(map[AlertType]bool)
+	for _, alert := range a.alerts.All() {
+		present[alert.Type] = true
+	}
+
+	start := AlertServiceCrashed
+	if a.filterByType {
+		start = a.typeFilter + 1
+	}
+	for t := start; t <= maxAlertType; t++ {
+		if present[t] {
+			a.typeFilter = t
+			a.filterByType = true
+			return
+		}
+	}
+	a.filterByType = false
+}
+
+// matches reports whether an alert passes the active filters.
+func (a *AlertsPanel) matches(alert Alert) bool {
+	if a.projectFilter != "" && alert.Project != a.projectFilter {
+		return false
+	}
+	if a.serviceFilter != "" && alert.Service != a.serviceFilter {
+		return false
+	}
+	if a.filterByType && alert.Type != a.typeFilter {
+		return false
+	}
+	return true
+}
+
+// filtered returns up to n alerts matching the filters (newest first).
+func (a *AlertsPanel) filtered(n int) []Alert {
+	all := a.alerts.All()
+	result := make([]Alert, 0, n)
+	for i := len(all) - 1; i >= 0 && len(result) < n; i-- {
+		if a.matches(all[i]) {
+			result = append(result, all[i])
+		}
+	}
+	return result
+}
+
+// filterSummary describes the active filters, or "" if none are set.
+func (a *AlertsPanel) filterSummary() string {
+	var parts []string
+	if a.projectFilter != "" {
+		parts = append(parts, "project="+a.projectFilter)
+	}
+	if a.serviceFilter != "" {
+		parts = append(parts, "service="+a.serviceFilter)
+	}
+	if a.filterByType {
+		parts = append(parts, "type="+a.typeFilter.String())
+	}
+	return strings.Join(parts, " ")
+}
+
 // View renders the alerts panel.
 func (a *AlertsPanel) View() string {
 	if !a.visible {
@@ -82,16 +204,32 @@ func (a *AlertsPanel) View() string {
 		Align(lipgloss.Center).
 		Bold(true).
 		Foreground(a.styles.theme.Primary)
-	content += titleStyle.Render("ALERTS") + "\n\n"
+	content += titleStyle.Render("ALERTS") + "\n"
+
+	// Active filters
+	filterStyle := lipgloss.NewStyle().
+		Width(76).
+		Align(lipgloss.Center).
+		Foreground(a.styles.theme.Muted)
+	summary := a.filterSummary()
+	if summary != "" {
+		content += filterStyle.Render("Filter: "+summary) + "\n"
+	} else {
+		content += "\n"
+	}
 
 	// Get recent alerts (limit to fit modal height)
-	alerts := a.alerts.Recent(18) // Reduced from 20 to fit better
+	alerts := a.filtered(18) // Reduced from 20 to fit better
 	if len(alerts) == 0 {
 		emptyStyle := lipgloss.NewStyle().
 			Width(76).
 			Align(lipgloss.Center).
 			Foreground(a.styles.theme.Muted)
-		content += emptyStyle.Render("No alerts yet") + "\n"
+		emptyText := "No alerts yet"
+		if summary != "" {
+			emptyText = "No alerts match the filter"
+		}
+		content += emptyStyle.Render(emptyText) + "\n"
 	} else {
 		// Render alerts (newest first)
 		for _, alert := range alerts {
@@ -102,7 +240,7 @@ func (a *AlertsPanel) View() string {
 	content += "\n"
 
 	// Footer
-	footerText := "[Esc] or [H] to close"
+	footerText := "[p] project  [s] service  [t] type  [c] clear  [Esc] close"
 	footerStyle := lipgloss.NewStyle().
 		Width(76).
 		Align(lipgloss.Center)
@@ -119,8 +257,11 @@ func (a *AlertsPanel) View() string {
 
 // renderAlert renders a single alert line.
 func (a *AlertsPanel) renderAlert(alert Alert) string {
-	// Timestamp
+	// Timestamp (with date for alerts from previous days)
 	timestamp := alert.Timestamp.Format("15:04:05")
+	if !sameDay(alert.Timestamp, time.Now()) {
+		timestamp = alert.Timestamp.Format("01-02 15:04")
+	}
 	timeStyle := lipgloss.NewStyle().Foreground(a.styles.theme.Muted)
 
 	// Alert type badge
@@ -158,6 +299,26 @@ func (a *AlertsPanel) renderAlert(alert Alert) string {
 			Foreground(a.styles.theme.Primary).
 			Bold(true)
 		badge = "[INFO]"
+	case AlertServiceRestarted:
+		badgeStyle = lipgloss.NewStyle().
+			Foreground(a.styles.theme.Warning).
+			Bold(true)
+		badge = "[RESTART]"
+	case AlertServiceFlapping:
+		badgeStyle = lipgloss.NewStyle().
+			Foreground(a.styles.theme.Warning).
+			Bold(true)
+		badge = "[FLAP]"
+	case AlertServiceReady:
+		badgeStyle = lipgloss.NewStyle().
+			Foreground(a.styles.theme.Success).
+			Bold(true)
+		badge = "[READY]"
+	case AlertServiceNotReady:
+		badgeStyle = lipgloss.NewStyle().
+			Foreground(a.styles.theme.Warning).
+			Bold(true)
+		badge = "[NOT READY]"
 	default:
 		badgeStyle = lipgloss.NewStyle().
 			Foreground(a.styles.theme.Muted).
@@ -167,12 +328,18 @@ func (a *AlertsPanel) renderAlert(alert Alert) string {
 
 	// Message (truncate if too long)
 	message := alert.Message
-	if alert.Service != "" {
-		message = fmt.Sprintf("%s: %s", alert.Service, message)
+	source := alert.Service
+	if alert.Project != "" && source != "" {
+		source = alert.Project + "/" + source
+	} else if alert.Project != "" {
+		source = alert.Project
+	}
+	if source != "" {
+		message = fmt.Sprintf("%s: %s", source, message)
 	}
 
-	// Calculate max message length: 76 - timestamp(8) - badge(~10) - spacing(4) = ~54
-	maxLen := 54
+	// Fit the line width: the timestamp is 8 or 11 wide, the badge varies
+	maxLen := 76 - len(timestamp) - len(badge) - 2
 	if len(message) > maxLen {
 		message = message[:maxLen-3] + "..."
 	}
@@ -185,3 +352,22 @@ func (a *AlertsPanel) renderAlert(alert Alert) string {
 	lineStyle := lipgloss.NewStyle().Width(76)
 	return lineStyle.Render(line)
 }
+
+// sameDay reports whether two times fall on the same calendar day.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// addAlert records an alert in the history and returns a command that
+// writes it to the history file off the UI goroutine.
+func (m *Model) addAlert(alert Alert) tea.Cmd {
+	m.alerts.Add(alert)
+	history := m.alerts
+	return func() tea.Msg {
+		// Best effort: unwritten alerts are retried on the next flush
+		_ = history.Flush()
+		return nil
+	}
+}
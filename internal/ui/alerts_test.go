@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -87,3 +89,82 @@ func TestAlertsPanelView(t *testing.T) {
 		t.Error("View() should return content when visible")
 	}
 }
+
+func TestAlertsPanelFilters(t *testing.T) {
+	styles := NewStyles(GetTheme("matrix"))
+	alerts := NewAlertHistory(100)
+	alerts.Add(Alert{Type: AlertServiceCrashed, Project: "api", Service: "postgres"})
+	alerts.Add(Alert{Type: AlertServiceRecovered, Project: "api", Service: "postgres"})
+	alerts.Add(Alert{Type: AlertServiceCrashed, Project: "web", Service: "redis"})
+	panel := NewAlertsPanel(styles, alerts, 100, 50)
+	panel.Show()
+
+	press := func(r rune) {
+		panel.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	press('p')
+	if panel.projectFilter != "api" {
+		t.Fatalf("projectFilter = %q, want api", panel.projectFilter)
+	}
+	if got := len(panel.filtered(18)); got != 2 {
+		t.Errorf("expected 2 alerts for api, got %d", got)
+	}
+
+	press('t')
+	got := panel.filtered(18)
+	if len(got) != 1 || got[0].Type != AlertServiceCrashed {
+		t.Errorf("expected only api crash, got %+v", got)
+	}
+
+	press('s')
+	if panel.serviceFilter != "postgres" {
+		t.Errorf("serviceFilter = %q, want postgres (only service in api)", panel.serviceFilter)
+	}
+	press('s')
+	if panel.serviceFilter != "" {
+		t.Errorf("service filter should cycle back to all, got %q", panel.serviceFilter)
+	}
+
+	press('c')
+	if panel.filterSummary() != "" || len(panel.filtered(18)) != 3 {
+		t.Error("clear should remove all filters")
+	}
+}
+
+func TestAlertsPanelTypeFilterCycles(t *testing.T) {
+	styles := NewStyles(GetTheme("matrix"))
+	alerts := NewAlertHistory(100)
+	alerts.Add(Alert{Type: AlertServiceCrashed})
+	alerts.Add(Alert{Type: AlertInfo})
+	panel := NewAlertsPanel(styles, alerts, 100, 50)
+
+	panel.cycleTypeFilter()
+	if !panel.filterByType || panel.typeFilter != AlertServiceCrashed {
+		t.Fatalf("expected crashed filter, got %v", panel.typeFilter)
+	}
+	panel.cycleTypeFilter()
+	if panel.typeFilter != AlertInfo {
+		t.Errorf("expected info filter (skipping absent types), got %v", panel.typeFilter)
+	}
+	panel.cycleTypeFilter()
+	if panel.filterByType {
+		t.Error("type filter should cycle back to all")
+	}
+}
+
+func TestRenderAlertFitsWithDate(t *testing.T) {
+	styles := NewStyles(GetTheme("matrix"))
+	panel := NewAlertsPanel(styles, NewAlertHistory(100), 100, 50)
+
+	line := panel.renderAlert(Alert{
+		Type:      AlertServiceNotReady,
+		Project:   "api",
+		Service:   "postgres",
+		Message:   strings.Repeat("x", 100),
+		Timestamp: time.Now().Add(-48 * time.Hour),
+	})
+	if strings.Contains(line, "\n") {
+		t.Errorf("alert from a previous day should fit on one line:\n%s", line)
+	}
+}
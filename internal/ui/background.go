@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// backgroundPollMsg triggers a poll of all running projects except the current one.
+type backgroundPollMsg struct{}
+
+// backgroundStatus is the polled service status of one background project.
+type backgroundStatus struct {
+	path     string // Project path, names are not unique
+	services []compose.ProcessStatus
+	err      error
+}
+
+// backgroundStatusMsg delivers the results of a background poll.
+type backgroundStatusMsg struct {
+	results []backgroundStatus
+}
+
+// backgroundPollCmd schedules the next background poll.
+func (m *Model) backgroundPollCmd() tea.Cmd {
+	interval := time.Duration(m.config.Polling.BackgroundProject) * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg {
+		return backgroundPollMsg{}
+	})
+}
+
+// backgroundTargets returns the running or degraded projects other than the
+// currently selected one, based on the cached project states.
+func (m *Model) backgroundTargets() []*registry.Project {
+	current := m.currentProject()
+	var targets []*registry.Project
+	for _, p := range m.registry.Projects {
+		if current != nil && p.Path == current.Path {
+			continue
+		}
+		state := m.projectStates[p.Path]
+		if state.IsActive() {
+			targets = append(targets, p)
+		}
+	}
+	return targets
+}
+
+// fetchBackgroundCmd queries every target project concurrently off the UI goroutine.
+func fetchBackgroundCmd(targets []*registry.Project) tea.Cmd {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	type target struct {
+		path       string
+		socketPath string
+	}
+	list := make([]target, len(targets))
+	for i, p := range targets {
+		list[i] = target{path: p.Path, socketPath: p.SocketPath()}
+	}
+
+	return func() tea.Msg {
+		results := make([]backgroundStatus, len(list))
+		var wg sync.WaitGroup
+		for i, t := range list {
+			wg.Add(1)
+			go func(i int, t target) {
+				defer wg.Done()
+				results[i].path = t.path
+				client := compose.NewClient(t.socketPath)
+				if err := client.Connect(); err != nil {
+					results[i].err = err
+					return
+				}
+				status, err := client.GetStatus()
+				if err != nil {
+					results[i].err = err
+					return
+				}
+				results[i].services = status.Processes
+			}(i, t)
+		}
+		wg.Wait()
+		return backgroundStatusMsg{results: results}
+	}
+}
+
+// handleBackgroundStatus feeds background results into the health monitor
+// and returns commands for any resulting health events and readiness probes.
+func (m *Model) handleBackgroundStatus(msg backgroundStatusMsg) []tea.Cmd {
+	currentPath := ""
+	if p := m.currentProject(); p != nil {
+		currentPath = p.Path
+	}
+
+	var cmds []tea.Cmd
+	for _, result := range msg.results {
+		// The foreground poll owns the current project (it may have been
+		// selected while this poll was in flight)
+		if result.err != nil || result.path == currentPath {
+			continue
+		}
+		// Removed while the poll was in flight
+		p := m.registry.FindByPath(result.path)
+		if p == nil {
+			continue
+		}
+		m.recordMetrics(p.Name, result.services)
+		for _, svc := range result.services {
+			event := m.health.UpdateService(p.Name, svc.Name, svc.IsRunning, svc.ExitCode)
+			if event != nil {
+				ev := *event
+				cmds = append(cmds, func() tea.Msg {
+					return healthEventMsg(ev)
+				})
+			}
+		}
+		if cmd := probeServicesCmd(m.config.Probes, p.Name, p.Path, result.services); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func newBackgroundTestModel(t *testing.T) (*Model, *registry.Project, *registry.Project) {
+	t.Helper()
+	reg := &registry.Registry{}
+	reg.AddProject(t.TempDir())
+	reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+	current := m.currentProject()
+	var other *registry.Project
+	for _, p := range reg.Projects {
+		if p.Path != current.Path {
+			other = p
+		}
+	}
+	return m, current, other
+}
+
+func TestBackgroundTargetsSkipsCurrentAndIdle(t *testing.T) {
+	m, current, other := newBackgroundTestModel(t)
+
+	m.projectStates[current.Path] = registry.StateRunning
+	m.projectStates[other.Path] = registry.StateIdle
+	if targets := m.backgroundTargets(); len(targets) != 0 {
+		t.Fatalf("expected no targets, got %d", len(targets))
+	}
+
+	m.projectStates[other.Path] = registry.StateDegraded
+	targets := m.backgroundTargets()
+	if len(targets) != 1 || targets[0].Path != other.Path {
+		t.Fatalf("expected only the background project, got %+v", targets)
+	}
+}
+
+func TestFetchBackgroundCmdNoTargets(t *testing.T) {
+	if fetchBackgroundCmd(nil) != nil {
+		t.Error("fetchBackgroundCmd should return nil without targets")
+	}
+}
+
+func TestHandleBackgroundStatusEmitsCrash(t *testing.T) {
+	m, _, other := newBackgroundTestModel(t)
+
+	m.handleBackgroundStatus(backgroundStatusMsg{results: []backgroundStatus{{
+		path:     other.Path,
+		services: []compose.ProcessStatus{{Name: "worker", IsRunning: true}},
+	}}})
+
+	cmds := m.handleBackgroundStatus(backgroundStatusMsg{results: []backgroundStatus{{
+		path:     other.Path,
+		services: []compose.ProcessStatus{{Name: "worker", IsRunning: false, ExitCode: 1}},
+	}}})
+	if len(cmds) != 1 {
+		t.Fatalf("expected 1 health event, got %d", len(cmds))
+	}
+	event := health.Event(cmds[0]().(healthEventMsg))
+	if event.Type != health.EventServiceCrashed || event.Project != other.Name {
+		t.Errorf("unexpected event: %+v", event)
+	}
+
+	m.notifier.SetEnabled(false)
+	var model tea.Model = m
+	model, _ = model.Update(healthEventMsg(event))
+	alerts := model.(*Model).alerts.All()
+	if len(alerts) == 0 || alerts[0].Project != other.Name {
+		t.Errorf("background crash should be recorded in alert history, got %+v", alerts)
+	}
+}
+
+func TestHandleBackgroundStatusIgnoresCurrentAndErrors(t *testing.T) {
+	m, current, other := newBackgroundTestModel(t)
+
+	cmds := m.handleBackgroundStatus(backgroundStatusMsg{results: []backgroundStatus{
+		{path: current.Path, services: []compose.ProcessStatus{{Name: "web", IsRunning: true}}},
+		{path: other.Path, err: errors.New("connection refused")},
+	}})
+	if len(cmds) != 0 {
+		t.Errorf("expected no events, got %d", len(cmds))
+	}
+	if _, ok := m.health.GetState(current.Name, "web"); ok {
+		t.Error("current project should be left to the foreground poll")
+	}
+}
+
+func TestHandleBackgroundStatusKeysProjectsByPath(t *testing.T) {
+	reg := &registry.Registry{}
+	reg.AddProject(filepath.Join(t.TempDir(), "app"))
+	reg.AddProject(filepath.Join(t.TempDir(), "app"))
+	m := New(config.Default(), reg)
+	current := m.currentProject()
+	other := reg.Projects[0]
+	if other.Path == current.Path {
+		other = reg.Projects[1]
+	}
+
+	m.handleBackgroundStatus(backgroundStatusMsg{results: []backgroundStatus{{
+		path:     other.Path,
+		services: []compose.ProcessStatus{{Name: "worker", IsRunning: true}},
+	}}})
+	if _, ok := m.health.GetState(other.Name, "worker"); !ok {
+		t.Error("a background project sharing the current project's name should still be monitored")
+	}
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"github.com/infktd/devdash/internal/metrics"
+)
+
+// brailleDots are the bits of a braille cell's dots, by row from the top
+// and then by column.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// brailleChart draws values as a line of braille dots, two values per
+// cell, in a chart width cells wide and height cells tall scaled from 0 at
+// the bottom to top. NaN values leave gaps in the line.
+func brailleChart(values []float64, top float64, width, height int) []string {
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+	cells := make([][]rune, height)
+	for i := range cells {
+		cells[i] = make([]rune, width)
+	}
+	rows := height * 4
+	// level is the dot row of v, counting up from the bottom
+	level := func(v float64) int {
+		if top <= 0 {
+			return 0
+		}
+		y := int(math.Round(v / top * float64(rows-1)))
+		return min(max(y, 0), rows-1)
+	}
+
+	prev := -1
+	for x, v := range values {
+		if x >= width*2 {
+			break
+		}
+		if math.IsNaN(v) {
+			prev = -1
+			continue
+		}
+		y := level(v)
+		// Connect to the previous value so steep changes stay a line
+		from, to := y, y
+		if prev >= 0 {
+			from, to = min(prev, y), max(prev, y)
+		}
+		for dy := from; dy <= to; dy++ {
+			row := rows - 1 - dy
+			cells[row/4][x/2] |= brailleDots[row%4][x%2]
+		}
+		prev = y
+	}
+
+	lines := make([]string, height)
+	for i, row := range cells {
+		runes := make([]rune, width)
+		for x, bits := range row {
+			runes[x] = 0x2800 + bits
+		}
+		lines[i] = string(runes)
+	}
+	return lines
+}
+
+// chartColumns spreads points over n columns by time, from start to
+// start+span, averaging the values of points that share a column. Columns
+// between points at most maxGap apart are interpolated; the rest of the
+// columns without points, such as while a service was stopped, are NaN.
+func chartColumns(points []metrics.Point, value func(metrics.Point) float64, start time.Time, span time.Duration, n int, maxGap time.Duration) []float64 {
+	column := func(t time.Time) int {
+		x := int(float64(t.Sub(start)) / float64(span) * float64(n))
+		return min(max(x, 0), n-1)
+	}
+	sums := make([]float64, n)
+	counts := make([]int, n)
+	for _, p := range points {
+		x := column(p.Time)
+		sums[x] += value(p)
+		counts[x]++
+	}
+	columns := make([]float64, n)
+	for x := range columns {
+		if counts[x] == 0 {
+			columns[x] = math.NaN()
+		} else {
+			columns[x] = sums[x] / float64(counts[x])
+		}
+	}
+
+	for i := 1; i < len(points); i++ {
+		if points[i].Time.Sub(points[i-1].Time) > maxGap {
+			continue
+		}
+		from, to := column(points[i-1].Time), column(points[i].Time)
+		for x := from + 1; x < to; x++ {
+			frac := float64(x-from) / float64(to-from)
+			columns[x] = columns[from] + (columns[to]-columns[from])*frac
+		}
+	}
+	return columns
+}
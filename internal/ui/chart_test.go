@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/metrics"
+)
+
+func TestBrailleChart(t *testing.T) {
+	// A flat line along the bottom: the bottom dots of both columns
+	if got := brailleChart([]float64{0, 0}, 1, 1, 1); !reflect.DeepEqual(got, []string{"⣀"}) {
+		t.Errorf("flat line = %q", got)
+	}
+	// A rise from the bottom to the top fills the second column
+	if got := brailleChart([]float64{0, 1}, 1, 1, 1); !reflect.DeepEqual(got, []string{"⣸"}) {
+		t.Errorf("rise = %q", got)
+	}
+	// Values above the top are clamped, gaps stay empty
+	got := brailleChart([]float64{5, math.NaN(), math.NaN(), 0}, 1, 2, 2)
+	want := []string{"⠁⠀", "⠀⢀"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("clamped with gap = %q, want %q", got, want)
+	}
+}
+
+func TestChartColumns(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	points := []metrics.Point{
+		{Time: start, CPU: 2},
+		{Time: start.Add(10 * time.Second), CPU: 4},
+		{Time: start.Add(50 * time.Second), CPU: 9},
+	}
+	cpu := func(p metrics.Point) float64 { return p.CPU }
+	got := chartColumns(points, cpu, start, time.Minute, 3, 20*time.Second)
+	if got[0] != 3 || !math.IsNaN(got[1]) || got[2] != 9 {
+		t.Errorf("chartColumns() = %v, want [3 NaN 9]", got)
+	}
+	// Points close enough together are joined
+	got = chartColumns(points, cpu, start, time.Minute, 3, time.Minute)
+	if got[1] != 6 {
+		t.Errorf("chartColumns() = %v, want the gap interpolated to 6", got)
+	}
+}
@@ -0,0 +1,329 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/infktd/devdash/internal/compose"
+)
+
+// depGraphMsg delivers the dependency graph of a project.
+type depGraphMsg struct {
+	project string
+	graph   *compose.DependencyGraph
+	err     error
+}
+
+// depOperationMsg reports the result of starting a service with its
+// dependencies or stopping it with its dependents.
+type depOperationMsg struct {
+	service   string
+	operation string   // "start" or "stop"
+	affected  []string // Services actually started or stopped
+	err       error
+}
+
+// graphLine is one row of the dependency tree.
+type graphLine struct {
+	prefix string // Tree connectors drawn before the node
+	name   string
+	repeat bool // Node was already expanded further up
+}
+
+// fetchDepGraphCmd loads a project's dependency graph.
+func fetchDepGraphCmd(client *compose.Client, project string) tea.Cmd {
+	return func() tea.Msg {
+		graph, err := client.GetDependencyGraph()
+		return depGraphMsg{project: project, graph: graph, err: err}
+	}
+}
+
+// toggleDepGraph switches the services pane between the table and the
+// dependency graph, loading the graph when it's shown.
+func (m *Model) toggleDepGraph() tea.Cmd {
+	m.showGraph = !m.showGraph
+	if !m.showGraph {
+		return nil
+	}
+	return m.refreshDepGraphCmd()
+}
+
+// refreshDepGraphCmd reloads the current project's graph.
+func (m *Model) refreshDepGraphCmd() tea.Cmd {
+	p := m.currentProject()
+	if p == nil {
+		return nil
+	}
+	client := m.getOrCreateClient(p)
+	if client == nil {
+		return nil
+	}
+	return fetchDepGraphCmd(client, p.Name)
+}
+
+// handleDepGraph stores a loaded graph if it's still for the current project.
+func (m *Model) handleDepGraph(msg depGraphMsg) {
+	p := m.currentProject()
+	if p == nil || p.Name != msg.project {
+		return
+	}
+	m.depGraph = msg.graph
+	m.depGraphProject = msg.project
+	m.depGraphErr = msg.err
+}
+
+// graphLines lays out a dependency graph as a tree per root (services nothing
+// depends on), each service above the services it depends on. A service
+// reachable from several places is expanded once and marked as a repeat
+// elsewhere.
+func graphLines(deps map[string][]string) []graphLine {
+	dependedOn := make(map[string]bool)
+	for _, list := range deps {
+		for _, dep := range list {
+			dependedOn[dep] = true
+		}
+	}
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []graphLine
+	expanded := make(map[string]bool)
+	var walk func(name, prefix, childPrefix string)
+	walk = func(name, prefix, childPrefix string) {
+		if expanded[name] {
+			lines = append(lines, graphLine{prefix: prefix, name: name, repeat: true})
+			return
+		}
+		expanded[name] = true
+		lines = append(lines, graphLine{prefix: prefix, name: name})
+		children := deps[name]
+		for i, child := range children {
+			if i == len(children)-1 {
+				walk(child, childPrefix+"└─ ", childPrefix+"   ")
+			} else {
+				walk(child, childPrefix+"├─ ", childPrefix+"│  ")
+			}
+		}
+	}
+
+	for _, name := range names {
+		if !dependedOn[name] {
+			walk(name, "", "")
+		}
+	}
+	// Services only reachable through a cycle have no root
+	for _, name := range names {
+		if !expanded[name] {
+			walk(name, "", "")
+		}
+	}
+	return lines
+}
+
+// depGraphLines returns the current graph's layout, or nil if it isn't loaded.
+func (m *Model) depGraphLines() []graphLine {
+	if m.depGraph == nil {
+		return nil
+	}
+	return graphLines(m.depGraph.Dependencies())
+}
+
+// selectedServiceName returns the name of the selected service, if any.
+func (m *Model) selectedServiceName() string {
+	if m.selectedService < len(m.services) {
+		return m.services[m.selectedService].Name
+	}
+	return ""
+}
+
+// moveGraphSelection moves the service selection through the graph in
+// display order, skipping repeated nodes.
+func (m *Model) moveGraphSelection(delta int) {
+	var order []string
+	for _, line := range m.depGraphLines() {
+		if !line.repeat {
+			order = append(order, line.name)
+		}
+	}
+	if len(order) == 0 {
+		return
+	}
+
+	pos := 0
+	selected := m.selectedServiceName()
+	for i, name := range order {
+		if name == selected {
+			pos = i + delta
+			break
+		}
+	}
+	if pos < 0 || pos >= len(order) {
+		return
+	}
+	for i, svc := range m.services {
+		if svc.Name == order[pos] {
+			m.selectedService = i
+			m.servicesTable.SetCursor(i)
+			return
+		}
+	}
+}
+
+// startWithDepsCmd starts the selected service and everything it needs.
+func startWithDepsCmd(client *compose.Client, service string) tea.Cmd {
+	return func() tea.Msg {
+		started, err := client.StartWithDependencies(service)
+		return depOperationMsg{service: service, operation: "start", affected: started, err: err}
+	}
+}
+
+// stopDependentsCmd stops the selected service and everything that needs it.
+func stopDependentsCmd(client *compose.Client, service string) tea.Cmd {
+	return func() tea.Msg {
+		stopped, err := client.StopWithDependents(service)
+		return depOperationMsg{service: service, operation: "stop", affected: stopped, err: err}
+	}
+}
+
+// confirmStopDependents asks before stopping a service and its dependents.
+func (m *Model) confirmStopDependents(client *compose.Client, service string) {
+	prompt := fmt.Sprintf("Stop %s and everything that depends on it?", service)
+	if p := m.currentProject(); p != nil && m.depGraph != nil && m.depGraphProject == p.Name {
+		if n := len(m.depGraph.StopOrder(service)) - 1; n > 0 {
+			prompt = fmt.Sprintf("Stop %s and %d dependent service(s)?", service, n)
+		}
+	}
+	m.confirm.Show(
+		prompt,
+		stopDependentsCmd(client, service),
+		func() tea.Msg { return nil },
+	)
+}
+
+// handleDepOperation reports a dependency-aware start or stop.
+func (m *Model) handleDepOperation(msg depOperationMsg) []tea.Cmd {
+	if msg.operation == "stop" {
+		// Don't auto-restart services the user stopped
+		if p := m.currentProject(); p != nil {
+			for _, service := range msg.affected {
+				m.restarts.Hold(p.Name, service)
+			}
+		}
+	}
+
+	if msg.err != nil {
+		m.toast.Show(
+			fmt.Sprintf("Failed to %s %s: %s", msg.operation, msg.service, describeComposeError(msg.err)),
+			ToastError,
+			5*time.Second,
+		)
+	} else if len(msg.affected) == 0 {
+		text := fmt.Sprintf("%s and its dependencies are already running", msg.service)
+		if msg.operation == "stop" {
+			text = fmt.Sprintf("%s and its dependents are already stopped", msg.service)
+		}
+		m.toast.Show(text, ToastInfo, 3*time.Second)
+	} else {
+		verb := "Started"
+		if msg.operation == "stop" {
+			verb = "Stopped"
+		}
+		m.toast.Show(fmt.Sprintf("%s %s", verb, strings.Join(msg.affected, ", ")), ToastSuccess, 3*time.Second)
+	}
+	return []tea.Cmd{m.toast.TickCmd(), m.pollServicesCmd()}
+}
+
+// renderDepGraph draws the dependency tree, colored by service state, in a
+// window that keeps the selected service visible.
+func (m *Model) renderDepGraph(width, height int) string {
+	if m.depGraphErr != nil {
+		return lipgloss.NewStyle().
+			Foreground(m.styles.theme.Error).
+			Render("Failed to load dependency graph: " + describeComposeError(m.depGraphErr))
+	}
+	lines := m.depGraphLines()
+	if lines == nil {
+		return lipgloss.NewStyle().Foreground(m.styles.theme.Muted).Render("Loading dependency graph...")
+	}
+
+	projectName := ""
+	if p := m.currentProject(); p != nil {
+		projectName = p.Name
+	}
+	byName := make(map[string]compose.ProcessStatus, len(m.services))
+	for _, svc := range m.services {
+		byName[svc.Name] = svc
+	}
+
+	selected := m.selectedServiceName()
+	selectedLine := 0
+	for i, line := range lines {
+		if line.name == selected && !line.repeat {
+			selectedLine = i
+			break
+		}
+	}
+
+	// Scroll so the selected service stays in view
+	start := 0
+	if height > 0 && len(lines) > height {
+		start = selectedLine - height/2
+		if start < 0 {
+			start = 0
+		}
+		if start > len(lines)-height {
+			start = len(lines) - height
+		}
+		lines = lines[start : start+height]
+	}
+
+	muted := lipgloss.NewStyle().Foreground(m.styles.theme.Muted)
+	var rows []string
+	for i, line := range lines {
+		svc, known := byName[line.name]
+		var glyph, detail string
+		switch {
+		case !known:
+			glyph = muted.Render("?")
+		case m.health.IsFlapping(projectName, svc.Name):
+			glyph = m.styles.StatusDegraded.Render("◐")
+			detail = "flapping"
+		case svc.IsRunning && !m.serviceReady(projectName, svc.Name):
+			glyph = m.styles.StatusDegraded.Render("◌")
+			detail = "starting"
+		case svc.IsRunning:
+			glyph = m.styles.StatusRunning.Render("●")
+		case svc.ExitCode != 0:
+			glyph = m.styles.StatusStale.Render("✗")
+			detail = fmt.Sprintf("exit %d", svc.ExitCode)
+		default:
+			glyph = m.styles.StatusIdle.Render("○")
+			detail = "stopped"
+		}
+		if line.repeat {
+			detail = "↑"
+		}
+
+		name := line.name
+		if start+i == selectedLine && m.focused == PaneServices {
+			name = lipgloss.NewStyle().Foreground(m.styles.theme.Primary).Bold(true).Render("▸ " + name)
+		} else if line.repeat {
+			name = muted.Render(name)
+		}
+
+		row := muted.Render(line.prefix) + glyph + " " + name
+		if detail != "" {
+			row += " " + muted.Render(detail)
+		}
+		rows = append(rows, lipgloss.NewStyle().MaxWidth(width).Render(row))
+	}
+	return strings.Join(rows, "\n")
+}
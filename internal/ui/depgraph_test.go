@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// storefrontDeps is web -> api -> {postgres, redis}, worker -> redis.
+var storefrontDeps = map[string][]string{
+	"web":      {"api"},
+	"api":      {"postgres", "redis"},
+	"postgres": {},
+	"redis":    {},
+	"worker":   {"redis"},
+}
+
+func TestGraphLines(t *testing.T) {
+	want := []graphLine{
+		{prefix: "", name: "web"},
+		{prefix: "└─ ", name: "api"},
+		{prefix: "   ├─ ", name: "postgres"},
+		{prefix: "   └─ ", name: "redis"},
+		{prefix: "", name: "worker"},
+		{prefix: "└─ ", name: "redis", repeat: true},
+	}
+	if got := graphLines(storefrontDeps); !reflect.DeepEqual(got, want) {
+		t.Errorf("graphLines() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestGraphLinesIncludesCycles(t *testing.T) {
+	lines := graphLines(map[string][]string{"a": {"b"}, "b": {"a"}})
+	if len(lines) != 3 || lines[0].name != "a" || !lines[2].repeat {
+		t.Errorf("a cycle should be drawn once with a repeat marker, got %+v", lines)
+	}
+}
+
+func TestMoveGraphSelection(t *testing.T) {
+	m := New(config.Default(), &registry.Registry{})
+	for _, name := range []string{"api", "postgres", "redis", "web", "worker"} {
+		m.services = append(m.services, compose.ProcessStatus{Name: name})
+	}
+	m.depGraph = &compose.DependencyGraph{Nodes: map[string]*compose.DependencyNode{}}
+	nodes := make(map[string]*compose.DependencyNode)
+	for name := range storefrontDeps {
+		nodes[name] = &compose.DependencyNode{
+			ProcessStatus: compose.ProcessStatus{Name: name},
+			DependsOn:     map[string]*compose.DependencyNode{},
+		}
+	}
+	for name, deps := range storefrontDeps {
+		for _, dep := range deps {
+			nodes[name].DependsOn[dep] = nodes[dep]
+		}
+	}
+	m.depGraph.Nodes["web"] = nodes["web"]
+	m.depGraph.Nodes["worker"] = nodes["worker"]
+
+	// Graph order is web, api, postgres, redis, worker
+	m.selectedService = 3 // web
+	m.moveGraphSelection(1)
+	if got := m.selectedServiceName(); got != "api" {
+		t.Errorf("after moving down from web, selected %q, want api", got)
+	}
+	m.moveGraphSelection(-1)
+	m.moveGraphSelection(-1)
+	if got := m.selectedServiceName(); got != "web" {
+		t.Errorf("moving up past the top should stay on web, got %q", got)
+	}
+}
+
+func TestDependencyActionsAgainstFakeServer(t *testing.T) {
+	m, p, srv := newFakeProject(t)
+	srv.AddProcess("web", "api")
+	srv.AddProcess("api", "db")
+	srv.AddProcess("db")
+	poll(t, m)
+
+	cmd := m.toggleDepGraph()
+	if !m.showGraph || cmd == nil {
+		t.Fatal("toggleDepGraph() should show the graph and load it")
+	}
+	m.Update(cmd())
+	if m.depGraph == nil || m.depGraphProject != p.Name {
+		t.Fatalf("graph not loaded: %v", m.depGraphErr)
+	}
+
+	client := m.getOrCreateClient(p)
+	m.Update(stopDependentsCmd(client, "api")())
+	if web, _ := srv.Process("web"); web.Running {
+		t.Error("stopping api with dependents should stop web")
+	}
+	if db, _ := srv.Process("db"); !db.Running {
+		t.Error("db doesn't depend on api and should keep running")
+	}
+
+	m.Update(startWithDepsCmd(client, "web")())
+	if web, _ := srv.Process("web"); !web.Running {
+		t.Error("web should be started again")
+	}
+
+	if m.toggleDepGraph() != nil || m.showGraph {
+		t.Error("toggling again should hide the graph")
+	}
+}
@@ -0,0 +1,310 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+	"github.com/infktd/devdash/internal/probe"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/stack"
+)
+
+// groupListItem shows a project group and its aggregate state in the
+// sidebar. Group rows aren't selectable; group actions apply to the group
+// of the selected project.
+type groupListItem struct {
+	group  *registry.Group
+	state  registry.ProjectState
+	active int // Projects of the group that are up
+}
+
+func (i groupListItem) FilterValue() string {
+	return ""
+}
+
+func (i groupListItem) Title() string {
+	return i.group.Name
+}
+
+func (i groupListItem) Description() string {
+	return fmt.Sprintf("%d/%d active", i.active, len(i.group.Projects))
+}
+
+// isProjectItem reports whether a sidebar item is a project, as opposed to
+// a section header or group row.
+func isProjectItem(item list.Item) bool {
+	_, ok := item.(projectListItem)
+	return ok
+}
+
+// groupStep is a step of a running group action.
+type groupStep struct {
+	project string
+	step    string // stack.Starting, stack.Waiting or stack.Stopping
+}
+
+// groupStepMsg reports a step of a group action and carries the channels
+// to wait on for the next one.
+type groupStepMsg struct {
+	group  string
+	step   groupStep
+	steps  <-chan groupStep
+	result <-chan groupDoneMsg
+}
+
+// groupDoneMsg reports the end of a group action.
+type groupDoneMsg struct {
+	group   string
+	action  string // "start", "stop" or "restart"
+	results []stack.Result
+	err     error
+}
+
+// groupItems builds the GROUPS section of the sidebar from cached states.
+func (m *Model) groupItems() []list.Item {
+	if len(m.registry.Groups) == 0 {
+		return nil
+	}
+	items := []list.Item{sectionHeaderItem{title: "GROUPS"}}
+	for _, g := range m.registry.Groups {
+		var states []registry.ProjectState
+		active := 0
+		for _, path := range g.Projects {
+			state, ok := m.projectStates[path]
+			if !ok {
+				state = registry.StateMissing
+			}
+			if state.IsActive() {
+				active++
+			}
+			states = append(states, state)
+		}
+		items = append(items, groupListItem{
+			group:  g,
+			state:  registry.AggregateState(states),
+			active: active,
+		})
+	}
+	return items
+}
+
+// currentGroup returns the first group the selected project belongs to.
+func (m *Model) currentGroup() *registry.Group {
+	p := m.currentProject()
+	if p == nil {
+		return nil
+	}
+	if groups := m.registry.GroupsOf(p.Path); len(groups) > 0 {
+		return groups[0]
+	}
+	return nil
+}
+
+// beginGroupMode waits for the group action key that follows g.
+func (m *Model) beginGroupMode() tea.Cmd {
+	g := m.currentGroup()
+	if g == nil {
+		m.toast.Show("Project isn't in a group", ToastInfo, 2*time.Second)
+		return m.toast.TickCmd()
+	}
+	m.groupMode = true
+	return nil
+}
+
+// handleGroupKey runs the group action chosen after g; any other key
+// cancels.
+func (m *Model) handleGroupKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.groupMode = false
+	g := m.currentGroup()
+	if g == nil {
+		return m, nil
+	}
+
+	var action string
+	switch {
+	case key.Matches(msg, m.keys.Start):
+		action = "start"
+	case key.Matches(msg, m.keys.Stop):
+		action = "stop"
+	case key.Matches(msg, m.keys.Restart):
+		action = "restart"
+	default:
+		return m, nil
+	}
+
+	if m.groupBusy[g.Name] {
+		m.toast.Show(fmt.Sprintf("Group %s is busy", g.Name), ToastInfo, 2*time.Second)
+		return m, m.toast.TickCmd()
+	}
+	return m, m.runGroupAction(g, action)
+}
+
+// runGroupAction starts a group action in the background and returns the
+// command that reports its steps.
+func (m *Model) runGroupAction(g *registry.Group, action string) tea.Cmd {
+	m.groupBusy[g.Name] = true
+	for _, path := range g.Projects {
+		p := m.registry.FindByPath(path)
+		if p == nil {
+			continue
+		}
+		if action == "start" {
+			m.restarts.ReleaseProject(p.Name)
+		} else {
+			// Services exiting during shutdown are not crashes to restart
+			m.restarts.HoldProject(p.Name)
+		}
+	}
+
+	steps := make(chan groupStep, 16)
+	result := make(chan groupDoneMsg, 1)
+	runner := &stack.Runner{
+		State: readinessState(m.config.Probes, m.health),
+		OnStep: func(p *registry.Project, step string) {
+			steps <- groupStep{project: p.Name, step: step}
+		},
+	}
+	// The runner works on copies; the registry may be reloaded meanwhile
+	reg, g := m.registry.Snapshot(g)
+	go func() {
+		defer close(steps)
+		var results []stack.Result
+		var err error
+		switch action {
+		case "start":
+			results, err = runner.Start(context.Background(), reg, g)
+		case "stop":
+			results, err = runner.Stop(context.Background(), reg, g)
+		default:
+			results, err = runner.Restart(context.Background(), reg, g)
+		}
+		result <- groupDoneMsg{group: g.Name, action: action, results: results, err: err}
+	}()
+
+	m.toast.Show(fmt.Sprintf("%s group %s...", actionVerb(action), g.Name), ToastInfo, 3*time.Second)
+	return tea.Batch(waitForGroupStepCmd(g.Name, steps, result), m.toast.TickCmd())
+}
+
+// readinessState reports project state the way the sidebar does, so a
+// group's wait gate also waits for readiness probes.
+func readinessState(probes []config.ProbeConfig, monitor *health.Monitor) func(p *registry.Project) registry.ProjectState {
+	return func(p *registry.Project) registry.ProjectState {
+		return p.DetectStateWithReadiness(func(service string) bool {
+			if probe.For(probes, p.Name, service) == nil {
+				return true
+			}
+			ready, _ := monitor.Readiness(p.Name, service)
+			return ready
+		})
+	}
+}
+
+// waitForGroupStepCmd waits for the next step of a group action, or for
+// its result once all steps are done.
+func waitForGroupStepCmd(group string, steps <-chan groupStep, result <-chan groupDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		step, ok := <-steps
+		if !ok {
+			return <-result
+		}
+		return groupStepMsg{group: group, step: step, steps: steps, result: result}
+	}
+}
+
+// handleGroupStep shows a group action's progress.
+func (m *Model) handleGroupStep(msg groupStepMsg) []tea.Cmd {
+	if msg.step.step == stack.Starting {
+		// A restart held the group's projects while stopping them
+		m.restarts.ReleaseProject(msg.step.project)
+	}
+	m.toast.Show(fmt.Sprintf("%s: %s %s", msg.group, msg.step.step, msg.step.project), ToastInfo, 3*time.Second)
+	return []tea.Cmd{
+		waitForGroupStepCmd(msg.group, msg.steps, msg.result),
+		m.toast.TickCmd(),
+	}
+}
+
+// handleGroupDone reports the end of a group action and refreshes states.
+func (m *Model) handleGroupDone(msg groupDoneMsg) []tea.Cmd {
+	delete(m.groupBusy, msg.group)
+	if msg.err != nil {
+		m.toast.Show(fmt.Sprintf("Failed to %s group %s: %v", msg.action, msg.group, msg.err), ToastError, 5*time.Second)
+	} else {
+		var changed []string
+		for _, r := range msg.results {
+			if r.Result == stack.Started || r.Result == stack.Stopped {
+				changed = append(changed, r.Project.Name)
+			}
+		}
+		text := fmt.Sprintf("Group %s %s", msg.group, actionDone(msg.action))
+		if len(changed) > 0 {
+			text += ": " + strings.Join(changed, ", ")
+		}
+		m.toast.Show(text, ToastSuccess, 3*time.Second)
+	}
+	for _, r := range msg.results {
+		m.refreshState(r.Project.Path)
+	}
+	m.updateDisplayedProjects()
+	return []tea.Cmd{m.toast.TickCmd(), m.pollServicesCmd()}
+}
+
+// actionVerb returns the present participle of a group action.
+func actionVerb(action string) string {
+	switch action {
+	case "start":
+		return "Starting"
+	case "stop":
+		return "Stopping"
+	default:
+		return "Restarting"
+	}
+}
+
+// actionDone returns the past tense of a group action.
+func actionDone(action string) string {
+	switch action {
+	case "start":
+		return "started"
+	case "stop":
+		return "stopped"
+	default:
+		return "restarted"
+	}
+}
+
+// renderGroup draws a group row: aggregate state, name and active count.
+func (d *projectDelegate) renderGroup(w io.Writer, item groupListItem) {
+	var glyph string
+	switch item.state {
+	case registry.StateRunning:
+		glyph = d.styles.StatusRunning.Render("●")
+	case registry.StateDegraded:
+		glyph = d.styles.StatusDegraded.Render("◐")
+	case registry.StateStarting:
+		glyph = d.styles.StatusDegraded.Render("◌")
+	case registry.StateStale:
+		glyph = d.styles.StatusStale.Render("✗")
+	case registry.StateMissing:
+		glyph = d.styles.StatusMissing.Render("✗")
+	default:
+		glyph = d.styles.StatusIdle.Render("○")
+	}
+
+	count := fmt.Sprintf("%d/%d", item.active, len(item.group.Projects))
+	if d.model != nil && d.model.groupBusy[item.group.Name] {
+		count += " …"
+	}
+	muted := lipgloss.NewStyle().Foreground(d.styles.theme.Muted)
+	fmt.Fprintf(w, "   %s %s %s", glyph, d.styles.ProjectItem.Render(item.group.Name), muted.Render(count))
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/stack"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestGroupItemsAggregateState(t *testing.T) {
+	reg := &registry.Registry{}
+	reg.AddProject("/src/api")
+	reg.AddProject("/src/web")
+	reg.AddGroup("product", []string{"/src/api", "/src/web"})
+	m := New(config.Default(), reg)
+
+	m.projectStates["/src/api"] = registry.StateRunning
+	m.projectStates["/src/web"] = registry.StateIdle
+	items := m.groupItems()
+	if len(items) != 2 {
+		t.Fatalf("expected a header and one group, got %d items", len(items))
+	}
+	g, ok := items[1].(groupListItem)
+	if !ok || g.state != registry.StateDegraded || g.active != 1 {
+		t.Errorf("unexpected group item: %+v", items[1])
+	}
+
+	m.projectStates["/src/web"] = registry.StateRunning
+	if g := m.groupItems()[1].(groupListItem); g.state != registry.StateRunning {
+		t.Errorf("group state = %v, want running", g.state)
+	}
+}
+
+func TestSidebarSkipsGroupRows(t *testing.T) {
+	reg := &registry.Registry{}
+	a := reg.AddProject(t.TempDir())
+	b := reg.AddProject(t.TempDir())
+	reg.AddGroup("product", []string{a.Path, b.Path})
+	m := New(config.Default(), reg)
+	m.focused = PaneSidebar
+	m.updateDisplayedProjects()
+
+	if !isProjectItem(m.projectsList.SelectedItem()) {
+		t.Fatalf("selection should start on a project, got %T", m.projectsList.SelectedItem())
+	}
+	first := m.currentProject()
+	m.moveDown()
+	if m.currentProject() == first {
+		t.Error("moving down should select the next project")
+	}
+	m.moveUp()
+	m.moveUp()
+	if m.currentProject() != first {
+		t.Errorf("moving up past the group rows should stay on the first project")
+	}
+}
+
+func TestGroupKeyWithoutGroup(t *testing.T) {
+	m, _, _ := newFakeProject(t)
+	m.showSplash = false
+	m.focused = PaneSidebar
+	m.updateDisplayedProjects()
+
+	m.handleKeyPress(runeKey('g'))
+	if m.groupMode {
+		t.Error("g should not enter group mode for a project outside any group")
+	}
+}
+
+func TestGroupStopFromSidebar(t *testing.T) {
+	m, p, srv := newFakeProject(t)
+	srv.AddProcess("web")
+	m.registry.AddGroup("product", []string{p.Path})
+	m.showSplash = false
+	m.focused = PaneSidebar
+	m.updateDisplayedProjects()
+
+	m.handleKeyPress(runeKey('g'))
+	if !m.groupMode {
+		t.Fatal("g should enter group mode")
+	}
+	_, cmd := m.handleKeyPress(runeKey('x'))
+	if m.groupMode || cmd == nil || !m.groupBusy["product"] {
+		t.Fatal("x should leave group mode and stop the group")
+	}
+
+	// The first command of the batch waits for the group's steps
+	wait := cmd().(tea.BatchMsg)[0]
+	step, ok := wait().(groupStepMsg)
+	if !ok || step.step.step != stack.Stopping || step.step.project != p.Name {
+		t.Fatalf("expected a stopping step, got %+v", step)
+	}
+	done, ok := m.handleGroupStep(step)[0]().(groupDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("expected the group to stop, got %+v", done)
+	}
+	if len(done.results) != 1 || done.results[0].Result != stack.Stopped {
+		t.Errorf("unexpected results: %+v", done.results)
+	}
+
+	m.handleGroupDone(done)
+	if m.groupBusy["product"] {
+		t.Error("the group should no longer be busy")
+	}
+	if web, _ := srv.Process("web"); web.Running {
+		t.Error("stopping the group should stop the project's processes")
+	}
+}
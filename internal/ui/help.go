@@ -107,9 +107,11 @@ func (h *HelpPanel) View() string {
 	leftCol += h.styles.Title.Render("GLOBAL") + "\n"
 	leftCol += "  " + k("q") + "       Quit (detach)\n"
 	leftCol += "  " + k("Ctrl+X") + "  Shutdown all\n"
+	leftCol += "  " + k("Ctrl+C") + "  Cancel start\n"
 	leftCol += "  " + k("S") + "       Settings\n"
 	leftCol += "  " + k("E") + "       Edit config\n"
 	leftCol += "  " + k("H") + "       Alerts\n"
+	leftCol += "  " + k("M") + "       Metrics\n"
 	leftCol += "  " + k("?") + "       This help\n\n"
 
 	// NAVIGATION
@@ -127,6 +129,9 @@ func (h *HelpPanel) View() string {
 	leftCol += "  " + k("x") + "       Stop project\n"
 	leftCol += "  " + k("d") + "       Delete project\n"
 	leftCol += "  " + k("c") + "       Repair stale\n"
+	leftCol += "  " + k("g") + "       Group: then s/x/r\n"
+	leftCol += "  " + kb("f/t") + "     Favorite/Tags\n"
+	leftCol += "  " + kb("v/o") + "     Group/sort by\n"
 	leftCol += "  " + k("Ctrl+h") + "  Hide/show\n\n"
 
 	// SERVICES
@@ -134,7 +139,10 @@ func (h *HelpPanel) View() string {
 	rightCol += "  " + k("s") + "       Start service\n"
 	rightCol += "  " + k("x") + "       Stop service\n"
 	rightCol += "  " + k("r") + "       Restart service\n"
-	rightCol += "  " + kb("Enter") + "   Filter logs\n\n\n"
+	rightCol += "  " + k("D") + "       Dependency graph\n"
+	rightCol += "  " + k("A") + "       Start with dependencies\n"
+	rightCol += "  " + k("X") + "       Stop with dependents\n"
+	rightCol += "  " + kb("Enter") + "   Filter logs\n\n"
 
 	// LOGS
 	leftCol += h.styles.Title.Render("LOGS") + "\n"
@@ -166,10 +174,10 @@ func (h *HelpPanel) View() string {
 		Align(lipgloss.Center)
 	content += footerStyle.Render(footerText)
 
-	// Fixed size modal box (80 cols x 28 rows)
+	// Fixed size modal box (80 cols x 30 rows)
 	modalStyle := h.styles.ModalBorder.
 		Width(80).
-		Height(28).
+		Height(30).
 		Padding(1, 2)
 
 	return modalStyle.Render(content)
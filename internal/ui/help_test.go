@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -79,3 +80,15 @@ func TestHelpPanelView(t *testing.T) {
 		t.Error("View() should return content when visible")
 	}
 }
+
+func TestHelpPanelListsSidebarKeys(t *testing.T) {
+	panel := NewHelpPanel(NewStyles(GetTheme("matrix")), 100, 50)
+	panel.Show()
+
+	view := panel.View()
+	for _, want := range []string{"Favorite/Tags", "Group/sort by", "Hide/show"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("help should list %q", want)
+		}
+	}
+}
@@ -5,13 +5,15 @@ import "github.com/charmbracelet/bubbles/key"
 // KeyMap defines all keybindings.
 type KeyMap struct {
 	// Global
-	Quit       key.Binding
-	Shutdown   key.Binding
-	Settings   key.Binding
-	EditConfig key.Binding
-	Help       key.Binding
-	Refresh    key.Binding
-	History    key.Binding
+	Quit        key.Binding
+	Shutdown    key.Binding
+	Settings    key.Binding
+	EditConfig  key.Binding
+	Help        key.Binding
+	Refresh     key.Binding
+	History     key.Binding
+	Metrics     key.Binding
+	CancelStart key.Binding
 
 	// Navigation
 	Up     key.Binding
@@ -29,12 +31,22 @@ type KeyMap struct {
 	Restart key.Binding
 	Search  key.Binding
 
+	// Dependencies
+	Graph          key.Binding
+	StartWithDeps  key.Binding
+	StopDependents key.Binding
+
 	// Project Management
-	Hide   key.Binding
-	Delete key.Binding
-	Edit   key.Binding
-	Move   key.Binding
-	Repair key.Binding
+	Hide     key.Binding
+	Delete   key.Binding
+	Edit     key.Binding
+	Move     key.Binding
+	Repair   key.Binding
+	Group    key.Binding
+	Favorite key.Binding
+	Tags     key.Binding
+	GroupBy  key.Binding
+	Sort     key.Binding
 
 	// Logs
 	Follow    key.Binding
@@ -59,6 +71,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("ctrl+x"),
 			key.WithHelp("ctrl+x", "shutdown all"),
 		),
+		CancelStart: key.NewBinding(
+			key.WithKeys("ctrl+c"),
+			key.WithHelp("ctrl+c", "cancel start"),
+		),
 		Settings: key.NewBinding(
 			key.WithKeys("S"),
 			key.WithHelp("S", "settings"),
@@ -79,6 +95,10 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("H"),
 			key.WithHelp("H", "alerts"),
 		),
+		Metrics: key.NewBinding(
+			key.WithKeys("M"),
+			key.WithHelp("M", "metrics"),
+		),
 
 		// Navigation
 		Up: key.NewBinding(
@@ -132,6 +152,20 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("/", "search"),
 		),
 
+		// Dependencies
+		Graph: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "dependency graph"),
+		),
+		StartWithDeps: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "start with dependencies"),
+		),
+		StopDependents: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "stop with dependents"),
+		),
+
 		// Project Management
 		Hide: key.NewBinding(
 			key.WithKeys("ctrl+h"),
@@ -153,6 +187,26 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("c"),
 			key.WithHelp("c", "repair stale"),
 		),
+		Group: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "group actions"),
+		),
+		Favorite: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "favorite"),
+		),
+		Tags: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "edit tags"),
+		),
+		GroupBy: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "group sidebar by"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "sort sidebar by"),
+		),
 
 		// Logs
 		Follow: key.NewBinding(
@@ -200,7 +254,8 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Tab, k.Select, k.Back},
 		{k.Start, k.Stop, k.Restart, k.Search},
+		{k.Graph, k.StartWithDeps, k.StopDependents},
 		{k.Follow, k.Top, k.Bottom, k.Wrap, k.NextMatch, k.PrevMatch},
-		{k.Settings, k.History, k.Help, k.Quit},
+		{k.Settings, k.History, k.Metrics, k.Help, k.Quit},
 	}
 }
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+)
+
+const (
+	// logStreamReplay is the number of existing lines replayed when a
+	// service's stream is first opened.
+	logStreamReplay = 100
+
+	// logStreamBatch caps how many buffered lines are applied per message.
+	logStreamBatch = 500
+)
+
+// logStreamStartedMsg is sent when a service's log stream is connected.
+type logStreamStartedMsg struct {
+	gen     int
+	service string
+	lines   <-chan compose.LogLine
+}
+
+// logLinesMsg delivers a batch of streamed lines for one service.
+type logLinesMsg struct {
+	gen     int
+	service string
+	lines   []compose.LogLine
+	ch      <-chan compose.LogLine
+}
+
+// logStreamEndedMsg is sent when a stream fails to connect or disconnects.
+type logStreamEndedMsg struct {
+	gen     int
+	service string
+	err     error
+}
+
+// resetLogStreams cancels all streams of the current project.
+// Messages from cancelled streams are dropped by generation.
+func (m *Model) resetLogStreams() {
+	if m.logStreamCancel != nil {
+		m.logStreamCancel()
+	}
+	m.logStreamCtx, m.logStreamCancel = context.WithCancel(context.Background())
+	m.logStreamGen++
+	m.logStreams = make(map[string]bool)
+	m.logLastLine = make(map[string]string)
+}
+
+// startLogStreamsCmd opens a stream for every current service that is not already followed.
+func (m *Model) startLogStreamsCmd() tea.Cmd {
+	p := m.currentProject()
+	if p == nil {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	for _, svc := range m.services {
+		active, seen := m.logStreams[svc.Name]
+		if active {
+			continue
+		}
+		// Reconnecting - resume after the last line shown
+		last, resume := m.logLastLine[svc.Name]
+		m.logStreams[svc.Name] = true
+		cmds = append(cmds, openLogStreamCmd(m.logStreamCtx, m.logStreamGen, p.SocketPath(), svc.Name, seen && resume, last))
+	}
+	return tea.Batch(cmds...)
+}
+
+// openLogStreamCmd connects to a service's log stream. When resuming, the
+// lines written after last are replayed instead of the initial backlog.
+func openLogStreamCmd(ctx context.Context, gen int, socketPath, service string, resume bool, last string) tea.Cmd {
+	return func() tea.Msg {
+		client := compose.NewClient(socketPath)
+		offset := logStreamReplay
+		if resume {
+			offset = resumeOffset(ctx, client, service, last)
+		}
+		lines, err := client.StreamLogs(ctx, []string{service}, offset)
+		if err != nil {
+			return logStreamEndedMsg{gen: gen, service: service, err: err}
+		}
+		return logStreamStartedMsg{gen: gen, service: service, lines: lines}
+	}
+}
+
+// resumeOffset returns how many lines the service wrote after last, looking
+// back at most logStreamReplay lines. If last is not found in that window,
+// the whole window is replayed.
+func resumeOffset(ctx context.Context, client *compose.Client, service, last string) int {
+	tail, err := client.GetLogsContext(ctx, service, 0, logStreamReplay)
+	if err != nil {
+		return logStreamReplay
+	}
+	for i := len(tail) - 1; i >= 0; i-- {
+		if tail[i] == last {
+			return len(tail) - 1 - i
+		}
+	}
+	return logStreamReplay
+}
+
+// waitForLogLinesCmd blocks for the next line and drains whatever else is buffered.
+func waitForLogLinesCmd(gen int, service string, ch <-chan compose.LogLine) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return logStreamEndedMsg{gen: gen, service: service}
+		}
+
+		lines := []compose.LogLine{line}
+		for len(lines) < logStreamBatch {
+			select {
+			case line, ok := <-ch:
+				if !ok {
+					// Deliver what we have, the next wait reports the close
+					return logLinesMsg{gen: gen, service: service, lines: lines, ch: ch}
+				}
+				lines = append(lines, line)
+			default:
+				return logLinesMsg{gen: gen, service: service, lines: lines, ch: ch}
+			}
+		}
+		return logLinesMsg{gen: gen, service: service, lines: lines, ch: ch}
+	}
+}
+
+// handleLogStreamStarted begins reading a newly connected stream.
+func (m *Model) handleLogStreamStarted(msg logStreamStartedMsg) tea.Cmd {
+	if msg.gen != m.logStreamGen {
+		return nil
+	}
+	return waitForLogLinesCmd(msg.gen, msg.service, msg.lines)
+}
+
+// handleLogLines adds streamed lines to the log view and waits for more.
+func (m *Model) handleLogLines(msg logLinesMsg) tea.Cmd {
+	if msg.gen != m.logStreamGen {
+		return nil
+	}
+	for _, line := range msg.lines {
+		service := line.Service
+		if service == "" {
+			service = msg.service
+		}
+		m.logView.AppendLine(service, line.Message)
+	}
+	if n := len(msg.lines); n > 0 {
+		m.logLastLine[msg.service] = msg.lines[n-1].Message
+	}
+	m.logActivity[msg.service] = time.Now()
+	return waitForLogLinesCmd(msg.gen, msg.service, msg.ch)
+}
+
+// handleLogStreamEnded marks a stream as ended so the next poll reopens it.
+func (m *Model) handleLogStreamEnded(msg logStreamEndedMsg) {
+	if msg.gen != m.logStreamGen {
+		return
+	}
+	m.logStreams[msg.service] = false
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func newLogStreamTestModel(t *testing.T) *Model {
+	t.Helper()
+	reg := &registry.Registry{}
+	reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+	m.services = []compose.ProcessStatus{{Name: "web"}, {Name: "db"}}
+	return m
+}
+
+func TestHandleLogLinesKeepsRepeatedLines(t *testing.T) {
+	m := newLogStreamTestModel(t)
+
+	ch := make(chan compose.LogLine)
+	cmd := m.handleLogLines(logLinesMsg{
+		gen:     m.logStreamGen,
+		service: "web",
+		lines: []compose.LogLine{
+			{Service: "web", Message: "ping"},
+			{Service: "web", Message: "ping"},
+			{Service: "web", Message: "ping"},
+		},
+		ch: ch,
+	})
+
+	if got := m.logView.buffer.Len(); got != 3 {
+		t.Errorf("expected 3 entries (identical lines kept), got %d", got)
+	}
+	if cmd == nil {
+		t.Error("handleLogLines should keep waiting for more lines")
+	}
+	if _, ok := m.logActivity["web"]; !ok {
+		t.Error("log activity should be recorded")
+	}
+}
+
+func TestHandleLogLinesDropsStaleGeneration(t *testing.T) {
+	m := newLogStreamTestModel(t)
+	staleGen := m.logStreamGen
+	m.resetLogStreams()
+
+	cmd := m.handleLogLines(logLinesMsg{
+		gen:     staleGen,
+		service: "web",
+		lines:   []compose.LogLine{{Service: "web", Message: "old project"}},
+		ch:      make(chan compose.LogLine),
+	})
+
+	if m.logView.buffer.Len() != 0 {
+		t.Error("lines from a previous project's stream should be dropped")
+	}
+	if cmd != nil {
+		t.Error("stale stream should not be read further")
+	}
+}
+
+func TestStartLogStreamsOpensEachServiceOnce(t *testing.T) {
+	m := newLogStreamTestModel(t)
+
+	if cmd := m.startLogStreamsCmd(); cmd == nil {
+		t.Fatal("expected commands to open streams")
+	}
+	if !m.logStreams["web"] || !m.logStreams["db"] {
+		t.Errorf("both services should be marked as streaming: %v", m.logStreams)
+	}
+
+	// Already streaming - nothing new to open
+	if cmd := m.startLogStreamsCmd(); cmd != nil {
+		t.Error("services with active streams should not be reopened")
+	}
+
+	// A dropped stream is reopened on the next poll
+	m.handleLogStreamEnded(logStreamEndedMsg{gen: m.logStreamGen, service: "web", err: errors.New("closed")})
+	if m.logStreams["web"] {
+		t.Error("ended stream should be marked inactive")
+	}
+	if cmd := m.startLogStreamsCmd(); cmd == nil {
+		t.Error("ended stream should be reopened")
+	}
+}
+
+func TestWaitForLogLinesBatchesBufferedLines(t *testing.T) {
+	ch := make(chan compose.LogLine, 10)
+	for i := 0; i < 5; i++ {
+		ch <- compose.LogLine{Service: "web", Message: "line"}
+	}
+	close(ch)
+
+	msg := waitForLogLinesCmd(1, "web", ch)()
+	lines, ok := msg.(logLinesMsg)
+	if !ok {
+		t.Fatalf("expected logLinesMsg, got %T", msg)
+	}
+	if len(lines.lines) != 5 {
+		t.Errorf("expected 5 batched lines, got %d", len(lines.lines))
+	}
+
+	// Channel is drained and closed - next wait reports the end
+	if _, ok := waitForLogLinesCmd(1, "web", ch)().(logStreamEndedMsg); !ok {
+		t.Error("expected logStreamEndedMsg once the channel is closed")
+	}
+}
+
+func TestReconnectResumesAfterLastShownLine(t *testing.T) {
+	m, p, srv := newFakeProject(t)
+	srv.AddProcess("web")
+	srv.Log("web", "one", "two")
+
+	m.handleLogLines(logLinesMsg{
+		gen:     m.logStreamGen,
+		service: "web",
+		lines:   []compose.LogLine{{Service: "web", Message: "one"}, {Service: "web", Message: "two"}},
+		ch:      make(chan compose.LogLine),
+	})
+	if got := m.logLastLine["web"]; got != "two" {
+		t.Fatalf("last line = %q, want %q", got, "two")
+	}
+
+	// Written while the stream was down
+	srv.Log("web", "three", "four")
+
+	client := compose.NewClient(p.SocketPath())
+	if got := resumeOffset(context.Background(), client, "web", m.logLastLine["web"]); got != 2 {
+		t.Errorf("resume offset = %d, want 2", got)
+	}
+	if got := resumeOffset(context.Background(), client, "web", "long gone"); got != logStreamReplay {
+		t.Errorf("resume offset for an unknown line = %d, want %d", got, logStreamReplay)
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -43,6 +44,21 @@ func (lv *LogView) AddEntry(entry LogEntry) {
 	}
 }
 
+// AppendLine adds a raw log line from a service, detecting its timestamp and level.
+func (lv *LogView) AppendLine(service, message string) {
+	// Try to parse timestamp from log line, fall back to now
+	ts, ok := ParseLogTimestamp(message)
+	if !ok {
+		ts = time.Now()
+	}
+	lv.AddEntry(LogEntry{
+		Timestamp: ts,
+		Service:   service,
+		Level:     DetectLogLevel(message),
+		Message:   message,
+	})
+}
+
 // SetBuffer replaces the internal buffer (for unified view).
 func (lv *LogView) SetBuffer(buf *LogBuffer) {
 	lv.buffer = buf
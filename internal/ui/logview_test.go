@@ -145,3 +145,25 @@ func TestLogViewSearchQuery(t *testing.T) {
 		t.Errorf("SearchQuery() = %q, want 'test'", query)
 	}
 }
+
+func TestLogViewAppendLine(t *testing.T) {
+	theme := GetTheme("matrix")
+	styles := NewStyles(theme)
+
+	lv := NewLogView(styles, 80, 10)
+	lv.AppendLine("api", "2026-01-20 14:30:00 ERROR connection refused")
+
+	lines := lv.buffer.Lines()
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(lines))
+	}
+	if lines[0].Service != "api" {
+		t.Errorf("service = %q, want api", lines[0].Service)
+	}
+	if lines[0].Level != LevelError {
+		t.Errorf("level = %v, want error", lines[0].Level)
+	}
+	if lines[0].Timestamp.Hour() != 14 || lines[0].Timestamp.Minute() != 30 {
+		t.Errorf("timestamp not parsed from line: %v", lines[0].Timestamp)
+	}
+}
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain keeps tests from reading or writing the user's alert history,
+// config and registry.
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "devdash-ui-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_STATE_HOME", filepath.Join(home, "state"))
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/metrics"
+)
+
+// metricsSaveInterval is how often the metrics history is saved, bounding
+// what a crash loses.
+const metricsSaveInterval = time.Minute
+
+// metricsQuitTimeout bounds the save on quit. A save cut short loses only
+// what it was writing; the next load skips a torn line.
+const metricsQuitTimeout = 2 * time.Second
+
+// metricsSaveMsg triggers a save of the metrics history.
+type metricsSaveMsg struct{}
+
+// metricsSaveCmd schedules the next save of the metrics history.
+func metricsSaveCmd() tea.Cmd {
+	return tea.Tick(metricsSaveInterval, func(time.Time) tea.Msg {
+		return metricsSaveMsg{}
+	})
+}
+
+// saveMetricsCmd saves the metrics history off the UI goroutine.
+func (m *Model) saveMetricsCmd() tea.Cmd {
+	store := m.metrics
+	return func() tea.Msg {
+		// Best effort: a failed save only loses history across restarts
+		_ = store.Save()
+		return nil
+	}
+}
+
+// saveMetrics saves the metrics history right away, such as on quit,
+// giving up after metricsQuitTimeout so a slow disk can't hold up quitting.
+func (m *Model) saveMetrics() {
+	store := m.metrics
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = store.Save()
+	}()
+	select {
+	case <-done:
+	case <-time.After(metricsQuitTimeout):
+	}
+}
+
+// recordMetrics adds a poll of a project's running services to the
+// metrics history.
+func (m *Model) recordMetrics(project string, services []compose.ProcessStatus) {
+	if project == "" {
+		return
+	}
+	now := time.Now()
+	for _, svc := range services {
+		if svc.IsRunning {
+			m.metrics.Record(project, svc.Name, now, metrics.Sample{
+				CPU:      svc.CPU,
+				Mem:      svc.Mem,
+				Restarts: svc.Restarts,
+			})
+		}
+	}
+}
+
+// showMetrics opens the metrics panel on the current project and service.
+func (m *Model) showMetrics() {
+	p := m.currentProject()
+	if p == nil {
+		return
+	}
+	service := ""
+	if m.selectedService < len(m.services) {
+		service = m.services[m.selectedService].Name
+	}
+	m.metricsPanel.Show(p.Name, service)
+}
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/infktd/devdash/internal/metrics"
+)
+
+// metricsRange is a span of history the metrics panel can show.
+type metricsRange struct {
+	name string
+	span time.Duration
+}
+
+var metricsRanges = []metricsRange{
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"24h", 24 * time.Hour},
+}
+
+const (
+	metricsChartHeight = 6 // Rows per chart
+	metricsLabelWidth  = 8 // Axis label column, including the axis
+)
+
+// MetricsPanel manages the metrics modal, which charts the CPU, memory and
+// restart history of a project's services.
+type MetricsPanel struct {
+	styles  *Styles
+	store   *metrics.Store
+	visible bool
+	width   int
+	height  int
+
+	project  string
+	services []string
+	service  int // Index into services
+	rangeIdx int // Index into metricsRanges
+}
+
+// NewMetricsPanel creates a metrics panel.
+func NewMetricsPanel(styles *Styles, store *metrics.Store, width, height int) *MetricsPanel {
+	return &MetricsPanel{
+		styles: styles,
+		store:  store,
+		width:  width,
+		height: height,
+	}
+}
+
+// Show opens the panel on a project's history, starting at service if it
+// has any.
+func (p *MetricsPanel) Show(project, service string) {
+	p.visible = true
+	p.project = project
+	p.services = p.store.Services(project)
+	p.service = 0
+	for i, s := range p.services {
+		if s == service {
+			p.service = i
+		}
+	}
+}
+
+// Hide closes the metrics panel.
+func (p *MetricsPanel) Hide() {
+	p.visible = false
+}
+
+// IsVisible returns whether the panel is shown.
+func (p *MetricsPanel) IsVisible() bool {
+	return p.visible
+}
+
+// SetSize updates the panel dimensions.
+func (p *MetricsPanel) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// Update handles input for the metrics panel.
+func (p *MetricsPanel) Update(msg tea.Msg) (*MetricsPanel, tea.Cmd) {
+	if !p.visible {
+		return p, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+
+	switch keyMsg.String() {
+	case "esc", "M":
+		p.visible = false
+	case "up", "k", "left", "h":
+		if n := len(p.services); n > 0 {
+			p.service = (p.service + n - 1) % n
+		}
+	case "down", "j", "right", "l":
+		if n := len(p.services); n > 0 {
+			p.service = (p.service + 1) % n
+		}
+	case "1", "2", "3":
+		p.rangeIdx = int(keyMsg.String()[0] - '1')
+	case "tab":
+		p.rangeIdx = (p.rangeIdx + 1) % len(metricsRanges)
+	}
+
+	return p, nil
+}
+
+// currentService returns the service being charted, or "" if the project
+// has no history.
+func (p *MetricsPanel) currentService() string {
+	if p.service < len(p.services) {
+		return p.services[p.service]
+	}
+	return ""
+}
+
+// innerWidth is the width of the panel's content, growing with the
+// terminal so longer ranges get more detail.
+func (p *MetricsPanel) innerWidth() int {
+	return min(max(p.width-14, 76), 116)
+}
+
+// View renders the metrics panel.
+func (p *MetricsPanel) View() string {
+	if !p.visible {
+		return ""
+	}
+	width := p.innerWidth()
+	center := lipgloss.NewStyle().Width(width).Align(lipgloss.Center)
+	muted := lipgloss.NewStyle().Foreground(p.styles.theme.Muted)
+
+	content := center.Bold(true).Foreground(p.styles.theme.Primary).Render("METRICS  "+p.project) + "\n"
+
+	// Service and range selectors
+	service := p.currentService()
+	var ranges []string
+	for i, r := range metricsRanges {
+		if i == p.rangeIdx {
+			ranges = append(ranges, lipgloss.NewStyle().Foreground(p.styles.theme.Primary).Bold(true).Render("["+r.name+"]"))
+		} else {
+			ranges = append(ranges, muted.Render(" "+r.name+" "))
+		}
+	}
+	selector := strings.Join(ranges, " ")
+	if service != "" {
+		selector = fmt.Sprintf("%s (%d/%d)   %s", service, p.service+1, len(p.services), selector)
+	}
+	content += center.Render(selector) + "\n\n"
+
+	rng := metricsRanges[p.rangeIdx]
+	now := time.Now()
+	var points []metrics.Point
+	if service != "" {
+		points = p.store.Query(p.project, service, rng.span, now)
+	}
+	if len(points) == 0 {
+		text := "No history for " + p.project + " yet"
+		if service != "" {
+			text = fmt.Sprintf("No history for %s in the last %s", service, rng.name)
+		}
+		content += center.Foreground(p.styles.theme.Muted).Render(text) + "\n"
+	} else {
+		last := points[len(points)-1]
+		start := now.Add(-rng.span)
+		// Missing a few buckets is a slow background poll, not a stop
+		maxGap := 3 * metrics.Step(rng.span)
+
+		cpu := func(pt metrics.Point) float64 { return pt.CPU }
+		cpuMax := maxOf(points, cpu)
+		content += p.renderChart(
+			fmt.Sprintf("CPU  now %.1f%%  max %.1f%%", last.CPU, cpuMax),
+			chartColumns(points, cpu, start, rng.span, (width-metricsLabelWidth)*2, maxGap),
+			max(cpuMax, 1),
+			func(v float64) string { return fmt.Sprintf("%.0f%%", v) },
+			p.styles.theme.Primary,
+		)
+		content += p.renderTimeAxis(rng.name) + "\n"
+
+		mem := func(pt metrics.Point) float64 { return float64(pt.Mem) }
+		memMax := maxOf(points, mem)
+		content += p.renderChart(
+			fmt.Sprintf("MEMORY  now %s  max %s", formatBytes(last.Mem), formatBytes(int64(memMax))),
+			chartColumns(points, mem, start, rng.span, (width-metricsLabelWidth)*2, maxGap),
+			max(memMax, 1),
+			func(v float64) string { return formatBytes(int64(v)) },
+			p.styles.theme.Secondary,
+		)
+		content += p.renderTimeAxis(rng.name) + "\n"
+
+		content += fmt.Sprintf("RESTARTS  %d in the last %s\n", metrics.Restarts(points), rng.name)
+	}
+
+	content += "\n"
+	footer := "[↑/↓] service  [1/2/3] 5m/1h/24h  [Tab] next range  [Esc] close"
+	content += center.Render(footer)
+
+	modalStyle := p.styles.ModalBorder.
+		Width(width+4).
+		Padding(1, 2)
+
+	return modalStyle.Render(content)
+}
+
+// renderChart renders a titled braille chart with the top and bottom of
+// its scale labeled.
+func (p *MetricsPanel) renderChart(title string, values []float64, top float64, label func(float64) string, color lipgloss.TerminalColor) string {
+	out := p.styles.Title.Render(title) + "\n"
+	lineStyle := lipgloss.NewStyle().Foreground(color)
+	axisStyle := lipgloss.NewStyle().Foreground(p.styles.theme.Muted)
+	lines := brailleChart(values, top, len(values)/2, metricsChartHeight)
+	for i, line := range lines {
+		axis := strings.Repeat(" ", metricsLabelWidth-1) + "│"
+		switch i {
+		case 0:
+			axis = fmt.Sprintf("%*s ┤", metricsLabelWidth-2, label(top))
+		case len(lines) - 1:
+			axis = fmt.Sprintf("%*s ┤", metricsLabelWidth-2, label(0))
+		}
+		out += axisStyle.Render(axis) + lineStyle.Render(line) + "\n"
+	}
+	return out
+}
+
+// renderTimeAxis labels the start and end of a chart's range.
+func (p *MetricsPanel) renderTimeAxis(rangeName string) string {
+	chartWidth := p.innerWidth() - metricsLabelWidth
+	left := "-" + rangeName
+	gap := max(chartWidth-len(left)-len("now"), 1)
+	axis := strings.Repeat(" ", metricsLabelWidth) + left + strings.Repeat(" ", gap) + "now"
+	return lipgloss.NewStyle().Foreground(p.styles.theme.Muted).Render(axis)
+}
+
+// maxOf returns the highest value of points, or 0 if there are none.
+func maxOf(points []metrics.Point, value func(metrics.Point) float64) float64 {
+	highest := 0.0
+	for _, pt := range points {
+		highest = math.Max(highest, value(pt))
+	}
+	return highest
+}
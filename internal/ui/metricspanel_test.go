@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/metrics"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func TestMetricsPanelKeys(t *testing.T) {
+	store := metrics.NewStore()
+	now := time.Now()
+	for _, svc := range []string{"db", "web", "worker"} {
+		store.Record("api", svc, now, metrics.Sample{CPU: 1})
+	}
+	p := NewMetricsPanel(NewStyles(GetTheme("")), store, 120, 40)
+	p.Show("api", "web")
+
+	if got := p.currentService(); got != "web" {
+		t.Fatalf("Show should start at the given service, got %q", got)
+	}
+	p.Update(runeKey('j'))
+	if got := p.currentService(); got != "worker" {
+		t.Errorf("j should move to the next service, got %q", got)
+	}
+	p.Update(runeKey('j'))
+	if got := p.currentService(); got != "db" {
+		t.Errorf("the service selection should wrap around, got %q", got)
+	}
+	p.Update(runeKey('3'))
+	if got := metricsRanges[p.rangeIdx].name; got != "24h" {
+		t.Errorf("3 should select 24h, got %s", got)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := metricsRanges[p.rangeIdx].name; got != "5m" {
+		t.Errorf("Tab should wrap to 5m, got %s", got)
+	}
+	p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.IsVisible() {
+		t.Error("Esc should close the panel")
+	}
+}
+
+func TestMetricsPanelView(t *testing.T) {
+	store := metrics.NewStore()
+	now := time.Now()
+	for i := range 30 {
+		at := now.Add(time.Duration(i-30) * 10 * time.Second)
+		store.Record("api", "web", at, metrics.Sample{CPU: float64(i), Mem: int64(i) << 20, Restarts: i / 10})
+	}
+	p := NewMetricsPanel(NewStyles(GetTheme("")), store, 120, 40)
+
+	p.Show("api", "")
+	view := p.View()
+	for _, want := range []string{"METRICS  api", "web (1/1)", "CPU  now 29.0%", "MEMORY  now 29.0M", "RESTARTS  2 in the last 5m", "-5m"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+
+	// The samples are close enough to draw a rising line rather than dots,
+	// so every row of both charts has some
+	rows := 0
+	for _, line := range strings.Split(view, "\n") {
+		if strings.IndexFunc(line, func(r rune) bool { return r > 0x2800 && r <= 0x28FF }) >= 0 {
+			rows++
+		}
+	}
+	if rows != 2*metricsChartHeight {
+		t.Errorf("%d chart rows have dots, want %d", rows, 2*metricsChartHeight)
+	}
+
+	p.Show("blog", "")
+	if view := p.View(); !strings.Contains(view, "No history for blog yet") {
+		t.Errorf("a project without history should say so:\n%s", view)
+	}
+}
+
+func TestPollsRecordMetrics(t *testing.T) {
+	reg := &registry.Registry{}
+	p := reg.AddProject(filepath.Join(t.TempDir(), "api"))
+	blog := reg.AddProject(filepath.Join(t.TempDir(), "blog"))
+	m := New(config.Default(), reg)
+	m.metrics = metrics.NewStore()
+	m.metricsPanel = NewMetricsPanel(m.styles, m.metrics, 120, 40)
+	m.showSplash = false
+	m.updateDisplayedProjects()
+
+	m.Update(servicesUpdatedMsg{services: []compose.ProcessStatus{
+		{Name: "web", IsRunning: true, CPU: 12.5, Mem: 1 << 20},
+		{Name: "migrate", IsRunning: false},
+	}})
+	m.handleBackgroundStatus(backgroundStatusMsg{results: []backgroundStatus{{
+		path:     blog.Path,
+		services: []compose.ProcessStatus{{Name: "hugo", IsRunning: true, CPU: 1}},
+	}}})
+
+	points := m.metrics.Query(p.Name, "web", 5*time.Minute, time.Now())
+	if len(points) != 1 || points[0].CPU != 12.5 {
+		t.Errorf("foreground poll should be recorded, got %+v", points)
+	}
+	if got := m.metrics.Services(p.Name); len(got) != 1 {
+		t.Errorf("stopped services shouldn't be recorded, got %v", got)
+	}
+	if got := m.metrics.Services("blog"); len(got) != 1 {
+		t.Errorf("background poll should be recorded, got %v", got)
+	}
+
+	m.handleKeyPress(runeKey('M'))
+	if !m.metricsPanel.IsVisible() || m.metricsPanel.currentService() != "web" {
+		t.Errorf("M should open the metrics panel on the selected service")
+	}
+}
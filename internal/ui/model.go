@@ -1,11 +1,12 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -21,10 +22,15 @@ import (
 
 	"github.com/infktd/devdash/internal/compose"
 	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/devenv"
 	"github.com/infktd/devdash/internal/health"
+	"github.com/infktd/devdash/internal/metrics"
 	"github.com/infktd/devdash/internal/notify"
 	"github.com/infktd/devdash/internal/packages"
 	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/restart"
+	"github.com/infktd/devdash/internal/statecache"
+	"github.com/infktd/devdash/internal/watch"
 )
 
 // FocusedPane tracks which pane has focus.
@@ -94,6 +100,7 @@ type Model struct {
 	showSettings bool
 	showSplash   bool
 	showPackages bool // When true, show packages pane instead of services (narrow terminals)
+	showGraph    bool // When true, show the dependency graph instead of the services table
 
 	// Search state (for logs)
 	searchMode         bool
@@ -104,22 +111,38 @@ type Model struct {
 	projectFilterMode  bool
 	projectFilterInput string
 
+	// Project groups
+	groupMode bool            // g pressed; the next key picks a group action
+	groupBusy map[string]bool // Groups with an action in progress
+
+	// Tag editing for the selected project
+	tagEditMode bool
+	tagInput    string
+
 	// Components
 	logView       *LogView
 	packagesView  *PackagesView
 	toast         *ToastManager
 	alerts        *AlertHistory
 	alertsPanel   *AlertsPanel
+	metrics       *metrics.Store
+	metricsPanel  *MetricsPanel
 	settings      *SettingsPanel
 	helpPanel     *HelpPanel
 	splash        *SplashScreen
 	confirm       *ConfirmDialog
 	health        *health.Monitor
 	notifier      *notify.Notifier
+	notifyPolicy  *notify.Policy
+	notifyHooks   *notify.Hooks
+	restarts      *restart.Supervisor
 	spinner       spinner.Model
 	servicesTable table.Model
 	projectsList  list.Model
 
+	// Sidebar item renderer, replaced when the theme changes
+	projectsDelegate *projectDelegate
+
 	// Loading state
 	loadingOp       string    // Description of current operation
 	loadingProject  string    // Project being operated on
@@ -127,20 +150,55 @@ type Model struct {
 	loadingStage    string    // Current stage description
 	loadingStarted  time.Time // When operation started
 
+	// Project start (devenv up) output and control
+	startLog        []string           // devenv up output for startLogProject
+	startLogProject string             // Project the start log belongs to
+	startCancel     context.CancelFunc // Kills devenv up; nil when not running
+	startStage      devenv.Stage       // Stage parsed from devenv output
+	startFailed     bool               // Start failed; keep the log on screen
+
+	// Reports changes to the config and registry files (nil without SetWatcher)
+	watcher *watch.Watcher
+
+	// Background project discovery
+	scanning    bool                // A scan is running
+	scanCancel  context.CancelFunc  // Cancels the running scan
+	scanGen     int                 // Drops results of a cancelled scan
+	scanAdded   int                 // Projects the running scan added
+	scanMoved   []*registry.Project // Projects the running scan found moved
+	scanUpdated bool                // The running scan changed a known project's layout or identity
+
+	// Reports projects appearing or moving under the scan paths (nil without SetDirWatcher)
+	dirWatcher     *watch.DirWatcher
+	dirWatchFailed bool // Watching failed and was reported
+
 	// Compose clients per project (keyed by project path)
 	clients map[string]*compose.Client
 
 	// Current project services
 	services []compose.ProcessStatus
 
+	// Dependency graph of the current project (loaded while showGraph is on)
+	depGraph        *compose.DependencyGraph
+	depGraphProject string // Project depGraph belongs to
+	depGraphErr     error  // Error from the last graph fetch
+
 	// Displayed projects in sidebar order (active first, then idle)
 	displayedProjects []*registry.Project
 
 	// Cached project states (to avoid inconsistent state during rendering)
 	projectStates map[string]registry.ProjectState
 
-	// Track last seen log message per service to fetch only new logs
-	lastLogMsg map[string]string
+	// Background project state probes and their latest results by path
+	states       *statecache.Cache
+	stateResults map[string]statecache.Result
+
+	// Streaming log follow for the current project
+	logStreams      map[string]bool    // Services with a stream (false once it ended)
+	logLastLine     map[string]string  // Last streamed line per service, to resume after a drop
+	logStreamCtx    context.Context    // Cancelled on project switch
+	logStreamCancel context.CancelFunc // Cancels logStreamCtx
+	logStreamGen    int                // Drops lines from streams of a previous project
 
 	// Track log activity timestamps per service for flow indicators
 	logActivity   map[string]time.Time
@@ -166,9 +224,8 @@ type servicesUpdatedMsg struct {
 	err      error
 }
 type healthEventMsg health.Event
-type logsUpdatedMsg struct {
-	logsByService map[string][]string
-	err           error
+type hookErrorMsg struct {
+	err error
 }
 type projectStartedMsg struct {
 	project string
@@ -185,6 +242,7 @@ type serviceOperationMsg struct {
 }
 type projectDeletedMsg struct {
 	project string
+	path    string
 }
 type projectHiddenMsg struct {
 	project string
@@ -204,7 +262,19 @@ func New(cfg *config.Config, reg *registry.Registry) *Model {
 	theme := GetTheme(cfg.UI.Theme)
 	styles := NewStyles(theme)
 
-	alertHistory := NewAlertHistory(100)
+	// Alert history survives restarts; a load error just starts it empty
+	histCfg := cfg.Notifications.History
+	if histCfg.MaxEntries <= 0 {
+		histCfg.MaxEntries = 100
+	}
+	alertHistory, _ := LoadAlertHistory(
+		AlertHistoryPath(),
+		histCfg.MaxEntries,
+		time.Duration(histCfg.RetentionDays)*24*time.Hour,
+		int64(histCfg.MaxFileKB)*1024,
+	)
+	// So does the metrics history
+	metricsStore, _ := metrics.Load(metrics.Path())
 
 	// Initialize spinner
 	s := spinner.New()
@@ -275,20 +345,30 @@ func New(cfg *config.Config, reg *registry.Registry) *Model {
 		toast:         NewToastManager(styles, 60),
 		alerts:        alertHistory,
 		alertsPanel:   NewAlertsPanel(styles, alertHistory, 80, 24),
+		metrics:       metricsStore,
+		metricsPanel:  NewMetricsPanel(styles, metricsStore, 80, 24),
 		settings:      NewSettingsPanel(cfg, styles, 80, 24),
 		helpPanel:     NewHelpPanel(styles, 80, 24),
 		splash:        NewSplashScreen(styles, 80, 24),
 		confirm:       NewConfirmDialog(styles),
-		health:        health.NewMonitor(2 * time.Second),
+		health:        newHealthMonitor(cfg.Health),
 		notifier:      notify.NewNotifier(cfg.Notifications.SystemEnabled),
+		notifyPolicy:  notify.NewPolicy(cfg.Notifications),
+		notifyHooks:   notify.NewHooks(cfg.Notifications),
+		restarts:      restart.NewSupervisor(cfg.Restart.Policies),
 		spinner:             s,
 		searchInput:         ti,
 		servicesTable:       t,
 		projectsList:        projectsList,
+		projectsDelegate:    projectsDelegate,
 		clients:             make(map[string]*compose.Client),
-		lastLogMsg:          make(map[string]string),
+		logStreams:          make(map[string]bool),
+		logLastLine:         make(map[string]string),
+		groupBusy:           make(map[string]bool),
 		logActivity:         make(map[string]time.Time),
 		projectStates:       make(map[string]registry.ProjectState),
+		states:              statecache.New(stateIntervals(cfg), nil),
+		stateResults:        make(map[string]statecache.Result),
 		serviceStates:       make(map[string]string),
 		stateChangeTime:     make(map[string]time.Time),
 		stateFlashIntensity: make(map[string]float64),
@@ -301,6 +381,9 @@ func New(cfg *config.Config, reg *registry.Registry) *Model {
 	m.splash.SetMessage("Starting devdash...")
 	m.splash.SetProgress(0.0)
 
+	// Context for the current project's log streams
+	m.logStreamCtx, m.logStreamCancel = context.WithCancel(context.Background())
+
 	// Set model reference on delegate (needed for spinner and loading state)
 	projectsDelegate.model = m
 
@@ -318,7 +401,7 @@ func (m *Model) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	}
 
 	// Skip if modals are open
-	if m.showSplash || m.showSettings || m.showHelp || m.alertsPanel.IsVisible() || m.confirm.IsVisible() {
+	if m.showSplash || m.showSettings || m.showHelp || m.alertsPanel.IsVisible() || m.metricsPanel.IsVisible() || m.confirm.IsVisible() {
 		return m, nil
 	}
 
@@ -388,12 +471,23 @@ func (m *Model) handleMouseEvent(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 
 // Init initializes the model.
 func (m *Model) Init() tea.Cmd {
+	var scan tea.Cmd
+	if m.config.Projects.AutoDiscover {
+		scan = m.startScan()
+	}
 	return tea.Batch(
+		scan,
 		m.tickCmd(),
+		m.runStatesCmd(),
+		m.waitForStateChanges(),
 		m.activityTickCmd(),
 		m.pollServicesCmd(),
+		m.backgroundPollCmd(),
 		m.splashTickCmd(),
 		m.spinner.Tick,
+		m.waitForFileChange(),
+		m.waitForDirChange(),
+		metricsSaveCmd(),
 	)
 }
 
@@ -401,7 +495,11 @@ type splashDoneMsg struct{}
 type splashTickMsg struct{}
 
 func (m *Model) tickCmd() tea.Cmd {
-	return tea.Tick(2*time.Second, func(t time.Time) tea.Msg {
+	interval := time.Duration(m.config.Polling.FocusedProject) * time.Second
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
@@ -430,57 +528,6 @@ func (m *Model) pollServicesCmd() tea.Cmd {
 	}
 }
 
-func (m *Model) pollLogsCmd() tea.Cmd {
-	// Capture current state for the closure
-	p := m.currentProject()
-	services := m.services
-
-	return func() tea.Msg {
-		if p == nil || len(services) == 0 {
-			return nil
-		}
-
-		// Create client in the goroutine
-		socketPath := filepath.Join(p.Path, ".devenv", "run", "pc.sock")
-		client := compose.NewClient(socketPath)
-		if err := client.Connect(); err != nil {
-			return nil
-		}
-
-		// Fetch logs for all services
-		logsByService := make(map[string][]string)
-		for _, svc := range services {
-			logs, err := client.GetLogs(svc.Name, 0, 100)
-			if err == nil && len(logs) > 0 {
-				logsByService[svc.Name] = logs
-			}
-		}
-
-		if len(logsByService) == 0 {
-			return nil
-		}
-		return logsUpdatedMsg{logsByService: logsByService}
-	}
-}
-
-// startProjectCmd starts an idle project using devenv up -d
-func (m *Model) startProjectCmd(p *registry.Project) tea.Cmd {
-	projectPath := p.Path
-	projectName := p.Name
-	return func() tea.Msg {
-		cmd := exec.Command("devenv", "up", "-d")
-		cmd.Dir = projectPath
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			// Enhance error message with command output
-			if len(output) > 0 {
-				err = fmt.Errorf("%v: %s", err, string(output))
-			}
-		}
-		return projectStartedMsg{project: projectName, err: err}
-	}
-}
-
 // startServiceCmd starts a specific service
 func (m *Model) startServiceCmd(client *compose.Client, serviceName string) tea.Cmd {
 	return func() tea.Msg {
@@ -519,29 +566,13 @@ func (m *Model) restartServiceCmd(client *compose.Client, serviceName string) te
 
 // stopProjectCmd stops a running project
 func (m *Model) stopProjectCmd(p *registry.Project) tea.Cmd {
-	projectPath := p.Path
+	projectDir := p.Dir()
+	projectKind := p.Kind
 	projectName := p.Name
+	socketPath := p.SocketPath()
 	return func() tea.Msg {
-		// First try to use the API if socket exists
-		socketPath := filepath.Join(projectPath, ".devenv", "run", "pc.sock")
-		client := compose.NewClient(socketPath)
-		if err := client.Connect(); err == nil {
-			err = client.ShutdownProject()
-			if err != nil {
-				err = fmt.Errorf("API shutdown failed: %v", err)
-			}
-			return projectStoppedMsg{project: projectName, err: err}
-		}
-		// Fallback to devenv down
-		cmd := exec.Command("devenv", "down")
-		cmd.Dir = projectPath
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			// Enhance error message with command output
-			if len(output) > 0 {
-				err = fmt.Errorf("%v: %s", err, string(output))
-			}
-		}
+		// Shutdown uses the API if the socket is reachable, otherwise devenv down
+		err := devenv.Shutdown(socketPath, projectDir, projectKind)
 		return projectStoppedMsg{project: projectName, err: err}
 	}
 }
@@ -564,10 +595,10 @@ func (m *Model) editConfigCmd() tea.Cmd {
 			return configEditedMsg{config: nil, err: err}
 		}
 
-		// Reload config after editing
+		// Reload config after editing; invalid configs are rejected
 		newConfig, loadErr := config.Load(configPath)
 		if loadErr != nil {
-			return configEditedMsg{config: nil, err: fmt.Errorf("failed to reload config: %v", loadErr)}
+			return configEditedMsg{config: nil, err: loadErr}
 		}
 
 		// Return the new config in the message
@@ -593,6 +624,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.tickCmd(),
 				m.activityTickCmd(),
 				m.pollServicesCmd(),
+				m.backgroundPollCmd(),
 			)
 		}
 
@@ -611,6 +643,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.tickCmd(),
 				m.activityTickCmd(),
 				m.pollServicesCmd(),
+				m.backgroundPollCmd(),
 			)
 		}
 		return m, cmd
@@ -632,6 +665,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.settings.SetSize(m.width, m.height)
 		m.helpPanel.SetSize(m.width, m.height)
 		m.alertsPanel.SetSize(m.width, m.height)
+		m.metricsPanel.SetSize(m.width, m.height)
 		m.toast = NewToastManager(m.styles, m.width-10)
 
 	case spinner.TickMsg:
@@ -663,10 +697,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.splash.Hide()
 
 	case tickMsg:
+		// Pick up changes another devdash or the CLI saved. On errors,
+		// such as a hand edit that doesn't parse, keep what we have.
+		_, _ = m.registry.Reload(registry.Path())
 		m.updateDisplayedProjects()
 		cmds = append(cmds, m.tickCmd())
 		cmds = append(cmds, m.pollServicesCmd())
 
+	case stateChangesMsg:
+		cmds = append(cmds, m.handleStateChanges(msg))
+
 	case activityTickMsg:
 		// Increment animation frame and update table if we have services
 		if len(m.services) > 0 {
@@ -699,19 +739,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Estimate stages based on operation type
 			var stage string
 
+			// Starts report real stages from devenv output (see startlog.go)
 			switch m.loadingOp {
-			case "Starting":
-				if elapsed < 2.5 {
-					stage = "Initializing environment..."
-					m.loadingProgress = elapsed / 2.5 * 0.3 // 0-30%
-				} else if elapsed < 5.5 {
-					stage = "Starting services..."
-					m.loadingProgress = 0.3 + ((elapsed-2.5)/3.0)*0.4 // 30-70%
-				} else {
-					stage = "Services online"
-					m.loadingProgress = 0.7 + ((elapsed-5.5)/2.5)*0.3 // 70-100%
-				}
-
 			case "Stopping":
 				if elapsed < 3.0 {
 					stage = "Stopping services..."
@@ -746,6 +775,20 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case backgroundPollMsg:
+		// Watch running projects we're not looking at for crashes
+		cmds = append(cmds, fetchBackgroundCmd(m.backgroundTargets()))
+		cmds = append(cmds, m.backgroundPollCmd())
+
+	case backgroundStatusMsg:
+		cmds = append(cmds, m.handleBackgroundStatus(msg)...)
+
+	case metricsSaveMsg:
+		cmds = append(cmds, m.saveMetricsCmd(), metricsSaveCmd())
+
+	case probeResultsMsg:
+		cmds = append(cmds, m.handleProbeResults(msg)...)
+
 	case servicesUpdatedMsg:
 		if msg.err == nil {
 			oldServices := m.services
@@ -760,11 +803,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateServicesTable()
 
 			// Update health monitor and check for state changes
+			projectName := ""
+			if p := m.currentProject(); p != nil {
+				projectName = p.Name
+			}
+			m.recordMetrics(projectName, msg.services)
 			for _, svc := range msg.services {
-				projectName := ""
-				if p := m.currentProject(); p != nil {
-					projectName = p.Name
-				}
 				event := m.health.UpdateService(projectName, svc.Name, svc.IsRunning, svc.ExitCode)
 				if event != nil {
 					cmds = append(cmds, func() tea.Msg {
@@ -815,117 +859,72 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Update table cursor
 			m.servicesTable.SetCursor(m.selectedService)
 
-			// Poll logs after services update
-			cmds = append(cmds, m.pollLogsCmd())
+			// Follow logs of any services that aren't streamed yet
+			cmds = append(cmds, m.startLogStreamsCmd())
+
+			// Check readiness of running services
+			if p := m.currentProject(); p != nil {
+				cmds = append(cmds, probeServicesCmd(m.config.Probes, p.Name, p.Path, m.services))
+			}
+
+			// Load the dependency graph if it's shown but not loaded yet
+			if p := m.currentProject(); p != nil && m.showGraph && (m.depGraph == nil || m.depGraphProject != p.Name) {
+				cmds = append(cmds, m.refreshDepGraphCmd())
+			}
 
 			_ = oldServices // Suppress unused warning
 		}
 
-	case logsUpdatedMsg:
-		if msg.err == nil && len(msg.logsByService) > 0 {
-			for service, logs := range msg.logsByService {
-				if len(logs) == 0 {
-					continue
-				}
+	case logStreamStartedMsg:
+		cmds = append(cmds, m.handleLogStreamStarted(msg))
 
-				// Find where new logs start (after last seen message)
-				lastSeen := m.lastLogMsg[service]
-				startIdx := 0
-				if lastSeen != "" {
-					for i, log := range logs {
-						if log == lastSeen {
-							startIdx = i + 1
-							break
-						}
-					}
-				}
+	case logLinesMsg:
+		cmds = append(cmds, m.handleLogLines(msg))
 
-				// Add new logs (logs come oldest-first from API)
-				newLogCount := 0
-				for i := startIdx; i < len(logs); i++ {
-					// Try to parse timestamp from log line, fall back to now
-					ts, ok := ParseLogTimestamp(logs[i])
-					if !ok {
-						ts = time.Now()
-					}
-					m.logView.AddEntry(LogEntry{
-						Timestamp: ts,
-						Service:   service,
-						Level:     DetectLogLevel(logs[i]),
-						Message:   logs[i],
-					})
-					newLogCount++
-				}
+	case logStreamEndedMsg:
+		m.handleLogStreamEnded(msg)
 
-				// Track last message seen
-				m.lastLogMsg[service] = logs[len(logs)-1]
+	case healthEventMsg:
+		cmds = append(cmds, m.handleHealthEvent(health.Event(msg))...)
 
-				// Record log activity timestamp if new logs were added
-				if newLogCount > 0 {
-					m.logActivity[service] = time.Now()
-				}
-			}
-		}
+	case restartDueMsg:
+		cmds = append(cmds, m.handleRestartDue(msg))
 
-	case healthEventMsg:
-		event := health.Event(msg)
-		// Add to alert history
-		m.alerts.Add(Alert{
-			Type:      alertTypeFromHealthEvent(event.Type),
-			Project:   event.Project,
-			Service:   event.Service,
-			Message:   event.Type.String(),
-			Timestamp: event.Timestamp,
-		})
-
-		// Show toast for crashes
-		if event.Type == health.EventServiceCrashed {
-			m.toast.Show(
-				fmt.Sprintf("%s crashed (exit %d)", event.Service, event.ExitCode),
-				ToastError,
-				5*time.Second,
-			)
-			cmds = append(cmds, m.toast.TickCmd())
+	case restartResultMsg:
+		cmds = append(cmds, m.handleRestartResult(msg)...)
 
-			// System notification
-			if m.notifier.IsEnabled() {
-				_ = m.notifier.ServiceCrashed(event.Project, event.Service, event.ExitCode)
-			}
-		} else if event.Type == health.EventServiceRecovered {
-			m.toast.Show(
-				fmt.Sprintf("%s recovered", event.Service),
-				ToastInfo,
-				3*time.Second,
-			)
-			cmds = append(cmds, m.toast.TickCmd())
-		}
+	case hookErrorMsg:
+		m.toast.Show(fmt.Sprintf("Notification hook failed: %v", msg.err), ToastWarn, 5*time.Second)
+		cmds = append(cmds, m.toast.TickCmd())
+
+	case startOutputMsg:
+		cmds = append(cmds, m.handleStartOutput(msg))
+
+	case startExitedMsg:
+		cmds = append(cmds, m.handleStartExited(msg))
+
+	case startReadyMsg:
+		cmds = append(cmds, m.handleStartReady(msg))
 
 	case projectStartedMsg:
-		// Clear loading state
-		m.loadingOp = ""
-		m.loadingProject = ""
-		m.loadingProgress = 0
-		m.loadingStage = ""
-		m.loadingStarted = time.Time{}
+		m.clearLoading()
 		if msg.err != nil {
 			m.toast.Show(fmt.Sprintf("Failed to start %s: %v", msg.project, msg.err), ToastError, 5*time.Second)
 		} else {
 			m.toast.Show(fmt.Sprintf("%s started", msg.project), ToastSuccess, 3*time.Second)
 			// Update project state cache to Running to prevent repeated start attempts
 			if p := m.currentProject(); p != nil && p.Name == msg.project {
-				m.projectStates[p.Path] = registry.StateRunning
+				m.assumeState(p.Path, registry.StateRunning)
 			}
 		}
+		if p := m.projectByName(msg.project); p != nil {
+			m.refreshState(p.Path)
+		}
 		cmds = append(cmds, m.toast.TickCmd())
 		cmds = append(cmds, m.pollServicesCmd())
 
 	case projectStoppedMsg:
-		// Clear loading state
-		m.loadingOp = ""
-		m.loadingProject = ""
-		m.loadingProgress = 0
-		m.loadingStage = ""
-		m.loadingStarted = time.Time{}
+		m.clearLoading()
 		if msg.err != nil {
 			m.toast.Show(fmt.Sprintf("Failed to stop %s: %v", msg.project, msg.err), ToastError, 5*time.Second)
 		} else {
@@ -933,17 +932,33 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Clear services and logs for stopped project
 			m.services = nil
 			m.logView.buffer.Clear()
+			m.resetLogStreams()
 			// Update project state cache to Idle to prevent state confusion
 			if p := m.currentProject(); p != nil && p.Name == msg.project {
-				m.projectStates[p.Path] = registry.StateIdle
+				m.assumeState(p.Path, registry.StateIdle)
 			}
 		}
+		if p := m.projectByName(msg.project); p != nil {
+			m.refreshState(p.Path)
+		}
 		cmds = append(cmds, m.toast.TickCmd())
 
+	case groupStepMsg:
+		cmds = append(cmds, m.handleGroupStep(msg)...)
+
+	case groupDoneMsg:
+		cmds = append(cmds, m.handleGroupDone(msg)...)
+
+	case depGraphMsg:
+		m.handleDepGraph(msg)
+
+	case depOperationMsg:
+		cmds = append(cmds, m.handleDepOperation(msg)...)
+
 	case serviceOperationMsg:
 		if msg.err != nil {
 			m.toast.Show(
-				fmt.Sprintf("Failed to %s %s: %v", msg.operation, msg.service, msg.err),
+				fmt.Sprintf("Failed to %s %s: %s", msg.operation, msg.service, describeComposeError(msg.err)),
 				ToastError,
 				5*time.Second,
 			)
@@ -959,29 +974,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case settingsSavedMsg:
 		m.toast.Show("Settings saved", ToastSuccess, 2*time.Second)
-		// Reload styles if theme changed
-		m.styles = NewStyles(GetTheme(m.config.UI.Theme))
-
-		// Update delegate with new styles
-		newDelegate := &projectDelegate{styles: m.styles, model: m}
-		m.projectsList.SetDelegate(newDelegate)
-
-		// Update table styles
-		tableStyle := table.DefaultStyles()
-		tableStyle.Header = tableStyle.Header.
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(m.styles.theme.Muted).
-			BorderBottom(true).
-			Bold(true).
-			Foreground(m.styles.theme.Primary)
-		tableStyle.Selected = tableStyle.Selected.
-			Foreground(m.styles.theme.Primary).
-			Bold(true)
-		// Keep default cell padding
-		m.servicesTable.SetStyles(tableStyle)
-
-		// Update spinner style
-		m.spinner.Style = lipgloss.NewStyle().Foreground(m.styles.theme.Primary)
+		m.applyConfig()
+		m.applyTheme()
 
 		// Restart tickers that were paused while settings modal was open
 		cmds = append(cmds, m.tickCmd())
@@ -993,7 +987,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.toast.Show(fmt.Sprintf("Failed to save settings: %v", msg.err), ToastError, 5*time.Second)
 		cmds = append(cmds, m.toast.TickCmd())
 
+	case registrySaveErrorMsg:
+		m.toast.Show(fmt.Sprintf("Failed to save registry: %v", msg.err), ToastError, 5*time.Second)
+		cmds = append(cmds, m.toast.TickCmd())
+
 	case projectDeletedMsg:
+		if !m.registry.RemoveProject(msg.path) {
+			break
+		}
+		cmds = append(cmds, m.saveRegistry())
 		m.toast.Show(fmt.Sprintf("%s removed from registry", msg.project), ToastSuccess, 2*time.Second)
 		m.updateDisplayedProjects()
 		cmds = append(cmds, m.toast.TickCmd())
@@ -1018,18 +1020,29 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case configEditedMsg:
 		if msg.err != nil {
-			m.toast.Show(fmt.Sprintf("Failed to edit config: %v", msg.err), ToastError, 5*time.Second)
+			m.showConfigError(msg.err, "Failed to edit config")
 		} else {
-			// Update config in model
-			m.config = msg.config
-			// Reload styles with new theme
-			m.styles = NewStyles(GetTheme(m.config.UI.Theme))
-			// Update settings panel with new config
-			m.settings = NewSettingsPanel(m.config, m.styles, m.width, m.height)
+			_, cmd := m.switchConfig(msg.config)
+			cmds = append(cmds, cmd)
 			m.toast.Show("Config reloaded", ToastSuccess, 2*time.Second)
 		}
 		cmds = append(cmds, m.toast.TickCmd())
 
+	case fileChangedMsg:
+		cmds = append(cmds, m.handleFileChange(msg.path), m.waitForFileChange())
+
+	case scanStartedMsg:
+		cmds = append(cmds, m.handleScanStarted(msg))
+
+	case scanFoundMsg:
+		cmds = append(cmds, m.handleScanFound(msg))
+
+	case scanDoneMsg:
+		cmds = append(cmds, m.handleScanDone(msg))
+
+	case dirChangedMsg:
+		cmds = append(cmds, m.handleDirChange(), m.waitForDirChange())
+
 	case ToastTickMsg:
 		var cmd tea.Cmd
 		m.toast, cmd = m.toast.Update(msg)
@@ -1044,6 +1057,172 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleHealthEvent notifies a health event and applies restart policies.
+// Transitions suppressed by flap detection only reach the restart
+// supervisor, so a crash-looping service keeps being restarted until its
+// policy gives up.
+func (m *Model) handleHealthEvent(event health.Event) []tea.Cmd {
+	var cmds []tea.Cmd
+	if !event.Suppressed {
+		cmds = m.notifyHealthEvent(event)
+	}
+
+	// Restart policies
+	switch {
+	case event.IsCrash():
+		cmds = append(cmds, m.superviseCrash(event)...)
+	case event.Type == health.EventServiceRecovered:
+		m.restarts.Recovered(event.Project, event.Service)
+	}
+	return cmds
+}
+
+// notifyHealthEvent records a health event in the alert history and
+// surfaces it as configured by the notification policy.
+func (m *Model) notifyHealthEvent(event health.Event) []tea.Cmd {
+	// Add to alert history
+	cmds := []tea.Cmd{m.addAlert(Alert{
+		Type:      alertTypeFromHealthEvent(event.Type),
+		Project:   event.Project,
+		Service:   event.Service,
+		Message:   event.Type.String(),
+		Timestamp: event.Timestamp,
+	})}
+
+	// Qualify background services with their project name
+	serviceLabel := event.Service
+	if p := m.currentProject(); p == nil || p.Name != event.Project {
+		serviceLabel = event.Project + "/" + event.Service
+	}
+
+	// Overrides and critical-only mode decide where the event surfaces
+	decision := m.notifyPolicy.Evaluate(event)
+
+	// Show toast for crashes
+	if event.Type == health.EventServiceCrashed {
+		if decision.TUI {
+			m.toast.Show(
+				fmt.Sprintf("%s crashed (exit %d)", serviceLabel, event.ExitCode),
+				ToastError,
+				5*time.Second,
+			)
+			cmds = append(cmds, m.toast.TickCmd())
+		}
+
+		// System notification
+		if decision.System {
+			_ = m.notifier.ServiceCrashed(event.Project, event.Service, event.ExitCode)
+		}
+	} else if event.Type == health.EventServiceRecovered {
+		if decision.TUI {
+			m.toast.Show(
+				fmt.Sprintf("%s recovered", serviceLabel),
+				ToastInfo,
+				3*time.Second,
+			)
+			cmds = append(cmds, m.toast.TickCmd())
+		}
+
+		if decision.System {
+			_ = m.notifier.ServiceRecovered(event.Project, event.Service)
+		}
+	} else if event.Type == health.EventServiceFlapping {
+		if decision.TUI {
+			m.toast.Show(
+				fmt.Sprintf("%s is flapping", serviceLabel),
+				ToastWarn,
+				5*time.Second,
+			)
+			cmds = append(cmds, m.toast.TickCmd())
+		}
+
+		if decision.System {
+			_ = m.notifier.ServiceFlapping(event.Project, event.Service)
+		}
+	} else if event.Type == health.EventServiceNotReady {
+		if decision.TUI {
+			m.toast.Show(
+				fmt.Sprintf("%s is no longer ready", serviceLabel),
+				ToastWarn,
+				5*time.Second,
+			)
+			cmds = append(cmds, m.toast.TickCmd())
+		}
+	}
+
+	// Webhooks and command hooks run off the UI goroutine
+	if decision.Hooks && m.notifyHooks.Len() > 0 {
+		cmds = append(cmds, sendHooksCmd(m.notifyHooks, event))
+	}
+	return cmds
+}
+
+// applyConfig syncs notifications and restart policies with the current config.
+func (m *Model) applyConfig() {
+	m.notifier.SetEnabled(m.config.Notifications.SystemEnabled)
+	m.notifyPolicy = notify.NewPolicy(m.config.Notifications)
+	m.notifyHooks = notify.NewHooks(m.config.Notifications)
+	m.restarts.SetPolicies(m.config.Restart.Policies)
+	m.health.SetFlapDetection(m.config.Health.FlapThreshold, time.Duration(m.config.Health.FlapWindow)*time.Second)
+	m.states.SetIntervals(stateIntervals(m.config))
+}
+
+// applyTheme rebuilds the styles for the configured theme and passes them
+// to the sidebar, services table and spinner.
+func (m *Model) applyTheme() {
+	m.styles = NewStyles(GetTheme(m.config.UI.Theme))
+
+	// Update delegate with new styles
+	m.projectsDelegate = &projectDelegate{styles: m.styles, model: m}
+	m.projectsList.SetDelegate(m.projectsDelegate)
+
+	// Update table styles
+	tableStyle := table.DefaultStyles()
+	tableStyle.Header = tableStyle.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(m.styles.theme.Muted).
+		BorderBottom(true).
+		Bold(true).
+		Foreground(m.styles.theme.Primary)
+	tableStyle.Selected = tableStyle.Selected.
+		Foreground(m.styles.theme.Primary).
+		Bold(true)
+	// Keep default cell padding
+	m.servicesTable.SetStyles(tableStyle)
+
+	// Update spinner style
+	m.spinner.Style = lipgloss.NewStyle().Foreground(m.styles.theme.Primary)
+}
+
+// newHealthMonitor creates the health monitor with flap detection from config.
+func newHealthMonitor(cfg config.HealthConfig) *health.Monitor {
+	monitor := health.NewMonitor(2 * time.Second)
+	monitor.SetFlapDetection(cfg.FlapThreshold, time.Duration(cfg.FlapWindow)*time.Second)
+	return monitor
+}
+
+// sendHooksCmd delivers an event to the notification hooks.
+func sendHooksCmd(hooks *notify.Hooks, event health.Event) tea.Cmd {
+	return func() tea.Msg {
+		if err := hooks.Send(context.Background(), event); err != nil {
+			return hookErrorMsg{err: err}
+		}
+		return nil
+	}
+}
+
+// describeComposeError shortens common process-compose failures for toasts.
+func describeComposeError(err error) string {
+	switch {
+	case errors.Is(err, compose.ErrNotConnected):
+		return "socket gone (project not running?)"
+	case errors.Is(err, compose.ErrProcessNotFound):
+		return "process not found"
+	default:
+		return err.Error()
+	}
+}
+
 func alertTypeFromHealthEvent(t health.EventType) AlertType {
 	switch t {
 	case health.EventServiceCrashed:
@@ -1054,6 +1233,12 @@ func alertTypeFromHealthEvent(t health.EventType) AlertType {
 		return AlertProjectStarted
 	case health.EventServiceStopped:
 		return AlertProjectStopped
+	case health.EventServiceFlapping:
+		return AlertServiceFlapping
+	case health.EventServiceReady:
+		return AlertServiceReady
+	case health.EventServiceNotReady:
+		return AlertServiceNotReady
 	default:
 		return AlertInfo
 	}
@@ -1069,9 +1254,7 @@ func (m *Model) getOrCreateClient(p *registry.Project) *compose.Client {
 	}
 
 	// Try to connect to process-compose socket
-	// devenv creates a symlink at .devenv/run pointing to /run/user/$UID/devenv-$HASH
-	socketPath := filepath.Join(p.Path, ".devenv", "run", "pc.sock")
-	client := compose.NewClient(socketPath)
+	client := compose.NewClient(p.SocketPath())
 	if err := client.Connect(); err != nil {
 		return nil
 	}
@@ -1108,6 +1291,12 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 
+	// Metrics modal - delegate to panel
+	if m.metricsPanel.IsVisible() {
+		_, cmd := m.metricsPanel.Update(msg)
+		return m, cmd
+	}
+
 	// Alerts modal - delegate to panel
 	if m.alertsPanel.IsVisible() {
 		_, cmd := m.alertsPanel.Update(msg)
@@ -1149,15 +1338,29 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Group action prefix: the key after g
+	if m.groupMode {
+		return m.handleGroupKey(msg)
+	}
+
+	// Tag input for the selected project
+	if m.tagEditMode {
+		return m.handleTagEditKey(msg)
+	}
+
 	// Global keys
 	switch {
 	case key.Matches(msg, m.keys.Quit):
+		m.saveMetrics()
+		_ = m.alerts.Flush()
 		return m, tea.Quit
 	case key.Matches(msg, m.keys.Shutdown):
 		// Shutdown all services
 		for _, client := range m.clients {
 			_ = client.ShutdownProject()
 		}
+		m.saveMetrics()
+		_ = m.alerts.Flush()
 		return m, tea.Quit
 	case key.Matches(msg, m.keys.Help):
 		m.helpPanel.Show()
@@ -1182,9 +1385,17 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.alertsPanel.Show()
 		}
 		return m, nil
+	case key.Matches(msg, m.keys.Metrics):
+		m.showMetrics()
+		return m, nil
 	case msg.String() == "p":
 		// Toggle between packages and services view
 		return m, m.togglePackagesView()
+	case key.Matches(msg, m.keys.CancelStart):
+		m.cancelStart()
+		return m, nil
+	case key.Matches(msg, m.keys.Back) && m.dismissStartLog():
+		return m, nil
 	case key.Matches(msg, m.keys.Back):
 		// Don't handle Esc globally if sidebar is filtering or logs has active search
 		if m.focused == PaneSidebar && m.projectFilterMode {
@@ -1243,6 +1454,11 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.updateDisplayedProjects()
 			}
 			return m, nil
+		case tea.KeySpace:
+			// Separates terms, e.g. "tag:backend api"
+			m.projectFilterInput += " "
+			m.updateDisplayedProjects()
+			return m, nil
 		case tea.KeyRunes:
 			m.projectFilterInput += string(msg.Runes)
 			m.updateDisplayedProjects()
@@ -1280,16 +1496,15 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				// Start idle project with devenv up -d
 				m.loadingOp = "Starting"
 				m.loadingProject = p.Name
-				m.loadingProgress = 0.0
-				m.loadingStage = "Initializing..."
 				m.loadingStarted = time.Now()
 
 				// Immediately update cache to prevent re-entry
-				m.projectStates[p.Path] = registry.StateRunning
+				m.assumeState(p.Path, registry.StateRunning)
+				m.restarts.ReleaseProject(p.Name)
 
 				m.toast.Show(fmt.Sprintf("Starting %s...", p.Name), ToastInfo, 3*time.Second)
-				return m, tea.Batch(m.startProjectCmd(p), m.toast.TickCmd(), m.progressTickCmd())
-			} else if state == registry.StateRunning || state == registry.StateDegraded {
+				return m, tea.Batch(m.beginStart(p), m.toast.TickCmd(), m.touchProject(p))
+			} else if state.IsActive() {
 				// Project already running - don't show progress, just inform
 				m.toast.Show("Project already running", ToastInfo, 2*time.Second)
 				return m, m.toast.TickCmd()
@@ -1309,7 +1524,7 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				state = p.DetectState()
 			}
 
-			if state == registry.StateRunning || state == registry.StateDegraded {
+			if state.IsActive() {
 				m.loadingOp = "Stopping"
 				m.loadingProject = p.Name
 				m.loadingProgress = 0.0
@@ -1317,7 +1532,9 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.loadingStarted = time.Now()
 
 				// Immediately update cache to prevent re-entry
-				m.projectStates[p.Path] = registry.StateIdle
+				m.assumeState(p.Path, registry.StateIdle)
+				// Services exiting during shutdown are not crashes to restart
+				m.restarts.HoldProject(p.Name)
 
 				m.toast.Show(fmt.Sprintf("Stopping %s...", p.Name), ToastInfo, 3*time.Second)
 				return m, tea.Batch(m.stopProjectCmd(p), m.toast.TickCmd(), m.progressTickCmd())
@@ -1327,6 +1544,22 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case key.Matches(msg, m.keys.Group):
+		// g - group action on the selected project's group (s/x/r follows)
+		return m, m.beginGroupMode()
+	case key.Matches(msg, m.keys.Favorite):
+		// f - pin/unpin project
+		return m, m.toggleFavorite()
+	case key.Matches(msg, m.keys.Tags):
+		// t - edit project tags
+		m.beginTagEdit()
+		return m, nil
+	case key.Matches(msg, m.keys.GroupBy):
+		// v - cycle sidebar grouping
+		return m, m.cycleSidebarGroupBy()
+	case key.Matches(msg, m.keys.Sort):
+		// o - cycle sidebar sort order
+		return m, m.cycleSidebarSort()
 	case key.Matches(msg, m.keys.Delete):
 		// d - delete project (with confirmation)
 		if p := m.currentProject(); p != nil {
@@ -1335,14 +1568,8 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.confirm.Show(
 				fmt.Sprintf("Remove %s from registry?", projectName),
 				func() tea.Msg {
-					// Delete the project
-					if m.registry.RemoveProject(projectPath) {
-						// Save registry
-						regPath := registry.Path()
-						_ = registry.Save(regPath, m.registry)
-						return projectDeletedMsg{project: projectName}
-					}
-					return nil
+					// Delete the project in Update, which owns the registry
+					return projectDeletedMsg{project: projectName, path: projectPath}
 				},
 				func() tea.Msg {
 					// Cancel - do nothing
@@ -1357,19 +1584,17 @@ func (m *Model) handleSidebarKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			projectPath := p.Path
 			projectName := p.Name
 			if m.registry.ToggleHidden(projectPath) {
-				// Save registry
-				regPath := registry.Path()
-				_ = registry.Save(regPath, m.registry)
-				return m, func() tea.Msg {
-					return projectHiddenMsg{project: projectName, hidden: p.Hidden}
-				}
+				hidden := p.Hidden
+				return m, tea.Batch(m.saveRegistry(), func() tea.Msg {
+					return projectHiddenMsg{project: projectName, hidden: hidden}
+				})
 			}
 		}
 		return m, nil
 	case key.Matches(msg, m.keys.Repair):
 		// c - repair stale project
 		if p := m.currentProject(); p != nil {
-			state := p.DetectState()
+			state := m.projectStates[p.Path]
 			if state == registry.StateStale {
 				projectName := p.Name
 				m.confirm.Show(
@@ -1443,6 +1668,8 @@ func (m *Model) handleServicesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if p := m.currentProject(); p != nil {
 			if client := m.getOrCreateClient(p); client != nil {
 				if m.selectedService < len(m.services) {
+					// Don't auto-restart a service the user stopped
+					m.restarts.Hold(p.Name, m.services[m.selectedService].Name)
 					return m, m.stopServiceCmd(client, m.services[m.selectedService].Name)
 				}
 			}
@@ -1458,6 +1685,29 @@ func (m *Model) handleServicesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 		return m, nil
+	case key.Matches(msg, m.keys.Graph):
+		// D - toggle dependency graph
+		return m, m.toggleDepGraph()
+	case key.Matches(msg, m.keys.StartWithDeps):
+		// A - start service with its dependencies
+		if p := m.currentProject(); p != nil {
+			if client := m.getOrCreateClient(p); client != nil {
+				if m.selectedService < len(m.services) {
+					return m, startWithDepsCmd(client, m.services[m.selectedService].Name)
+				}
+			}
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.StopDependents):
+		// X - stop service and its dependents, after confirmation
+		if p := m.currentProject(); p != nil {
+			if client := m.getOrCreateClient(p); client != nil {
+				if m.selectedService < len(m.services) {
+					m.confirmStopDependents(client, m.services[m.selectedService].Name)
+				}
+			}
+		}
+		return m, nil
 	}
 	return m, nil
 }
@@ -1537,57 +1787,43 @@ func (m *Model) togglePackagesView() tea.Cmd {
 	return nil
 }
 
-// updateDisplayedProjects rebuilds the sidebar display order (active first, then idle, alphabetical within each).
-// It also caches the detected states to avoid inconsistent state during rendering.
+// updateDisplayedProjects rebuilds the sidebar: groups, favorites, then the
+// projects matching the filter in the configured grouping and sort order.
+// It also caches the probed states to avoid inconsistent state during
+// rendering, and hands the projects to the state cache to probe.
 func (m *Model) updateDisplayedProjects() {
-	var active, idle []*registry.Project
+	m.states.SetProjects(m.registry.Projects)
+	states := make(map[string]registry.ProjectState, len(m.registry.Projects))
 	for _, p := range m.registry.Projects {
-		state := p.DetectState()
-		// Cache the state so we use consistent state during rendering
-		m.projectStates[p.Path] = state
-
-		// Apply filter if active
-		if m.projectFilterInput != "" {
-			if !strings.Contains(strings.ToLower(p.Name), strings.ToLower(m.projectFilterInput)) {
-				continue // Skip projects that don't match filter
-			}
+		if state, ok := m.stateOf(p); ok {
+			states[p.Path] = state
+		} else if state, ok := m.projectStates[p.Path]; ok {
+			states[p.Path] = state // Assumed, or not probed yet
 		}
+	}
+	// Cache the states so we use consistent state during rendering
+	for path, state := range states {
+		m.projectStates[path] = state
+	}
 
-		if state == registry.StateRunning || state == registry.StateDegraded {
-			active = append(active, p)
-		} else {
-			idle = append(idle, p)
+	var matching []*registry.Project
+	for _, p := range m.registry.Projects {
+		if matchesFilter(p, m.projectFilterInput) {
+			matching = append(matching, p)
 		}
 	}
+	sections := m.sidebarSections(matching)
 
-	// Sort each group alphabetically
-	sort.Slice(active, func(i, j int) bool {
-		return active[i].Name < active[j].Name
-	})
-	sort.Slice(idle, func(i, j int) bool {
-		return idle[i].Name < idle[j].Name
-	})
-
-	m.displayedProjects = append(active, idle...)
-
-	// Update list items with section headers
+	// Update list items with section headers, groups first (hidden while filtering)
+	m.displayedProjects = nil
 	var items []list.Item
-
-	// Add ACTIVE section
-	if len(active) > 0 {
-		items = append(items, sectionHeaderItem{title: "ACTIVE"})
-		for _, p := range active {
-			items = append(items, projectListItem{
-				project: p,
-				state:   m.projectStates[p.Path],
-			})
-		}
+	if m.projectFilterInput == "" {
+		items = append(items, m.groupItems()...)
 	}
-
-	// Add IDLE section
-	if len(idle) > 0 {
-		items = append(items, sectionHeaderItem{title: "IDLE"})
-		for _, p := range idle {
+	for _, section := range sections {
+		items = append(items, sectionHeaderItem{title: section.title})
+		for _, p := range section.projects {
+			m.displayedProjects = append(m.displayedProjects, p)
 			items = append(items, projectListItem{
 				project: p,
 				state:   m.projectStates[p.Path],
@@ -1613,10 +1849,10 @@ func (m *Model) updateDisplayedProjects() {
 
 	// Ensure we're not on a header (this shouldn't happen, but safety check)
 	if len(m.projectsList.Items()) > 0 {
-		if _, isHeader := m.projectsList.SelectedItem().(sectionHeaderItem); isHeader {
+		if !isProjectItem(m.projectsList.SelectedItem()) {
 			// Move to first non-header item
 			for i, item := range m.projectsList.Items() {
-				if _, isHeader := item.(sectionHeaderItem); !isHeader {
+				if isProjectItem(item) {
 					m.projectsList.Select(i)
 					m.selectedProject = m.listIndexToProjectIndex(i)
 					break
@@ -1636,7 +1872,7 @@ func (m *Model) projectIndexToListIndex(projectIndex int) int {
 	projectsSeen := 0
 
 	for _, item := range m.projectsList.Items() {
-		if _, isHeader := item.(sectionHeaderItem); isHeader {
+		if !isProjectItem(item) {
 			listIndex++
 			continue
 		}
@@ -1654,7 +1890,7 @@ func (m *Model) projectIndexToListIndex(projectIndex int) int {
 func (m *Model) listIndexToProjectIndex(listIndex int) int {
 	projectIndex := 0
 	for i := 0; i < listIndex && i < len(m.projectsList.Items()); i++ {
-		if _, isHeader := m.projectsList.Items()[i].(sectionHeaderItem); !isHeader {
+		if isProjectItem(m.projectsList.Items()[i]) {
 			projectIndex++
 		}
 	}
@@ -1707,7 +1943,10 @@ func (m *Model) cycleFocusReverse() {
 func (m *Model) switchToCurrentProject() {
 	m.selectedService = 0
 	m.services = nil // Clear services, will be repopulated
-	m.lastLogMsg = make(map[string]string)           // Reset log tracking for new project
+	m.depGraph = nil // Reloaded on the next poll if the graph is shown
+	m.depGraphProject = ""
+	m.depGraphErr = nil
+	m.resetLogStreams()                              // Stop following the previous project's logs
 	m.logActivity = make(map[string]time.Time)       // Reset log activity tracking
 	m.serviceStates = make(map[string]string)        // Reset state tracking
 	m.stateChangeTime = make(map[string]time.Time)   // Reset state change times
@@ -1741,7 +1980,7 @@ func (m *Model) moveUp() tea.Cmd {
 
 			// Skip over headers
 			for m.projectsList.Index() > 0 {
-				if _, isHeader := m.projectsList.SelectedItem().(sectionHeaderItem); !isHeader {
+				if isProjectItem(m.projectsList.SelectedItem()) {
 					break
 				}
 				m.projectsList.CursorUp()
@@ -1758,7 +1997,9 @@ func (m *Model) moveUp() tea.Cmd {
 			}
 		}
 	case PaneServices:
-		if m.selectedService > 0 {
+		if m.showGraph {
+			m.moveGraphSelection(-1)
+		} else if m.selectedService > 0 {
 			m.selectedService--
 			m.servicesTable.SetCursor(m.selectedService)
 		}
@@ -1778,7 +2019,7 @@ func (m *Model) moveDown() tea.Cmd {
 
 			// Skip over headers
 			for m.projectsList.Index() < maxIdx {
-				if _, isHeader := m.projectsList.SelectedItem().(sectionHeaderItem); !isHeader {
+				if isProjectItem(m.projectsList.SelectedItem()) {
 					break
 				}
 				m.projectsList.CursorDown()
@@ -1795,7 +2036,9 @@ func (m *Model) moveDown() tea.Cmd {
 			}
 		}
 	case PaneServices:
-		if m.selectedService < len(m.services)-1 {
+		if m.showGraph {
+			m.moveGraphSelection(1)
+		} else if m.selectedService < len(m.services)-1 {
 			m.selectedService++
 			m.servicesTable.SetCursor(m.selectedService)
 		}
@@ -1868,6 +2111,21 @@ func (m *Model) View() string {
 		)
 	}
 
+	// Metrics modal overlay (centered on screen)
+	if m.metricsPanel.IsVisible() {
+		metricsModal := m.metricsPanel.View()
+		// Place modal centered on a dark background
+		main = lipgloss.Place(
+			m.width,
+			m.height,
+			lipgloss.Center,
+			lipgloss.Center,
+			metricsModal,
+			lipgloss.WithWhitespaceChars(" "),
+			lipgloss.WithWhitespaceForeground(lipgloss.Color("#1a1a1a")),
+		)
+	}
+
 	// Confirm dialog overlay (centered, transparent background)
 	if m.confirm.IsVisible() {
 		confirmModal := m.confirm.View()
@@ -1935,12 +2193,22 @@ func (m *Model) renderBody() string {
 
 func (m *Model) renderSidebar(width, height int) string {
 	// Title line
-	titleLine := m.renderSectionTitle("PROJECTS", m.focused == PaneSidebar, width-4)
+	title := "PROJECTS"
+	if m.scanning {
+		title += " (SCANNING)"
+	}
+	titleLine := m.renderSectionTitle(title, m.focused == PaneSidebar, width-4)
 
 	// Custom filter UI
 	var filterLine string
 	listHeight := height  // Use full height - let border handle spacing
-	if m.projectFilterMode || m.projectFilterInput != "" {
+	if m.tagEditMode {
+		promptStyle := lipgloss.NewStyle().
+			Foreground(m.styles.theme.Primary).
+			Bold(true)
+		filterLine = promptStyle.Render("Tags: ") + lipgloss.NewStyle().Foreground(m.styles.theme.Primary).Render(m.tagInput+"_")
+		listHeight = height - 1
+	} else if m.projectFilterMode || m.projectFilterInput != "" {
 		filterPromptStyle := lipgloss.NewStyle().
 			Foreground(m.styles.theme.Primary).
 			Bold(true)
@@ -1997,6 +2265,8 @@ func (m *Model) renderProjectItem(idx int, p *registry.Project) string {
 			glyph = m.styles.StatusRunning.Render("●")
 		case registry.StateDegraded:
 			glyph = m.styles.StatusDegraded.Render("◐")
+		case registry.StateStarting:
+			glyph = m.styles.StatusDegraded.Render("◌")
 		case registry.StateIdle:
 			glyph = m.styles.StatusIdle.Render("○")
 		case registry.StateStale:
@@ -2076,6 +2346,9 @@ func (m *Model) renderServices(width, height int) string {
 	if p := m.currentProject(); p != nil {
 		// Title with focus indicator and toggle hint on narrow terminals
 		title := fmt.Sprintf("SERVICES [%s]", p.Name)
+		if m.showGraph {
+			title += " [graph]"
+		}
 		if !m.shouldShowBothPanes() {
 			title += " [p:packages]"
 		}
@@ -2096,6 +2369,8 @@ func (m *Model) renderServices(width, height int) string {
 				emptyStateHeight,
 			)
 			content += emptyContent
+		} else if m.showGraph {
+			content += m.renderDepGraph(width-6, height-6)
 		} else {
 			// Update table focus based on pane focus
 			m.servicesTable.SetHeight(height - 6)
@@ -2162,6 +2437,11 @@ func (m *Model) renderPackages(width, height int) string {
 }
 
 func (m *Model) renderLogs(width, height int) string {
+	// devenv up output takes over the pane while the project starts
+	if m.showingStartLog() {
+		return m.renderStartLog(width, height)
+	}
+
 	m.logView.SetSize(width-4, height-4)
 
 	// Title with focus indicator
@@ -2260,6 +2540,11 @@ func (m *Model) highlightKeys(text string) string {
 
 // updateServicesTable updates the table rows from the services list.
 func (m *Model) updateServicesTable() {
+	projectName := ""
+	if p := m.currentProject(); p != nil {
+		projectName = p.Name
+	}
+
 	rows := make([]table.Row, len(m.services))
 	for i, svc := range m.services {
 		var status string
@@ -2292,8 +2577,19 @@ func (m *Model) updateServicesTable() {
 			uptimeOrExit = fmt.Sprintf("exit %d", svc.ExitCode)
 		}
 
-		// Apply flash effect to status if state recently changed
+		// Apply flash effect to status if state recently changed; a
+		// flapping or not-yet-ready service gets a steady warning instead
 		styledStatus := m.applyFlashEffect(status, svc.Name, svc.IsRunning)
+		if m.health.IsFlapping(projectName, svc.Name) {
+			styledStatus = lipgloss.NewStyle().
+				Foreground(m.styles.theme.Warning).
+				Bold(true).
+				Render("Flapping")
+		} else if svc.IsRunning && !m.serviceReady(projectName, svc.Name) {
+			styledStatus = lipgloss.NewStyle().
+				Foreground(m.styles.theme.Warning).
+				Render("Starting")
+		}
 
 		// Get activity indicator (animated spinner if logs received recently)
 		activity := m.getActivityIndicator(svc.Name)
@@ -2603,16 +2899,24 @@ func (m *Model) renderFooter() string {
 		// Check if current project is stale to show repair option
 		isStale := false
 		if p := m.currentProject(); p != nil {
-			state := p.DetectState()
+			state := m.projectStates[p.Path]
 			isStale = (state == registry.StateStale)
 		}
 		if isStale {
 			help = "[↑/↓] Navigate  [Tab] Switch Pane  [c] Repair  [d] Delete  [Ctrl+h] Hide  [?] Help"
 		} else {
-			help = "[↑/↓] Navigate  [Tab] Switch Pane  [/] Search  [Enter] Select  [s] Start  [x] Stop  [d] Delete  [Ctrl+h] Hide  [?] Help"
+			help = "[↑/↓] Navigate  [Tab] Switch Pane  [/] Search  [Enter] Select  [s] Start  [x] Stop  [g] Group  [d] Delete  [Ctrl+h] Hide  [?] Help"
+		}
+		if m.tagEditMode {
+			help = "[Type] Tags, comma separated  [Enter] Save  [Esc] Cancel"
+		}
+		if m.groupMode {
+			if g := m.currentGroup(); g != nil {
+				help = fmt.Sprintf("Group %s:  [s] Start  [x] Stop  [r] Restart  [Esc] Cancel", g.Name)
+			}
 		}
 	case PaneServices:
-		help = "[↑/↓] Navigate  [Tab] Switch Pane  [Enter] Filter  [s] Start  [x] Stop  [r] Restart  [?] Help"
+		help = "[↑/↓] Navigate  [Tab] Switch Pane  [Enter] Filter  [s] Start  [x] Stop  [r] Restart  [D] Graph  [?] Help"
 	case PaneLogs:
 		if m.searchMode {
 			help = "[Type] Search  [Enter] Confirm  [Esc] Cancel"
@@ -2720,6 +3024,11 @@ func (d *projectDelegate) Render(w io.Writer, m list.Model, index int, item list
 		return
 	}
 
+	if groupItem, ok := item.(groupListItem); ok {
+		d.renderGroup(w, groupItem)
+		return
+	}
+
 	projItem, ok := item.(projectListItem)
 	if !ok {
 		return
@@ -2738,6 +3047,8 @@ func (d *projectDelegate) Render(w io.Writer, m list.Model, index int, item list
 			glyph = d.styles.StatusRunning.Render("●")
 		case registry.StateDegraded:
 			glyph = d.styles.StatusDegraded.Render("◐")
+		case registry.StateStarting:
+			glyph = d.styles.StatusDegraded.Render("◌")
 		case registry.StateIdle:
 			glyph = d.styles.StatusIdle.Render("○")
 		case registry.StateStale:
@@ -11,6 +11,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/compose/composetest"
 	"github.com/infktd/devdash/internal/config"
 	"github.com/infktd/devdash/internal/registry"
 )
@@ -397,7 +398,7 @@ func TestSwitchToCurrentProjectResetsState(t *testing.T) {
 	// Set up some state
 	m.services = []compose.ProcessStatus{{Name: "test"}}
 	m.selectedService = 5
-	m.lastLogMsg = map[string]string{"svc": "msg"}
+	m.logStreams = map[string]bool{"svc": true}
 	m.serviceStates = map[string]string{"svc": "running"}
 	m.cpuHistory = map[string][]float64{"svc": {1.0, 2.0}}
 
@@ -411,8 +412,8 @@ func TestSwitchToCurrentProjectResetsState(t *testing.T) {
 	if m.selectedService != 0 {
 		t.Error("selectedService should be reset to 0")
 	}
-	if len(m.lastLogMsg) != 0 {
-		t.Error("lastLogMsg should be cleared")
+	if len(m.logStreams) != 0 {
+		t.Error("logStreams should be cleared")
 	}
 	if len(m.serviceStates) != 0 {
 		t.Error("serviceStates should be cleared")
@@ -862,3 +863,183 @@ func TestProjectDelegateRender(t *testing.T) {
 	_ = delegate
 	_ = item
 }
+
+func TestServicesTableShowsFlapping(t *testing.T) {
+	m, current, _ := newBackgroundTestModel(t)
+	m.health.SetFlapDetection(2, time.Minute)
+	m.services = []compose.ProcessStatus{{Name: "worker", IsRunning: true}}
+
+	m.health.UpdateService(current.Name, "worker", true, 0)
+	m.health.UpdateService(current.Name, "worker", false, 1)
+	m.health.UpdateService(current.Name, "worker", true, 0)
+
+	m.updateServicesTable()
+	if status := m.servicesTable.Rows()[0][0]; !strings.Contains(status, "Flapping") {
+		t.Errorf("status cell = %q, want flapping indicator", status)
+	}
+}
+
+func TestDescribeComposeError(t *testing.T) {
+	notConnected := compose.NewClient("/nonexistent/socket.sock").Connect()
+	if got := describeComposeError(notConnected); got != "socket gone (project not running?)" {
+		t.Errorf("describeComposeError(not connected) = %q", got)
+	}
+
+	notFound := &compose.APIError{Method: "POST", Path: "/process/x/start", StatusCode: 400, Message: "no such process"}
+	if got := describeComposeError(notFound); got != "process not found" {
+		t.Errorf("describeComposeError(not found) = %q", got)
+	}
+
+	other := fmt.Errorf("boom")
+	if got := describeComposeError(other); got != "boom" {
+		t.Errorf("describeComposeError(other) = %q", got)
+	}
+}
+
+// newFakeProject serves a fake process-compose API from a project directory
+// and returns a model with that project selected.
+func newFakeProject(t *testing.T) (*Model, *registry.Project, *composetest.Server) {
+	t.Helper()
+	dir, err := os.MkdirTemp("/tmp", "devdash-ui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	os.MkdirAll(filepath.Join(dir, ".devenv", "run"), 0755)
+
+	reg := &registry.Registry{}
+	p := reg.AddProject(dir)
+	srv, err := composetest.New(p.SocketPath())
+	if err != nil {
+		t.Fatalf("composetest.New() error: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+
+	m := New(config.Default(), reg)
+	m.notifier.SetEnabled(false)
+	return m, p, srv
+}
+
+// poll runs one foreground poll of the current project through Update.
+func poll(t *testing.T, m *Model) {
+	t.Helper()
+	_, cmd := m.Update(pollServicesMsg{})
+	if cmd == nil {
+		t.Fatal("poll should return a command")
+	}
+	m.Update(cmd())
+}
+
+func TestPollAgainstFakeServer(t *testing.T) {
+	m, p, srv := newFakeProject(t)
+	srv.AddProcess("web")
+	srv.AddProcess("worker")
+
+	poll(t, m)
+	if len(m.services) != 2 || m.services[1].Name != "worker" || !m.services[1].IsRunning {
+		t.Fatalf("unexpected services after poll: %+v", m.services)
+	}
+
+	srv.Crash("worker", 2)
+	poll(t, m)
+	state, ok := m.health.GetState(p.Name, "worker")
+	if !ok || state.Running || state.ExitCode != 2 {
+		t.Errorf("health monitor should see the crash, got %+v", state)
+	}
+}
+
+func TestPollImportedProject(t *testing.T) {
+	root, err := os.MkdirTemp("/tmp", "devdash-ui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	sub := filepath.Join(root, "services", "api")
+	os.MkdirAll(sub, 0755)
+	os.MkdirAll(filepath.Join(root, ".devenv", "run"), 0755)
+
+	// The socket of an imported project lives under the importing root
+	reg := &registry.Registry{}
+	p := reg.AddProject(sub)
+	p.Kind = registry.KindImported
+	p.Root = root
+	srv, err := composetest.New(filepath.Join(root, ".devenv", "run", "pc.sock"))
+	if err != nil {
+		t.Fatalf("composetest.New() error: %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	srv.AddProcess("api")
+
+	m := New(config.Default(), reg)
+	m.notifier.SetEnabled(false)
+	poll(t, m)
+	if len(m.services) != 1 || m.services[0].Name != "api" {
+		t.Fatalf("services pane should list the imported project's services, got %+v", m.services)
+	}
+	if m.getOrCreateClient(p) == nil {
+		t.Error("service actions should reach the imported project's socket")
+	}
+}
+
+func TestTickReloadsRegistry(t *testing.T) {
+	path := registry.Path()
+	reg, err := registry.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	m := New(config.Default(), reg)
+
+	// Another devdash adds a project
+	time.Sleep(10 * time.Millisecond)
+	other, _ := registry.Load(path)
+	added := filepath.Join(t.TempDir(), "other")
+	other.AddProject(added)
+	if err := registry.Save(path, other); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	m.Update(tickMsg(time.Now()))
+	found := false
+	for _, p := range m.displayedProjects {
+		if p.Path == added {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("the tick should reload projects saved by another instance")
+	}
+}
+
+func TestDeleteProjectSaves(t *testing.T) {
+	reg := &registry.Registry{}
+	p := reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+
+	m.Update(projectDeletedMsg{project: p.Name, path: p.Path})
+	if reg.FindByPath(p.Path) != nil {
+		t.Fatal("project should be removed")
+	}
+	saved, _ := registry.Load(registry.Path())
+	if saved.FindByPath(p.Path) != nil {
+		t.Error("the removal should be saved")
+	}
+}
+
+func TestConfigEditRejectsInvalidConfig(t *testing.T) {
+	m := New(config.Default(), &registry.Registry{})
+	previous := m.config
+
+	err := &config.ValidationError{Problems: []config.Problem{
+		{Path: "ui.theme", Line: 3, Column: 10, Message: `unknown value "nordd"`},
+		{Path: "polling.focused_project", Line: 5, Column: 20, Message: "must be at least 1, got 0"},
+	}}
+	m.Update(configEditedMsg{err: err})
+
+	if m.config != previous {
+		t.Error("an invalid config should keep the previous one")
+	}
+	msg := m.toast.current.Message
+	if !strings.Contains(msg, "line 3, column 10: ui.theme") || !strings.Contains(msg, "+1 more") {
+		t.Errorf("toast = %q, want the first problem and a count", msg)
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/probe"
+)
+
+// probeResultsMsg delivers readiness probe results for one project, keyed by
+// service name. A nil error means the service is ready.
+type probeResultsMsg struct {
+	project string
+	results map[string]error
+}
+
+// probeServicesCmd runs the configured readiness probes for the running
+// services of a project concurrently. It returns nil when no running service
+// has a probe.
+func probeServicesCmd(probes []config.ProbeConfig, project, dir string, services []compose.ProcessStatus) tea.Cmd {
+	checks := make(map[string]config.ProbeConfig)
+	for _, svc := range services {
+		if !svc.IsRunning {
+			continue
+		}
+		if cfg := probe.For(probes, project, svc.Name); cfg != nil {
+			checks[svc.Name] = *cfg
+		}
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		results := make(map[string]error, len(checks))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for service, cfg := range checks {
+			wg.Add(1)
+			go func(service string, cfg config.ProbeConfig) {
+				defer wg.Done()
+				err := probe.Run(context.Background(), cfg, dir)
+				mu.Lock()
+				results[service] = err
+				mu.Unlock()
+			}(service, cfg)
+		}
+		wg.Wait()
+		return probeResultsMsg{project: project, results: results}
+	}
+}
+
+// handleProbeResults records probe results in the health monitor and returns
+// commands for any readiness changes.
+func (m *Model) handleProbeResults(msg probeResultsMsg) []tea.Cmd {
+	var cmds []tea.Cmd
+	for service, err := range msg.results {
+		// The service may have stopped while its probe was running
+		if state, ok := m.health.GetState(msg.project, service); !ok || !state.Running {
+			continue
+		}
+		event := m.health.UpdateReadiness(msg.project, service, err == nil)
+		if event != nil {
+			ev := *event
+			cmds = append(cmds, func() tea.Msg {
+				return healthEventMsg(ev)
+			})
+		}
+	}
+	if p := m.currentProject(); p != nil && p.Name == msg.project {
+		m.updateServicesTable()
+	}
+	return cmds
+}
+
+// serviceReady reports whether a service has passed its readiness probe.
+// Services without a probe are ready as soon as they run.
+func (m *Model) serviceReady(project, service string) bool {
+	if probe.For(m.config.Probes, project, service) == nil {
+		return true
+	}
+	ready, _ := m.health.Readiness(project, service)
+	return ready
+}
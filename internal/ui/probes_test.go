@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+)
+
+func TestProbeServicesCmdRunsMatchingProbes(t *testing.T) {
+	probes := []config.ProbeConfig{
+		{Service: "web", Command: "true"},
+		{Service: "db", Command: "false"},
+	}
+	services := []compose.ProcessStatus{
+		{Name: "web", IsRunning: true},
+		{Name: "db", IsRunning: true},
+		{Name: "worker", IsRunning: true},
+		{Name: "cache", IsRunning: false},
+	}
+
+	cmd := probeServicesCmd(probes, "api", t.TempDir(), services)
+	if cmd == nil {
+		t.Fatal("expected a probe command")
+	}
+	msg, ok := cmd().(probeResultsMsg)
+	if !ok {
+		t.Fatalf("expected probeResultsMsg, got %T", cmd())
+	}
+	if msg.project != "api" || len(msg.results) != 2 {
+		t.Fatalf("unexpected results: %+v", msg)
+	}
+	if msg.results["web"] != nil {
+		t.Errorf("web probe should pass, got %v", msg.results["web"])
+	}
+	if msg.results["db"] == nil {
+		t.Error("db probe should fail")
+	}
+}
+
+func TestProbeServicesCmdNilWithoutProbes(t *testing.T) {
+	services := []compose.ProcessStatus{{Name: "web", IsRunning: true}}
+	if cmd := probeServicesCmd(nil, "api", t.TempDir(), services); cmd != nil {
+		t.Error("expected nil command when no probes are configured")
+	}
+}
+
+func TestHandleProbeResultsTracksReadiness(t *testing.T) {
+	m, p, _ := newBackgroundTestModel(t)
+	m.config.Probes = []config.ProbeConfig{{Service: "web", TCP: "localhost:1"}}
+	m.health.UpdateService(p.Name, "web", true, 0)
+
+	if m.serviceReady(p.Name, "web") {
+		t.Error("probed service should not be ready before its first result")
+	}
+	if !m.serviceReady(p.Name, "worker") {
+		t.Error("service without a probe should be ready")
+	}
+
+	if cmds := m.handleProbeResults(probeResultsMsg{project: p.Name, results: map[string]error{"web": nil}}); len(cmds) != 1 {
+		t.Fatalf("expected a ready event, got %d cmds", len(cmds))
+	}
+	if !m.serviceReady(p.Name, "web") {
+		t.Error("service should be ready after passing its probe")
+	}
+
+	cmds := m.handleProbeResults(probeResultsMsg{project: p.Name, results: map[string]error{"web": errors.New("refused")}})
+	if len(cmds) != 1 {
+		t.Fatalf("expected a not-ready event, got %d cmds", len(cmds))
+	}
+	if msg, ok := cmds[0]().(healthEventMsg); !ok || alertTypeFromHealthEvent(msg.Type) != AlertServiceNotReady {
+		t.Errorf("unexpected event %+v", msg)
+	}
+}
+
+func TestHandleProbeResultsIgnoresStoppedServices(t *testing.T) {
+	m, p, _ := newBackgroundTestModel(t)
+	m.health.UpdateService(p.Name, "web", false, 1)
+
+	if cmds := m.handleProbeResults(probeResultsMsg{project: p.Name, results: map[string]error{"web": nil}}); len(cmds) != 0 {
+		t.Errorf("stopped service should not become ready, got %d cmds", len(cmds))
+	}
+}
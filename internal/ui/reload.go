@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/watch"
+)
+
+// fileChangedMsg reports that the config or registry file changed on disk.
+type fileChangedMsg struct {
+	path string
+}
+
+// SetWatcher makes the dashboard apply changes to the config and registry
+// files as they happen, such as edits from another terminal or a dotfile
+// manager. Call it before the program starts.
+func (m *Model) SetWatcher(w *watch.Watcher) {
+	m.watcher = w
+}
+
+// waitForFileChange waits for the next change reported by the watcher.
+func (m *Model) waitForFileChange() tea.Cmd {
+	if m.watcher == nil {
+		return nil
+	}
+	changes := m.watcher.Changes()
+	return func() tea.Msg {
+		path, ok := <-changes
+		if !ok {
+			return nil
+		}
+		return fileChangedMsg{path: path}
+	}
+}
+
+// handleFileChange reloads the file that changed.
+func (m *Model) handleFileChange(path string) tea.Cmd {
+	switch path {
+	case registry.Path():
+		changed, err := m.registry.Reload(path)
+		if err != nil {
+			m.toast.Show(fmt.Sprintf("Failed to reload registry: %v", err), ToastError, 5*time.Second)
+			return m.toast.TickCmd()
+		}
+		if !changed {
+			return nil // Our own save
+		}
+		m.updateDisplayedProjects()
+		m.toast.Show("Projects reloaded", ToastInfo, 2*time.Second)
+		return m.toast.TickCmd()
+
+	case config.Path():
+		cfg, err := config.Load(path)
+		if err != nil {
+			m.showConfigError(err, "Failed to reload config")
+			return m.toast.TickCmd()
+		}
+		changes, cmd := m.switchConfig(cfg)
+		if len(changes) == 0 {
+			return nil // Our own save, or a change that doesn't matter
+		}
+		m.toast.Show("Config reloaded: "+strings.Join(changes, ", "), ToastSuccess, 3*time.Second)
+		return tea.Batch(cmd, m.toast.TickCmd())
+	}
+	return nil
+}
+
+// switchConfig applies a config loaded from disk and returns what changed,
+// along with a rescan when the scan paths did.
+func (m *Model) switchConfig(cfg *config.Config) ([]string, tea.Cmd) {
+	changes := config.Changes(m.config, cfg)
+	m.config = cfg
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	m.applyConfig()
+	m.applyTheme()
+	// Update settings panel with new config
+	m.settings = NewSettingsPanel(m.config, m.styles, m.width, m.height)
+	m.updateDisplayedProjects()
+
+	if !slices.Contains(changes, "scan paths") {
+		return changes, nil
+	}
+	if cfg.Projects.AutoDiscover {
+		return changes, m.startScan()
+	}
+	if m.dirWatcher != nil {
+		m.dirWatcher.Set(nil) // Stop watching for projects
+	}
+	return changes, nil
+}
+
+// showConfigError reports a config that failed to load. Invalid configs
+// are rejected and the current one stays in effect.
+func (m *Model) showConfigError(err error, prefix string) {
+	var invalid *config.ValidationError
+	if !errors.As(err, &invalid) {
+		m.toast.Show(fmt.Sprintf("%s: %v", prefix, err), ToastError, 5*time.Second)
+		return
+	}
+	text := "Config rejected, keeping previous: " + invalid.Problems[0].String()
+	if more := len(invalid.Problems) - 1; more > 0 {
+		text += fmt.Sprintf(" (+%d more, see devdash config check)", more)
+	}
+	m.toast.Show(text, ToastError, 8*time.Second)
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/restart"
+	"github.com/infktd/devdash/internal/watch"
+)
+
+func writeConfigFile(t *testing.T, cfg *config.Config) {
+	t.Helper()
+	if err := config.Save(config.Path(), cfg); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+}
+
+func TestFileChangeReloadsConfig(t *testing.T) {
+	m := New(config.Default(), &registry.Registry{})
+
+	changed := config.Default()
+	changed.UI.Theme = "nord"
+	changed.Polling.BackgroundProject = 30
+	writeConfigFile(t, changed)
+
+	m.handleFileChange(config.Path())
+	if m.config.UI.Theme != "nord" || m.config.Polling.BackgroundProject != 30 {
+		t.Fatalf("config not reloaded: %+v", m.config.UI)
+	}
+	if m.styles.theme.Name != "nord" {
+		t.Error("styles should switch to the new theme")
+	}
+	if m.projectsDelegate.styles != m.styles {
+		t.Error("sidebar delegate should use the new styles")
+	}
+	if m.spinner.Style.GetForeground() != m.styles.theme.Primary {
+		t.Error("spinner should use the new theme")
+	}
+	msg := m.toast.current.Message
+	if !strings.Contains(msg, "theme") || !strings.Contains(msg, "polling intervals") {
+		t.Errorf("toast = %q, want the changed settings", msg)
+	}
+}
+
+func TestFileChangeIgnoresOwnSave(t *testing.T) {
+	m := New(config.Default(), &registry.Registry{})
+	writeConfigFile(t, m.config)
+
+	if cmd := m.handleFileChange(config.Path()); cmd != nil || m.toast.current != nil {
+		t.Error("saving the current config shouldn't announce a reload")
+	}
+}
+
+func TestFileChangeKeepsRestartHolds(t *testing.T) {
+	m := New(config.Default(), &registry.Registry{})
+	m.config.Restart.Policies = []config.RestartPolicy{{Service: "*", Policy: config.RestartOnFailure}}
+	m.applyConfig()
+	m.restarts.HoldProject("api")
+
+	// Our own save, then a real change
+	writeConfigFile(t, m.config)
+	m.handleFileChange(config.Path())
+	changed := *m.config
+	changed.UI.Theme = "nord"
+	writeConfigFile(t, &changed)
+	m.handleFileChange(config.Path())
+
+	if d := m.restarts.Crashed("api", "web", time.Now()); d.Action != restart.ActionNone {
+		t.Errorf("reload should keep the project hold, got %+v", d)
+	}
+}
+
+func TestFileChangeRejectsInvalidConfig(t *testing.T) {
+	m := New(config.Default(), &registry.Registry{})
+	previous := m.config
+	os.MkdirAll(filepath.Dir(config.Path()), 0755)
+	os.WriteFile(config.Path(), []byte("version: 1\npolling:\n  focused_project: 0\n"), 0644)
+
+	m.handleFileChange(config.Path())
+	if m.config != previous {
+		t.Error("an invalid config should keep the previous one")
+	}
+	if msg := m.toast.current.Message; !strings.Contains(msg, "polling.focused_project") {
+		t.Errorf("toast = %q, want the problem", msg)
+	}
+}
+
+func TestFileChangeRescansNewScanPaths(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	os.MkdirAll(project, 0755)
+	os.WriteFile(filepath.Join(project, "devenv.nix"), []byte("{}"), 0644)
+
+	m := New(config.Default(), &registry.Registry{})
+	changed := config.Default()
+	changed.Projects.ScanPaths = []string{root}
+	writeConfigFile(t, changed)
+
+	cmd := m.handleFileChange(config.Path())
+	if cmd == nil {
+		t.Fatal("new scan paths should trigger a rescan")
+	}
+	finishScan(t, m, cmd)
+	if m.registry.FindByPath(project) == nil {
+		t.Error("the rescan should add the new project")
+	}
+}
+
+func TestFileChangeReloadsRegistry(t *testing.T) {
+	reg, err := registry.Load(registry.Path())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	m := New(config.Default(), reg)
+
+	time.Sleep(10 * time.Millisecond)
+	other, _ := registry.Load(registry.Path())
+	added := other.AddProject(filepath.Join(t.TempDir(), "elsewhere"))
+	registry.Save(registry.Path(), other)
+
+	m.handleFileChange(registry.Path())
+	if m.registry.FindByPath(added.Path) == nil {
+		t.Error("the registry change should be picked up")
+	}
+}
+
+func TestWaitForFileChange(t *testing.T) {
+	if cmd := New(config.Default(), &registry.Registry{}).waitForFileChange(); cmd != nil {
+		t.Error("without a watcher there is nothing to wait for")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	w, err := watch.New(path)
+	if err != nil {
+		t.Fatalf("watch.New() error = %v", err)
+	}
+	defer w.Close()
+	m := New(config.Default(), &registry.Registry{})
+	m.SetWatcher(w)
+
+	os.WriteFile(path, []byte("ui: {}\n"), 0644)
+	got := make(chan tea.Msg, 1)
+	go func() { got <- m.waitForFileChange()() }()
+	select {
+	case msg := <-got:
+		if msg != (fileChangedMsg{path: path}) {
+			t.Errorf("msg = %#v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no file change delivered")
+	}
+}
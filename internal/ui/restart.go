@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/health"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/restart"
+)
+
+// restartDueMsg fires when a scheduled automatic restart is due.
+type restartDueMsg struct {
+	project string
+	service string
+	attempt int
+}
+
+// restartResultMsg reports the outcome of an automatic restart.
+type restartResultMsg struct {
+	project string
+	service string
+	attempt int
+	err     error
+}
+
+// projectByName returns the registered project with the given name,
+// preferring one that is running.
+func (m *Model) projectByName(name string) *registry.Project {
+	var found *registry.Project
+	for _, p := range m.registry.Projects {
+		if p.Name != name {
+			continue
+		}
+		state := m.projectStates[p.Path]
+		if state.IsActive() {
+			return p
+		}
+		if found == nil {
+			found = p
+		}
+	}
+	return found
+}
+
+// superviseCrash applies the restart policy to a crash event.
+func (m *Model) superviseCrash(event health.Event) []tea.Cmd {
+	decision := m.restarts.Crashed(event.Project, event.Service, event.Timestamp)
+
+	switch decision.Action {
+	case restart.ActionRestart:
+		flush := m.addAlert(Alert{
+			Type:      AlertServiceRestarted,
+			Project:   event.Project,
+			Service:   event.Service,
+			Message:   fmt.Sprintf("restart attempt %d in %s", decision.Attempt, decision.Delay),
+			Timestamp: time.Now(),
+		})
+		project, service, attempt := event.Project, event.Service, decision.Attempt
+		return []tea.Cmd{flush, tea.Tick(decision.Delay, func(time.Time) tea.Msg {
+			return restartDueMsg{project: project, service: service, attempt: attempt}
+		})}
+
+	case restart.ActionGiveUp:
+		message := fmt.Sprintf("gave up restarting after %d crashes", decision.Attempt)
+		flush := m.addAlert(Alert{
+			Type:      AlertCritical,
+			Project:   event.Project,
+			Service:   event.Service,
+			Message:   message,
+			Timestamp: time.Now(),
+		})
+		m.toast.Show(
+			fmt.Sprintf("%s/%s: %s", event.Project, event.Service, message),
+			ToastError,
+			5*time.Second,
+		)
+		_ = m.notifier.Critical(
+			"acidBurn: Restart Policy Exhausted",
+			fmt.Sprintf("%s in %s %s", event.Service, event.Project, message),
+		)
+		return []tea.Cmd{flush, m.toast.TickCmd()}
+	}
+	return nil
+}
+
+// handleRestartDue performs a scheduled restart if it is still wanted and
+// the project is still up.
+func (m *Model) handleRestartDue(msg restartDueMsg) tea.Cmd {
+	if !m.restarts.Pending(msg.project, msg.service, msg.attempt) {
+		// Stopped, recovered or crashed again while the restart was pending
+		return nil
+	}
+	p := m.projectByName(msg.project)
+	if p == nil {
+		return nil
+	}
+	state := m.projectStates[p.Path]
+	if !state.IsActive() {
+		// Project was stopped while the restart was pending
+		return nil
+	}
+
+	socketPath := p.SocketPath()
+	return func() tea.Msg {
+		client := compose.NewClient(socketPath)
+		err := client.Connect()
+		if err == nil {
+			err = client.RestartProcess(msg.service)
+		}
+		return restartResultMsg{
+			project: msg.project,
+			service: msg.service,
+			attempt: msg.attempt,
+			err:     err,
+		}
+	}
+}
+
+// handleRestartResult records the outcome of an automatic restart.
+func (m *Model) handleRestartResult(msg restartResultMsg) []tea.Cmd {
+	if msg.err == nil {
+		// Recovery shows up through the regular health events
+		return []tea.Cmd{m.pollServicesCmd()}
+	}
+
+	flush := m.addAlert(Alert{
+		Type:      AlertServiceRestarted,
+		Project:   msg.project,
+		Service:   msg.service,
+		Message:   fmt.Sprintf("restart attempt %d failed: %v", msg.attempt, msg.err),
+		Timestamp: time.Now(),
+	})
+	m.toast.Show(
+		fmt.Sprintf("Auto-restart of %s failed: %s", msg.service, describeComposeError(msg.err)),
+		ToastWarn,
+		5*time.Second,
+	)
+	return []tea.Cmd{flush, m.toast.TickCmd()}
+}
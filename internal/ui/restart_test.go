@@ -0,0 +1,163 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/health"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/restart"
+)
+
+func newRestartTestModel(t *testing.T, maxAttempts int) (*Model, *registry.Project) {
+	t.Helper()
+	m, current, _ := newBackgroundTestModel(t)
+	m.notifier.SetEnabled(false)
+	m.restarts = restart.NewSupervisor([]config.RestartPolicy{{
+		Service:     "worker",
+		Policy:      config.RestartOnFailure,
+		MaxAttempts: maxAttempts,
+	}})
+	return m, current
+}
+
+func crash(project string) health.Event {
+	return health.Event{
+		Type:      health.EventServiceCrashed,
+		Project:   project,
+		Service:   "worker",
+		ExitCode:  1,
+		Timestamp: time.Now(),
+	}
+}
+
+func TestSuperviseCrashSchedulesRestart(t *testing.T) {
+	m, p := newRestartTestModel(t, 3)
+
+	cmds := m.superviseCrash(crash(p.Name))
+	if len(cmds) != 2 {
+		t.Fatalf("expected an alert write and a scheduled restart, got %d cmds", len(cmds))
+	}
+	alerts := m.alerts.All()
+	if len(alerts) == 0 || alerts[len(alerts)-1].Type != AlertServiceRestarted {
+		t.Errorf("restart attempt should be recorded in alert history, got %+v", alerts)
+	}
+}
+
+func TestSuperviseCrashGivesUpWithCriticalAlert(t *testing.T) {
+	m, p := newRestartTestModel(t, 1)
+
+	m.superviseCrash(crash(p.Name))
+	m.superviseCrash(crash(p.Name))
+
+	alerts := m.alerts.All()
+	last := alerts[len(alerts)-1]
+	if last.Type != AlertCritical || last.Service != "worker" {
+		t.Errorf("expected critical give-up alert, got %+v", last)
+	}
+}
+
+func TestHandleRestartDueSkipsStoppedProject(t *testing.T) {
+	m, p := newRestartTestModel(t, 3)
+	m.superviseCrash(crash(p.Name))
+	m.projectStates[p.Path] = registry.StateIdle
+
+	if cmd := m.handleRestartDue(restartDueMsg{project: p.Name, service: "worker", attempt: 1}); cmd != nil {
+		t.Error("restart should be skipped once the project is stopped")
+	}
+
+	m.projectStates[p.Path] = registry.StateDegraded
+	if cmd := m.handleRestartDue(restartDueMsg{project: p.Name, service: "worker", attempt: 1}); cmd == nil {
+		t.Error("restart should run while the project is up")
+	}
+}
+
+func TestHandleRestartDueSkipsServiceStoppedDuringDelay(t *testing.T) {
+	m, p, srv := newFakeProject(t)
+	srv.AddProcess("worker")
+	m.restarts = restart.NewSupervisor([]config.RestartPolicy{{Service: "worker", Policy: config.RestartOnFailure}})
+	m.showSplash = false
+	m.projectStates[p.Path] = registry.StateDegraded
+	m.superviseCrash(crash(p.Name))
+
+	// x on the crashed service while the restart is pending
+	m.services = []compose.ProcessStatus{{Name: "worker"}}
+	m.selectedService = 0
+	m.focused = PaneServices
+	m.handleKeyPress(runeKey('x'))
+
+	if cmd := m.handleRestartDue(restartDueMsg{project: p.Name, service: "worker", attempt: 1}); cmd != nil {
+		t.Error("restart should be dropped for a service stopped during the delay")
+	}
+}
+
+func TestHandleRestartDueSkipsSupersededAttempt(t *testing.T) {
+	m, p := newRestartTestModel(t, 3)
+	m.projectStates[p.Path] = registry.StateDegraded
+	m.superviseCrash(crash(p.Name))
+	m.superviseCrash(crash(p.Name))
+
+	if cmd := m.handleRestartDue(restartDueMsg{project: p.Name, service: "worker", attempt: 1}); cmd != nil {
+		t.Error("restart for an earlier crash should be dropped")
+	}
+	if cmd := m.handleRestartDue(restartDueMsg{project: p.Name, service: "worker", attempt: 2}); cmd == nil {
+		t.Error("restart for the latest crash should run")
+	}
+
+	m.restarts.Recovered(p.Name, "worker")
+	if cmd := m.handleRestartDue(restartDueMsg{project: p.Name, service: "worker", attempt: 2}); cmd != nil {
+		t.Error("restart should be dropped once the service recovered")
+	}
+}
+
+func TestHandleRestartResultRecordsFailure(t *testing.T) {
+	m, p := newRestartTestModel(t, 3)
+
+	m.handleRestartResult(restartResultMsg{project: p.Name, service: "worker", attempt: 2, err: errors.New("connection refused")})
+	alerts := m.alerts.All()
+	if len(alerts) == 0 || alerts[len(alerts)-1].Type != AlertServiceRestarted {
+		t.Errorf("failed restart should be recorded, got %+v", alerts)
+	}
+}
+
+func TestCrashLoopIsSupervisedWhileFlapping(t *testing.T) {
+	m, p := newRestartTestModel(t, 5)
+	m.health.SetFlapDetection(5, 2*time.Minute)
+
+	update := func(running bool, exitCode int) {
+		if event := m.health.UpdateService(p.Name, "worker", running, exitCode); event != nil {
+			m.handleHealthEvent(*event)
+		}
+	}
+
+	before := len(m.alerts.All())
+	update(true, 0)
+	for i := 0; i < 6; i++ {
+		update(false, 1)
+		update(true, 0)
+	}
+
+	if !m.health.IsFlapping(p.Name, "worker") {
+		t.Fatal("crash loop should be flapping")
+	}
+	restarts := 0
+	var last Alert
+	for _, a := range m.alerts.All()[before:] {
+		if a.Project != p.Name || a.Service != "worker" {
+			continue
+		}
+		if a.Type == AlertServiceRestarted {
+			restarts++
+		}
+		last = a
+	}
+	if restarts != 5 {
+		t.Errorf("expected 5 restart attempts while flapping, got %d", restarts)
+	}
+	if last.Type != AlertCritical {
+		t.Errorf("expected give-up alert after the last crash, got %+v", last)
+	}
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/scanner"
+	"github.com/infktd/devdash/internal/watch"
+)
+
+// scanBatch caps how many found projects are applied per message.
+const scanBatch = 50
+
+// scanStartedMsg is sent when a project scan is running.
+type scanStartedMsg struct {
+	gen   int
+	found <-chan scanner.Hit
+	done  <-chan scanDoneMsg
+}
+
+// scanFoundMsg delivers a batch of projects found by the running scan.
+type scanFoundMsg struct {
+	gen   int
+	hits  []scanner.Hit
+	found <-chan scanner.Hit
+	done  <-chan scanDoneMsg
+}
+
+// scanDoneMsg is sent when a project scan finishes.
+type scanDoneMsg struct {
+	gen  int
+	dirs []string // Directories the scan looked in
+	err  error
+}
+
+// dirChangedMsg reports that projects may have appeared, moved or gone
+// under the scan paths.
+type dirChangedMsg struct{}
+
+// startScan discovers projects under the scan paths in the background,
+// cancelling a scan that's already running. Messages from a cancelled scan
+// are dropped by generation.
+func (m *Model) startScan() tea.Cmd {
+	if m.scanCancel != nil {
+		m.scanCancel()
+	}
+	var ctx context.Context
+	ctx, m.scanCancel = context.WithCancel(context.Background())
+	m.scanGen++
+	m.scanning = true
+	m.scanAdded = 0
+	m.scanMoved = nil
+	m.scanUpdated = false
+
+	p := m.config.Projects
+	opts := scanner.Options{MaxDepth: p.ScanDepth, Exclude: slices.Clone(p.Exclude)}
+	return scanCmd(ctx, m.scanGen, slices.Clone(p.ScanPaths), opts)
+}
+
+// scanCmd starts walking paths, reusing and then updating the scan cache.
+func scanCmd(ctx context.Context, gen int, paths []string, opts scanner.Options) tea.Cmd {
+	return func() tea.Msg {
+		found := make(chan scanner.Hit, scanBatch)
+		done := make(chan scanDoneMsg, 1)
+		go func() {
+			var dirs []string
+			opts.OnDir = func(dir string) { dirs = append(dirs, dir) }
+			cachePath := scanner.CachePath()
+			opts.Cache = scanner.LoadCache(cachePath)
+			err := scanner.Walk(ctx, paths, opts, func(h scanner.Hit) {
+				select {
+				case found <- h:
+				case <-ctx.Done():
+				}
+			})
+			if err == nil {
+				// The cache only saves time; the next scan reads everything without it
+				_ = opts.Cache.Save(cachePath)
+			}
+			close(found)
+			done <- scanDoneMsg{gen: gen, dirs: dirs, err: err}
+		}()
+		return scanStartedMsg{gen: gen, found: found, done: done}
+	}
+}
+
+// waitForScanCmd blocks for the next found project and drains whatever else
+// is buffered.
+func waitForScanCmd(gen int, found <-chan scanner.Hit, done <-chan scanDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		hit, ok := <-found
+		if !ok {
+			return <-done
+		}
+
+		hits := []scanner.Hit{hit}
+		for len(hits) < scanBatch {
+			select {
+			case hit, ok := <-found:
+				if !ok {
+					// Deliver what we have, the next wait reports the end
+					return scanFoundMsg{gen: gen, hits: hits, found: found, done: done}
+				}
+				hits = append(hits, hit)
+			default:
+				return scanFoundMsg{gen: gen, hits: hits, found: found, done: done}
+			}
+		}
+		return scanFoundMsg{gen: gen, hits: hits, found: found, done: done}
+	}
+}
+
+// handleScanStarted begins reading a running scan.
+func (m *Model) handleScanStarted(msg scanStartedMsg) tea.Cmd {
+	if msg.gen != m.scanGen {
+		return nil
+	}
+	return waitForScanCmd(msg.gen, msg.found, msg.done)
+}
+
+// handleScanFound adds newly found projects to the sidebar as they arrive,
+// keeping the selection on the same project. A project found where a
+// missing one moved to takes its place, and known projects get their
+// layouts and identities updated.
+func (m *Model) handleScanFound(msg scanFoundMsg) tea.Cmd {
+	if msg.gen != m.scanGen {
+		return nil
+	}
+	added := false
+	for _, h := range msg.hits {
+		if m.registry.FindByPath(h.Path) == nil {
+			if moved := m.registry.FindMoved(h.Remote, h.Fingerprint); moved != nil {
+				old := moved.Path
+				m.registry.Relocate(old, h.Path)
+				delete(m.clients, old)
+				delete(m.projectStates, old)
+				delete(m.stateResults, old)
+				m.scanMoved = append(m.scanMoved, moved)
+			} else {
+				m.registry.AddProject(h.Path)
+				m.scanAdded++
+			}
+			added = true
+		}
+		if m.registry.SetLayout(h.Path, h.Kind, h.Root) {
+			m.scanUpdated = true
+		}
+		if m.registry.SetIdentity(h.Path, h.Remote, h.Fingerprint) {
+			m.scanUpdated = true
+		}
+	}
+	if added {
+		selected := m.currentProject()
+		m.updateDisplayedProjects()
+		if selected != nil {
+			m.selectProject(selected)
+		}
+	}
+	return waitForScanCmd(msg.gen, msg.found, msg.done)
+}
+
+// handleScanDone saves the projects the scan added, moved or updated, and
+// watches the directories it looked in for projects appearing or moving.
+func (m *Model) handleScanDone(msg scanDoneMsg) tea.Cmd {
+	if msg.gen != m.scanGen {
+		return nil
+	}
+	m.scanning = false
+
+	var cmds []tea.Cmd
+	if m.scanAdded > 0 || len(m.scanMoved) > 0 || m.scanUpdated {
+		cmds = append(cmds, m.saveRegistry())
+	}
+	var news []string
+	if m.scanAdded > 0 {
+		news = append(news, fmt.Sprintf("Found %d new project(s)", m.scanAdded))
+	}
+	switch len(m.scanMoved) {
+	case 0:
+	case 1:
+		p := m.scanMoved[0]
+		news = append(news, fmt.Sprintf("%s moved to %s", p.Name, p.Path))
+	default:
+		news = append(news, fmt.Sprintf("%d projects moved", len(m.scanMoved)))
+	}
+	shown := len(news) > 0
+	if shown {
+		m.toast.Show(strings.Join(news, ", "), ToastSuccess, 3*time.Second)
+	}
+
+	err := msg.err
+	if err == nil && m.dirWatcher != nil {
+		if werr := m.dirWatcher.Set(msg.dirs); werr != nil && !m.dirWatchFailed {
+			// Reported once; every rescan would repeat it
+			m.dirWatchFailed = true
+			err = fmt.Errorf("can't watch for new projects: %w", werr)
+		}
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		m.toast.Show(fmt.Sprintf("Error during project scan: %v", err), ToastWarn, 5*time.Second)
+		shown = true
+	}
+	if shown {
+		cmds = append(cmds, m.toast.TickCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+// SetDirWatcher makes the dashboard pick up projects as they're created,
+// moved or deleted under the scan paths, watching the directories each scan
+// looks in. Call it before the program starts.
+func (m *Model) SetDirWatcher(w *watch.DirWatcher) {
+	m.dirWatcher = w
+}
+
+// waitForDirChange waits for the next change under the scan paths.
+func (m *Model) waitForDirChange() tea.Cmd {
+	if m.dirWatcher == nil {
+		return nil
+	}
+	changes := m.dirWatcher.Changes()
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		return dirChangedMsg{}
+	}
+}
+
+// handleDirChange rescans after a change under the scan paths. The scan
+// cache makes this cheap: only changed directories are read again.
+func (m *Model) handleDirChange() tea.Cmd {
+	if !m.config.Projects.AutoDiscover {
+		return nil
+	}
+	return m.startScan()
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/watch"
+)
+
+// scanMsgs runs cmd and returns the scan messages it produced, looking
+// inside batches.
+func scanMsgs(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		var out []tea.Msg
+		for _, c := range msg {
+			out = append(out, scanMsgs(c)...)
+		}
+		return out
+	case scanStartedMsg, scanFoundMsg, scanDoneMsg:
+		return []tea.Msg{msg}
+	}
+	return nil
+}
+
+// finishScan feeds the scan started by cmd through the model until it's done.
+func finishScan(t *testing.T, m *Model, cmd tea.Cmd) {
+	t.Helper()
+	msgs := scanMsgs(cmd)
+	if len(msgs) == 0 {
+		t.Fatal("expected a scan to start")
+	}
+	for _, msg := range msgs {
+		for msg != nil {
+			var next tea.Cmd
+			switch msg := msg.(type) {
+			case scanStartedMsg:
+				next = m.handleScanStarted(msg)
+			case scanFoundMsg:
+				next = m.handleScanFound(msg)
+			case scanDoneMsg:
+				m.handleScanDone(msg)
+			}
+			if next == nil {
+				break
+			}
+			msg = next()
+		}
+	}
+}
+
+func newScanRoot(t *testing.T, names ...string) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, name := range names {
+		dir := filepath.Join(root, name)
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "devenv.nix"), []byte("{}"), 0644)
+	}
+	return root
+}
+
+func TestScanStreamsProjectsIntoSidebar(t *testing.T) {
+	root := newScanRoot(t, "api", "web")
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{root}
+	m := New(cfg, &registry.Registry{})
+
+	cmd := m.startScan()
+	if !m.scanning {
+		t.Error("scanning should be set while the scan runs")
+	}
+	finishScan(t, m, cmd)
+
+	if m.scanning {
+		t.Error("scanning should be cleared when the scan is done")
+	}
+	if len(m.displayedProjects) != 2 {
+		t.Errorf("sidebar has %d projects, want 2", len(m.displayedProjects))
+	}
+	if got := m.toast.current.Message; got != "Found 2 new project(s)" {
+		t.Errorf("toast = %q", got)
+	}
+	saved, err := registry.Load(registry.Path())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if saved.FindByPath(filepath.Join(root, "api")) == nil {
+		t.Error("found projects should be saved")
+	}
+}
+
+func TestScanSkipsKnownProjects(t *testing.T) {
+	root := newScanRoot(t, "api")
+	reg := &registry.Registry{}
+	reg.AddProject(filepath.Join(root, "api"))
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{root}
+	m := New(cfg, reg)
+
+	finishScan(t, m, m.startScan())
+	if m.scanAdded != 0 {
+		t.Errorf("scan added %d projects, want none", m.scanAdded)
+	}
+	if m.toast.current != nil {
+		t.Errorf("no toast expected, got %q", m.toast.current.Message)
+	}
+}
+
+func TestRestartedScanDropsOldResults(t *testing.T) {
+	root := newScanRoot(t, "api")
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{root}
+	m := New(cfg, &registry.Registry{})
+
+	stale := m.startScan()
+	m.startScan()
+	finishScan(t, m, stale)
+	if len(m.registry.Projects) != 0 {
+		t.Error("results of a cancelled scan should be dropped")
+	}
+	if !m.scanning {
+		t.Error("the newer scan is still running")
+	}
+}
+
+func TestInitScansOnlyWithAutoDiscover(t *testing.T) {
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{t.TempDir()}
+	cfg.Projects.AutoDiscover = false
+	m := New(cfg, &registry.Registry{})
+	m.Init()
+	if m.scanning {
+		t.Error("Init shouldn't scan with auto_discover off")
+	}
+
+	cfg.Projects.AutoDiscover = true
+	m = New(cfg, &registry.Registry{})
+	m.Init()
+	if !m.scanning {
+		t.Error("Init should start a scan with auto_discover on")
+	}
+	m.scanCancel()
+}
+
+func TestScanUpdatesLayouts(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "app")
+	os.MkdirAll(project, 0755)
+	os.WriteFile(filepath.Join(project, "flake.nix"), []byte("devenv.lib.mkShell"), 0644)
+	reg := &registry.Registry{}
+	reg.AddProject(project)
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{root}
+	m := New(cfg, reg)
+
+	finishScan(t, m, m.startScan())
+	if got := reg.FindByPath(project).Kind; got != registry.KindFlake {
+		t.Errorf("Kind = %q, want flake", got)
+	}
+	saved, _ := registry.Load(registry.Path())
+	if p := saved.FindByPath(project); p == nil || p.Kind != registry.KindFlake {
+		t.Error("the changed layout should be saved")
+	}
+}
+
+func TestScanRelocatesMovedProjects(t *testing.T) {
+	root := newScanRoot(t, "api")
+	old := filepath.Join(root, "api")
+	// Unique, as other tests leave projects with the same files behind
+	os.WriteFile(filepath.Join(old, "devenv.nix"), []byte("{ env.TEST = \""+t.Name()+"\"; }"), 0644)
+	reg := &registry.Registry{}
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{root}
+	m := New(cfg, reg)
+	finishScan(t, m, m.startScan())
+
+	p := reg.FindByPath(old)
+	if p == nil || p.Fingerprint == "" {
+		t.Fatalf("scan should record the project's fingerprint, got %+v", p)
+	}
+	p.Tags = []string{"backend"}
+	id := p.ID
+
+	moved := filepath.Join(root, "services", "api")
+	os.MkdirAll(filepath.Dir(moved), 0755)
+	if err := os.Rename(old, moved); err != nil {
+		t.Fatal(err)
+	}
+	finishScan(t, m, m.startScan())
+
+	if reg.FindByPath(old) != nil || p.Path != moved {
+		t.Fatalf("project should move to %s, got %s", moved, p.Path)
+	}
+	if p.ID != id || !p.HasTag("backend") {
+		t.Errorf("a moved project keeps its ID and tags, got %+v", p)
+	}
+	if m.scanAdded != 0 {
+		t.Error("a move shouldn't add a project")
+	}
+	if got, want := m.toast.current.Message, "api moved to "+moved; got != want {
+		t.Errorf("toast = %q, want %q", got, want)
+	}
+}
+
+func TestDirChangeRescans(t *testing.T) {
+	root := newScanRoot(t)
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{root}
+	m := New(cfg, &registry.Registry{})
+	w, err := watch.NewDirs()
+	if err != nil {
+		t.Fatalf("NewDirs() error = %v", err)
+	}
+	defer w.Close()
+	m.SetDirWatcher(w)
+	finishScan(t, m, m.startScan())
+
+	// The scan watches the root, so a new project is noticed
+	os.Mkdir(filepath.Join(root, "web"), 0755)
+	os.WriteFile(filepath.Join(root, "web", "devenv.nix"), []byte("{}"), 0644)
+	msg := m.waitForDirChange()()
+	if _, ok := msg.(dirChangedMsg); !ok {
+		t.Fatalf("waitForDirChange() = %#v, want dirChangedMsg", msg)
+	}
+	finishScan(t, m, m.handleDirChange())
+	if m.registry.FindByPath(filepath.Join(root, "web")) == nil {
+		t.Error("the rescan should add the new project")
+	}
+
+	m.config.Projects.AutoDiscover = false
+	if m.handleDirChange() != nil {
+		t.Error("changes shouldn't rescan with auto_discover off")
+	}
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+// sidebarSection is a titled run of projects in the sidebar.
+type sidebarSection struct {
+	title    string
+	projects []*registry.Project
+}
+
+// matchesFilter reports whether a project matches the sidebar filter. The
+// filter is a list of space-separated terms that must all match: tag:x
+// matches projects tagged x, any other term a part of the project's name.
+func matchesFilter(p *registry.Project, filter string) bool {
+	for _, term := range strings.Fields(filter) {
+		if tag, ok := strings.CutPrefix(term, "tag:"); ok {
+			if tag != "" && !p.HasTag(tag) {
+				return false
+			}
+			continue
+		}
+		if !strings.Contains(strings.ToLower(p.Name), strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// sidebarSections splits projects into the sidebar's sections: favorites
+// first, then by the configured grouping, each sorted by the configured
+// sort mode. Empty sections are left out.
+func (m *Model) sidebarSections(projects []*registry.Project) []sidebarSection {
+	var favorites, rest []*registry.Project
+	for _, p := range projects {
+		if p.Favorite {
+			favorites = append(favorites, p)
+		} else {
+			rest = append(rest, p)
+		}
+	}
+
+	var sections []sidebarSection
+	if len(favorites) > 0 {
+		sections = append(sections, sidebarSection{title: "FAVORITES", projects: favorites})
+	}
+
+	switch m.config.UI.SidebarGroupBy {
+	case config.GroupByTag:
+		sections = append(sections, groupProjects(rest, func(p *registry.Project) string {
+			if len(p.Tags) == 0 {
+				return ""
+			}
+			return strings.ToUpper(p.Tags[0])
+		}, "UNTAGGED")...)
+	case config.GroupByRoot:
+		roots := m.config.Projects.ScanPaths
+		sections = append(sections, groupProjects(rest, func(p *registry.Project) string {
+			return scanRootOf(p.Path, roots)
+		}, "OTHER")...)
+	default:
+		var active, idle []*registry.Project
+		for _, p := range rest {
+			if m.projectStates[p.Path].IsActive() {
+				active = append(active, p)
+			} else {
+				idle = append(idle, p)
+			}
+		}
+		sections = append(sections,
+			sidebarSection{title: "ACTIVE", projects: active},
+			sidebarSection{title: "IDLE", projects: idle},
+		)
+	}
+
+	nonEmpty := sections[:0]
+	for _, s := range sections {
+		if len(s.projects) > 0 {
+			m.sortProjects(s.projects)
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return nonEmpty
+}
+
+// groupProjects splits projects into sections by key, sorted by title,
+// with projects whose key is empty last under fallback.
+func groupProjects(projects []*registry.Project, key func(*registry.Project) string, fallback string) []sidebarSection {
+	byKey := make(map[string][]*registry.Project)
+	var titles []string
+	var other []*registry.Project
+	for _, p := range projects {
+		k := key(p)
+		if k == "" {
+			other = append(other, p)
+			continue
+		}
+		if _, seen := byKey[k]; !seen {
+			titles = append(titles, k)
+		}
+		byKey[k] = append(byKey[k], p)
+	}
+	sort.Strings(titles)
+
+	sections := make([]sidebarSection, 0, len(titles)+1)
+	for _, title := range titles {
+		sections = append(sections, sidebarSection{title: title, projects: byKey[title]})
+	}
+	return append(sections, sidebarSection{title: fallback, projects: other})
+}
+
+// scanRootOf returns the scan path that contains path, shortened with ~,
+// or "" if none does. The most specific root wins.
+func scanRootOf(path string, roots []string) string {
+	home, _ := os.UserHomeDir()
+	best := ""
+	for _, root := range roots {
+		expanded := root
+		if rest, ok := strings.CutPrefix(root, "~/"); ok && home != "" {
+			expanded = filepath.Join(home, rest)
+		}
+		expanded = filepath.Clean(expanded)
+		if path != expanded && !strings.HasPrefix(path, expanded+string(filepath.Separator)) {
+			continue
+		}
+		if len(expanded) > len(best) {
+			best = expanded
+		}
+	}
+	if home != "" && best != "" {
+		if rest, ok := strings.CutPrefix(best, home+string(filepath.Separator)); ok {
+			return "~/" + rest
+		}
+	}
+	return best
+}
+
+// stateRank orders project states for the state sort mode.
+var stateRank = map[registry.ProjectState]int{
+	registry.StateRunning:  0,
+	registry.StateStarting: 1,
+	registry.StateDegraded: 2,
+	registry.StateStale:    3,
+	registry.StateIdle:     4,
+	registry.StateMissing:  5,
+}
+
+// sortProjects sorts projects in place by the configured sort mode, with
+// name as the tiebreaker.
+func (m *Model) sortProjects(projects []*registry.Project) {
+	mode := m.config.UI.SidebarSort
+	sort.SliceStable(projects, func(i, j int) bool {
+		a, b := projects[i], projects[j]
+		switch mode {
+		case config.SortByLastActive:
+			if !a.LastActive.Equal(b.LastActive) {
+				return a.LastActive.After(b.LastActive)
+			}
+		case config.SortByState:
+			ra, rb := stateRank[m.projectStates[a.Path]], stateRank[m.projectStates[b.Path]]
+			if ra != rb {
+				return ra < rb
+			}
+		}
+		return a.Name < b.Name
+	})
+}
+
+// touchActiveProjects records the time projects became active, so the
+// last active sort reflects use. It reports whether any project changed.
+// Projects started from the TUI are assumed running before they are
+// probed, so touchProject records those.
+func (m *Model) touchActiveProjects(states map[string]registry.ProjectState) bool {
+	touched := false
+	now := time.Now()
+	for _, p := range m.registry.Projects {
+		prev, seen := m.projectStates[p.Path]
+		if states[p.Path].IsActive() && (!seen || !prev.IsActive()) {
+			p.LastActive = now
+			touched = true
+		}
+	}
+	return touched
+}
+
+// touchProject records that the user just started p and saves the
+// registry.
+func (m *Model) touchProject(p *registry.Project) tea.Cmd {
+	p.LastActive = time.Now()
+	return m.saveRegistry()
+}
+
+// sidebarGroupModes and sidebarSortModes are cycled by the sidebar keys.
+var (
+	sidebarGroupModes = []string{config.GroupByState, config.GroupByTag, config.GroupByRoot}
+	sidebarSortModes  = []string{config.SortByName, config.SortByLastActive, config.SortByState}
+)
+
+// nextMode returns the mode after current in modes, wrapping around.
+func nextMode(modes []string, current string) string {
+	for i, mode := range modes {
+		if mode == current {
+			return modes[(i+1)%len(modes)]
+		}
+	}
+	return modes[0]
+}
+
+// cycleSidebarGroupBy switches to the next sidebar grouping and saves it.
+func (m *Model) cycleSidebarGroupBy() tea.Cmd {
+	m.config.UI.SidebarGroupBy = nextMode(sidebarGroupModes, m.config.UI.SidebarGroupBy)
+	m.updateDisplayedProjects()
+	return m.showSidebarMode("Group by " + strings.ReplaceAll(m.config.UI.SidebarGroupBy, "_", " "))
+}
+
+// cycleSidebarSort switches to the next sidebar sort mode and saves it.
+func (m *Model) cycleSidebarSort() tea.Cmd {
+	m.config.UI.SidebarSort = nextMode(sidebarSortModes, m.config.UI.SidebarSort)
+	m.updateDisplayedProjects()
+	return m.showSidebarMode("Sort by " + strings.ReplaceAll(m.config.UI.SidebarSort, "_", " "))
+}
+
+// showSidebarMode announces a sidebar mode change and saves the config.
+func (m *Model) showSidebarMode(text string) tea.Cmd {
+	m.toast.Show(text, ToastInfo, 2*time.Second)
+	// Save a copy; m.config may change while the command runs
+	cfg := *m.config
+	return tea.Batch(m.toast.TickCmd(), func() tea.Msg {
+		if err := config.Save(config.Path(), &cfg); err != nil {
+			return settingsSaveErrorMsg{err: err}
+		}
+		return nil
+	})
+}
+
+// toggleFavorite pins or unpins the selected project and saves the registry.
+func (m *Model) toggleFavorite() tea.Cmd {
+	p := m.currentProject()
+	if p == nil {
+		return nil
+	}
+	m.registry.ToggleFavorite(p.Path)
+	m.updateDisplayedProjects()
+	m.selectProject(p)
+	return m.saveRegistry()
+}
+
+// beginTagEdit starts editing the selected project's tags.
+func (m *Model) beginTagEdit() {
+	if p := m.currentProject(); p != nil {
+		m.tagEditMode = true
+		m.tagInput = strings.Join(p.Tags, ", ")
+	}
+}
+
+// handleTagEditKey edits the tag input; Enter saves, Esc cancels.
+func (m *Model) handleTagEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.tagEditMode = false
+		return m, nil
+	case tea.KeyEnter:
+		m.tagEditMode = false
+		p := m.currentProject()
+		if p == nil {
+			return m, nil
+		}
+		m.registry.SetTags(p.Path, strings.FieldsFunc(m.tagInput, func(r rune) bool {
+			return r == ',' || r == ' '
+		}))
+		m.updateDisplayedProjects()
+		m.selectProject(p)
+		return m, m.saveRegistry()
+	case tea.KeyBackspace:
+		if len(m.tagInput) > 0 {
+			runes := []rune(m.tagInput)
+			m.tagInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.tagInput += " "
+	case tea.KeyRunes:
+		m.tagInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// selectProject moves the sidebar selection to p, which may have moved to
+// another section.
+func (m *Model) selectProject(p *registry.Project) {
+	for i, dp := range m.displayedProjects {
+		if dp == p {
+			m.selectedProject = i
+			m.projectsList.Select(m.projectIndexToListIndex(i))
+			return
+		}
+	}
+}
+
+// saveRegistry saves the registry, reporting failures with a toast. It
+// saves right away rather than in a command because Save merges other
+// instances' changes into m.registry, which only Update may touch.
+func (m *Model) saveRegistry() tea.Cmd {
+	if err := registry.Save(registry.Path(), m.registry); err != nil {
+		return func() tea.Msg { return registrySaveErrorMsg{err: err} }
+	}
+	return nil
+}
+
+// registrySaveErrorMsg is sent when the registry fails to save.
+type registrySaveErrorMsg struct {
+	err error
+}
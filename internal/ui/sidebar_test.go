@@ -0,0 +1,299 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func sectionNames(sections []sidebarSection) map[string][]string {
+	out := make(map[string][]string)
+	for _, s := range sections {
+		for _, p := range s.projects {
+			out[s.title] = append(out[s.title], p.Name)
+		}
+	}
+	return out
+}
+
+func sectionTitles(sections []sidebarSection) []string {
+	var titles []string
+	for _, s := range sections {
+		titles = append(titles, s.title)
+	}
+	return titles
+}
+
+// sidebarModel has api (backend, running), web (frontend), worker (backend,
+// favorite) and docs (untagged) under /src, and blog outside it.
+func sidebarModel() *Model {
+	reg := &registry.Registry{}
+	api := reg.AddProject("/src/api")
+	api.Tags = []string{"backend"}
+	web := reg.AddProject("/src/web")
+	web.Tags = []string{"frontend"}
+	worker := reg.AddProject("/src/worker")
+	worker.Tags = []string{"backend"}
+	worker.Favorite = true
+	reg.AddProject("/src/docs")
+	reg.AddProject("/elsewhere/blog")
+
+	cfg := config.Default()
+	cfg.Projects.ScanPaths = []string{"/src"}
+	m := New(cfg, reg)
+	for _, p := range reg.Projects {
+		m.projectStates[p.Path] = registry.StateIdle
+	}
+	m.projectStates["/src/api"] = registry.StateRunning
+	return m
+}
+
+func TestMatchesFilter(t *testing.T) {
+	p := &registry.Project{Name: "billing-api", Tags: []string{"Backend", "go"}}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"", true},
+		{"bill", true},
+		{"API", true},
+		{"web", false},
+		{"tag:backend", true},
+		{"tag:frontend", false},
+		{"tag:go bill", true},
+		{"tag:go web", false},
+		{"tag:", true},
+	}
+	for _, tt := range tests {
+		if got := matchesFilter(p, tt.filter); got != tt.want {
+			t.Errorf("matchesFilter(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestSidebarSectionsByState(t *testing.T) {
+	m := sidebarModel()
+	sections := m.sidebarSections(m.registry.Projects)
+
+	if got := sectionTitles(sections); !reflect.DeepEqual(got, []string{"FAVORITES", "ACTIVE", "IDLE"}) {
+		t.Errorf("titles = %v", got)
+	}
+	want := map[string][]string{
+		"FAVORITES": {"worker"},
+		"ACTIVE":    {"api"},
+		"IDLE":      {"blog", "docs", "web"},
+	}
+	if got := sectionNames(sections); !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+}
+
+func TestSidebarSectionsByTag(t *testing.T) {
+	m := sidebarModel()
+	m.config.UI.SidebarGroupBy = config.GroupByTag
+	sections := m.sidebarSections(m.registry.Projects)
+
+	want := []string{"FAVORITES", "BACKEND", "FRONTEND", "UNTAGGED"}
+	if got := sectionTitles(sections); !reflect.DeepEqual(got, want) {
+		t.Errorf("titles = %v, want %v", got, want)
+	}
+	if got := sectionNames(sections)["UNTAGGED"]; !reflect.DeepEqual(got, []string{"blog", "docs"}) {
+		t.Errorf("UNTAGGED = %v", got)
+	}
+}
+
+func TestSidebarSectionsByRoot(t *testing.T) {
+	m := sidebarModel()
+	m.config.UI.SidebarGroupBy = config.GroupByRoot
+	sections := m.sidebarSections(m.registry.Projects)
+
+	want := map[string][]string{
+		"FAVORITES": {"worker"},
+		"/src":      {"api", "docs", "web"},
+		"OTHER":     {"blog"},
+	}
+	if got := sectionNames(sections); !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %v, want %v", got, want)
+	}
+}
+
+func TestScanRootOf(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	roots := []string{"~/src", "~/src/work", "/opt"}
+	if got := scanRootOf(filepath.Join(home, "src", "work", "api"), roots); got != "~/src/work" {
+		t.Errorf("most specific root should win, got %q", got)
+	}
+	if got := scanRootOf("/opt/tool", roots); got != "/opt" {
+		t.Errorf("scanRootOf(/opt/tool) = %q", got)
+	}
+	if got := scanRootOf("/optional/x", roots); got != "" {
+		t.Errorf("a path prefix that isn't a parent directory shouldn't match, got %q", got)
+	}
+}
+
+func TestSortProjects(t *testing.T) {
+	m := sidebarModel()
+	now := time.Now()
+	projects := []*registry.Project{
+		m.registry.FindByPath("/src/web"),
+		m.registry.FindByPath("/src/api"),
+		m.registry.FindByPath("/src/docs"),
+	}
+	projects[0].LastActive = now
+	projects[1].LastActive = now.Add(-time.Hour)
+	projects[2].LastActive = now.Add(-2 * time.Hour)
+	names := func() []string {
+		var out []string
+		for _, p := range projects {
+			out = append(out, p.Name)
+		}
+		return out
+	}
+
+	m.sortProjects(projects)
+	if got := names(); !reflect.DeepEqual(got, []string{"api", "docs", "web"}) {
+		t.Errorf("by name = %v", got)
+	}
+	m.config.UI.SidebarSort = config.SortByLastActive
+	m.sortProjects(projects)
+	if got := names(); !reflect.DeepEqual(got, []string{"web", "api", "docs"}) {
+		t.Errorf("by last active = %v", got)
+	}
+	m.config.UI.SidebarSort = config.SortByState
+	m.sortProjects(projects)
+	if got := names(); !reflect.DeepEqual(got, []string{"api", "docs", "web"}) {
+		t.Errorf("by state = %v", got)
+	}
+}
+
+func TestNextMode(t *testing.T) {
+	if got := nextMode(sidebarSortModes, config.SortByState); got != config.SortByName {
+		t.Errorf("nextMode should wrap around, got %q", got)
+	}
+	if got := nextMode(sidebarGroupModes, "bogus"); got != config.GroupByState {
+		t.Errorf("nextMode of an unknown mode = %q, want the first", got)
+	}
+}
+
+func TestTouchActiveProjects(t *testing.T) {
+	m := sidebarModel()
+	api := m.registry.FindByPath("/src/api")
+	web := m.registry.FindByPath("/src/web")
+	old := time.Now().Add(-time.Hour)
+	api.LastActive, web.LastActive = old, old
+
+	states := map[string]registry.ProjectState{
+		"/src/api": registry.StateRunning, // Already running
+		"/src/web": registry.StateRunning, // Just started
+	}
+	if !m.touchActiveProjects(states) {
+		t.Fatal("touchActiveProjects should report the newly active project")
+	}
+	if !api.LastActive.Equal(old) {
+		t.Error("a project that stayed active keeps its time")
+	}
+	if !web.LastActive.After(old) {
+		t.Error("a newly active project should be touched")
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	reg := &registry.Registry{}
+	p := reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+	m.showSplash = false
+	m.focused = PaneSidebar
+	m.updateDisplayedProjects()
+
+	m.handleKeyPress(runeKey('t'))
+	if !m.tagEditMode {
+		t.Fatal("t should start editing tags")
+	}
+	for _, r := range "api," {
+		m.handleKeyPress(runeKey(r))
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeySpace})
+	for _, r := range "go" {
+		m.handleKeyPress(runeKey(r))
+	}
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEnter})
+
+	if m.tagEditMode {
+		t.Error("Enter should finish editing")
+	}
+	if !reflect.DeepEqual(p.Tags, []string{"api", "go"}) {
+		t.Errorf("Tags = %v, want [api go]", p.Tags)
+	}
+
+	m.handleKeyPress(runeKey('t'))
+	m.handleKeyPress(runeKey('x'))
+	m.handleKeyPress(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(p.Tags) != 2 {
+		t.Errorf("Esc should discard the edit, got %v", p.Tags)
+	}
+}
+
+func TestToggleFavoriteKeepsSelection(t *testing.T) {
+	reg := &registry.Registry{}
+	reg.AddProject(filepath.Join(t.TempDir(), "a"))
+	b := reg.AddProject(filepath.Join(t.TempDir(), "b"))
+	m := New(config.Default(), reg)
+	m.updateDisplayedProjects()
+	m.selectProject(b)
+
+	m.toggleFavorite()
+	if !b.Favorite {
+		t.Fatal("project should be a favorite")
+	}
+	if m.currentProject() != b {
+		t.Errorf("selection should follow the project into FAVORITES, got %v", m.currentProject().Name)
+	}
+	if m.displayedProjects[0] != b {
+		t.Error("favorites should be pinned at the top")
+	}
+}
+
+func TestSidebarFooterKeepsSelectAndHide(t *testing.T) {
+	m := sidebarModel()
+	m.focused = PaneSidebar
+
+	footer := m.renderFooter()
+	for _, want := range []string{"[Enter] Select", "[Ctrl+h] Hide"} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("footer should show %q, got %q", want, footer)
+		}
+	}
+}
+
+func TestStartFromTUITouchesProject(t *testing.T) {
+	reg := &registry.Registry{}
+	p := reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+	m.showSplash = false
+	m.assumeState(p.Path, registry.StateIdle)
+	defer func() {
+		if m.startCancel != nil {
+			m.startCancel()
+		}
+	}()
+
+	before := time.Now()
+	m.handleKeyPress(runeKey('s'))
+	if m.loadingOp != "Starting" {
+		t.Fatalf("s should start the idle project, loading op %q", m.loadingOp)
+	}
+	if p.LastActive.Before(before) {
+		t.Error("a project started from the TUI should be touched")
+	}
+}
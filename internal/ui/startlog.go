@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/compose"
+	"github.com/infktd/devdash/internal/devenv"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+const (
+	startLogMax       = 1000                   // Lines of devenv output kept
+	startOutputBatch  = 100                    // Lines delivered per message
+	startReadyPoll    = 500 * time.Millisecond // Interval between readiness checks
+	startReadyTimeout = 30 * time.Second       // Give up waiting for processes after this
+)
+
+// startOutputMsg delivers lines of devenv up output.
+type startOutputMsg struct {
+	project string
+	lines   []string
+	ch      <-chan string
+	result  <-chan error
+}
+
+// startExitedMsg reports that devenv up has exited.
+type startExitedMsg struct {
+	project string
+	err     error
+}
+
+// startReadyMsg reports how many processes are up after devenv up returned.
+type startReadyMsg struct {
+	project string
+	ready   int
+	total   int
+	since   time.Time // When waiting began
+}
+
+// beginStart launches devenv up for a project and streams its output into
+// the start log.
+func (m *Model) beginStart(p *registry.Project) tea.Cmd {
+	if m.startCancel != nil {
+		m.startCancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.startCancel = cancel
+	m.startLog = nil
+	m.startLogProject = p.Name
+	m.startFailed = false
+	m.setStartStage(devenv.StageStarting)
+
+	lines := make(chan string, 256)
+	result := make(chan error, 1)
+	dir, kind := p.Dir(), p.Kind
+	go func() {
+		result <- devenv.UpStream(ctx, dir, kind, lines)
+	}()
+
+	return waitForStartOutputCmd(p.Name, lines, result)
+}
+
+// waitForStartOutputCmd waits for the next batch of devenv output, or for
+// devenv to exit once all output has been read.
+func waitForStartOutputCmd(project string, ch <-chan string, result <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-ch
+		if !ok {
+			return startExitedMsg{project: project, err: <-result}
+		}
+		lines := []string{line}
+		for len(lines) < startOutputBatch {
+			select {
+			case line, ok := <-ch:
+				if !ok {
+					return startOutputMsg{project: project, lines: lines, ch: ch, result: result}
+				}
+				lines = append(lines, line)
+			default:
+				return startOutputMsg{project: project, lines: lines, ch: ch, result: result}
+			}
+		}
+		return startOutputMsg{project: project, lines: lines, ch: ch, result: result}
+	}
+}
+
+// setStartStage moves the start progress forward to stage.
+func (m *Model) setStartStage(stage devenv.Stage) {
+	m.startStage = stage
+	m.loadingStage = stage.String()
+	m.loadingProgress = stage.Progress()
+}
+
+// appendStartLog adds lines to the start log, keeping the most recent ones.
+func (m *Model) appendStartLog(lines ...string) {
+	m.startLog = append(m.startLog, lines...)
+	if over := len(m.startLog) - startLogMax; over > 0 {
+		m.startLog = m.startLog[over:]
+	}
+}
+
+// handleStartOutput records devenv output and advances the parsed stage.
+func (m *Model) handleStartOutput(msg startOutputMsg) tea.Cmd {
+	if msg.project == m.startLogProject {
+		m.appendStartLog(msg.lines...)
+		for _, line := range msg.lines {
+			if stage, ok := devenv.ParseStage(line); ok && stage > m.startStage {
+				m.setStartStage(stage)
+			}
+		}
+	}
+	return waitForStartOutputCmd(msg.project, msg.ch, msg.result)
+}
+
+// handleStartExited either fails the start or waits for processes to come up.
+func (m *Model) handleStartExited(msg startExitedMsg) tea.Cmd {
+	if msg.project != m.startLogProject {
+		return nil
+	}
+	m.startCancel = nil
+
+	if msg.err == nil {
+		m.setStartStage(devenv.StageWaiting)
+		return m.checkStartReadyCmd(msg.project, time.Now())
+	}
+
+	p := m.projectByName(msg.project)
+	if p != nil {
+		m.assumeState(p.Path, registry.StateIdle)
+		m.refreshState(p.Path)
+	}
+	m.clearLoading()
+
+	if errors.Is(msg.err, context.Canceled) {
+		m.startLog = nil
+		m.startLogProject = ""
+		m.toast.Show(fmt.Sprintf("Start of %s cancelled", msg.project), ToastWarn, 3*time.Second)
+		cmds := []tea.Cmd{m.toast.TickCmd()}
+		// Tear down anything devenv managed to launch before it was killed
+		if p != nil {
+			if _, err := os.Stat(p.SocketPath()); err == nil {
+				cmds = append(cmds, m.stopProjectCmd(p))
+			}
+		}
+		return tea.Batch(cmds...)
+	}
+
+	// Keep the start log on screen so the full build error is visible
+	m.startFailed = true
+	m.appendStartLog("", "✗ "+msg.err.Error())
+	m.toast.Show(fmt.Sprintf("Failed to start %s - see start log", msg.project), ToastError, 5*time.Second)
+	return m.toast.TickCmd()
+}
+
+// checkStartReadyCmd counts running processes after a delay.
+func (m *Model) checkStartReadyCmd(project string, since time.Time) tea.Cmd {
+	p := m.projectByName(project)
+	if p == nil {
+		return nil
+	}
+	socketPath := p.SocketPath()
+	return tea.Tick(startReadyPoll, func(time.Time) tea.Msg {
+		msg := startReadyMsg{project: project, since: since}
+		client := compose.NewClient(socketPath)
+		if err := client.Connect(); err != nil {
+			return msg
+		}
+		status, err := client.GetStatus()
+		if err != nil {
+			return msg
+		}
+		for _, svc := range status.Processes {
+			msg.total++
+			if svc.IsRunning || svc.Status == "Completed" {
+				msg.ready++
+			}
+		}
+		return msg
+	})
+}
+
+// handleStartReady finishes the start once all processes are up, or after
+// startReadyTimeout.
+func (m *Model) handleStartReady(msg startReadyMsg) tea.Cmd {
+	if msg.project != m.startLogProject || m.startStage != devenv.StageWaiting {
+		return nil
+	}
+
+	if msg.total > 0 {
+		m.loadingStage = fmt.Sprintf("Waiting for processes (%d/%d ready)", msg.ready, msg.total)
+		m.loadingProgress = devenv.StageWaiting.Progress() +
+			(devenv.StageReady.Progress()-devenv.StageWaiting.Progress())*float64(msg.ready)/float64(msg.total)
+	}
+
+	allReady := msg.total > 0 && msg.ready == msg.total
+	if !allReady && time.Since(msg.since) < startReadyTimeout {
+		return m.checkStartReadyCmd(msg.project, msg.since)
+	}
+
+	m.setStartStage(devenv.StageReady)
+	m.startLog = nil
+	m.startLogProject = ""
+	project := msg.project
+	return func() tea.Msg {
+		return projectStartedMsg{project: project}
+	}
+}
+
+// cancelStart kills an in-progress devenv up.
+func (m *Model) cancelStart() bool {
+	if m.startCancel == nil {
+		return false
+	}
+	m.startCancel()
+	m.startCancel = nil
+	m.loadingStage = "Cancelling..."
+	return true
+}
+
+// dismissStartLog hides the start log of a failed start.
+func (m *Model) dismissStartLog() bool {
+	if !m.startFailed {
+		return false
+	}
+	m.startFailed = false
+	m.startLog = nil
+	m.startLogProject = ""
+	return true
+}
+
+// showingStartLog reports whether the logs pane shows the start log for the
+// current project.
+func (m *Model) showingStartLog() bool {
+	p := m.currentProject()
+	return p != nil && m.startLogProject == p.Name && (m.startCancel != nil || m.startFailed || m.startStage == devenv.StageWaiting)
+}
+
+// clearLoading resets the loading indicator state.
+func (m *Model) clearLoading() {
+	m.loadingOp = ""
+	m.loadingProject = ""
+	m.loadingProgress = 0
+	m.loadingStage = ""
+	m.loadingStarted = time.Time{}
+}
+
+// renderStartLog renders the tail of the start log for the logs pane.
+func (m *Model) renderStartLog(width, height int) string {
+	title := fmt.Sprintf("START LOG [%s]", m.startLogProject)
+	content := m.renderSectionTitle(title, m.focused == PaneLogs, width-4) + "\n"
+
+	hint := "[ctrl+c] cancel start"
+	if m.startFailed {
+		hint = "[Esc] dismiss"
+	}
+	content += m.styles.Breadcrumb.Render(m.loadingStage+"  "+hint) + "\n"
+
+	// Wrap rather than truncate so build errors are readable in full
+	var rendered []string
+	for _, line := range m.startLog {
+		isError := strings.HasPrefix(line, "✗") || strings.Contains(strings.ToLower(line), "error")
+		for _, part := range wrapLine(line, width-6) {
+			if isError {
+				part = m.styles.StatusStale.Render(part)
+			}
+			rendered = append(rendered, part)
+		}
+	}
+
+	visible := height - 4
+	if visible < 1 {
+		visible = 1
+	}
+	if len(rendered) > visible {
+		rendered = rendered[len(rendered)-visible:]
+	}
+	content += strings.Join(rendered, "\n")
+
+	style := m.styles.BlurredBorder
+	if m.focused == PaneLogs {
+		style = m.styles.FocusedBorder
+	}
+	return style.Width(width).Height(height).Render(content)
+}
+
+// wrapLine splits a line into chunks of at most width runes.
+func wrapLine(line string, width int) []string {
+	runes := []rune(line)
+	if width <= 0 || len(runes) <= width {
+		return []string{line}
+	}
+	var parts []string
+	for len(runes) > width {
+		parts = append(parts, string(runes[:width]))
+		runes = runes[width:]
+	}
+	return append(parts, string(runes))
+}
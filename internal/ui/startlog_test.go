@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/devenv"
+	"github.com/infktd/devdash/internal/registry"
+)
+
+func newStartTestModel(t *testing.T) (*Model, *registry.Project) {
+	t.Helper()
+	reg := &registry.Registry{}
+	reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+	p := m.currentProject()
+	m.loadingOp = "Starting"
+	m.loadingProject = p.Name
+	m.startLogProject = p.Name
+	m.startCancel = func() {}
+	m.projectStates[p.Path] = registry.StateRunning
+	return m, p
+}
+
+func TestHandleStartOutputAdvancesStage(t *testing.T) {
+	m, p := newStartTestModel(t)
+
+	cmd := m.handleStartOutput(startOutputMsg{
+		project: p.Name,
+		lines:   []string{"• Evaluating devenv.nix", "building '/nix/store/x.drv'", "• Evaluating again"},
+		ch:      make(chan string),
+		result:  make(chan error),
+	})
+	if cmd == nil {
+		t.Error("should keep waiting for output")
+	}
+	if len(m.startLog) != 3 {
+		t.Errorf("expected 3 start log lines, got %d", len(m.startLog))
+	}
+	// Stages only move forward
+	if m.startStage != devenv.StageBuilding {
+		t.Errorf("stage = %v, want building", m.startStage)
+	}
+	if m.loadingProgress != devenv.StageBuilding.Progress() {
+		t.Errorf("progress = %v, want %v", m.loadingProgress, devenv.StageBuilding.Progress())
+	}
+	if !m.showingStartLog() {
+		t.Error("logs pane should show the start log while starting")
+	}
+}
+
+func TestHandleStartExitedFailureKeepsLog(t *testing.T) {
+	m, p := newStartTestModel(t)
+	m.startLog = []string{"error: undefined variable 'postgress'"}
+
+	m.handleStartExited(startExitedMsg{project: p.Name, err: errors.New("devenv up failed: exit status 1")})
+
+	if !m.startFailed || !m.showingStartLog() {
+		t.Fatal("failed start should keep the start log visible")
+	}
+	if m.loadingOp != "" {
+		t.Error("loading state should be cleared")
+	}
+	if m.projectStates[p.Path] != registry.StateIdle {
+		t.Error("failed project should be marked idle")
+	}
+	if last := m.startLog[len(m.startLog)-1]; !strings.Contains(last, "exit status 1") {
+		t.Errorf("start log should end with the error, got %q", last)
+	}
+
+	view := m.renderStartLog(80, 20)
+	if !strings.Contains(view, "postgress") {
+		t.Error("start log pane should show the build error")
+	}
+
+	if !m.dismissStartLog() || m.showingStartLog() {
+		t.Error("Esc should dismiss the failed start log")
+	}
+}
+
+func TestHandleStartExitedCancelled(t *testing.T) {
+	m, p := newStartTestModel(t)
+
+	m.handleStartExited(startExitedMsg{project: p.Name, err: context.Canceled})
+
+	if m.startFailed || m.showingStartLog() {
+		t.Error("cancelled start should not leave a start log")
+	}
+	if m.loadingOp != "" {
+		t.Error("loading state should be cleared")
+	}
+}
+
+func TestHandleStartExitedSuccessWaitsForProcesses(t *testing.T) {
+	m, p := newStartTestModel(t)
+
+	cmd := m.handleStartExited(startExitedMsg{project: p.Name})
+	if cmd == nil {
+		t.Fatal("expected readiness check")
+	}
+	if m.startStage != devenv.StageWaiting {
+		t.Errorf("stage = %v, want waiting", m.startStage)
+	}
+
+	// Partially ready: keep waiting
+	if cmd := m.handleStartReady(startReadyMsg{project: p.Name, ready: 1, total: 2, since: time.Now()}); cmd == nil {
+		t.Error("should keep polling until all processes are ready")
+	}
+	if !strings.Contains(m.loadingStage, "1/2") {
+		t.Errorf("stage text = %q, want ready count", m.loadingStage)
+	}
+
+	// All ready: finish the start
+	cmd = m.handleStartReady(startReadyMsg{project: p.Name, ready: 2, total: 2, since: time.Now()})
+	if cmd == nil {
+		t.Fatal("expected completion")
+	}
+	if msg, ok := cmd().(projectStartedMsg); !ok || msg.err != nil {
+		t.Errorf("expected successful projectStartedMsg, got %#v", msg)
+	}
+}
+
+func TestCancelStart(t *testing.T) {
+	m, _ := newStartTestModel(t)
+	cancelled := false
+	m.startCancel = func() { cancelled = true }
+
+	if !m.cancelStart() || !cancelled {
+		t.Error("cancelStart should invoke the cancel func")
+	}
+	if m.cancelStart() {
+		t.Error("second cancel should be a no-op")
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	parts := wrapLine("abcdefghij", 4)
+	if len(parts) != 3 || parts[0] != "abcd" || parts[2] != "ij" {
+		t.Errorf("wrapLine = %q", parts)
+	}
+	if parts := wrapLine("short", 10); len(parts) != 1 {
+		t.Errorf("short line should not wrap, got %q", parts)
+	}
+}
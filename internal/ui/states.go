@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/statecache"
+)
+
+// stateChangesMsg delivers project states that changed, keyed by path.
+type stateChangesMsg struct {
+	diff statecache.Diff
+}
+
+// stateIntervals returns the probe intervals for the polling config: active
+// projects at the focused rate, the rest at the background rate or slower.
+func stateIntervals(cfg *config.Config) statecache.Intervals {
+	focused := time.Duration(cfg.Polling.FocusedProject) * time.Second
+	if focused <= 0 {
+		focused = 2 * time.Second
+	}
+	background := time.Duration(cfg.Polling.BackgroundProject) * time.Second
+	if background <= 0 {
+		background = 10 * time.Second
+	}
+	return statecache.IntervalsFor(focused, background)
+}
+
+// runStatesCmd starts probing project states. The cache runs for the life
+// of the program.
+func (m *Model) runStatesCmd() tea.Cmd {
+	states := m.states
+	return func() tea.Msg {
+		go states.Run(context.Background())
+		return nil
+	}
+}
+
+// waitForStateChanges waits for the state cache to report changes.
+func (m *Model) waitForStateChanges() tea.Cmd {
+	changes := m.states.Changes()
+	return func() tea.Msg {
+		return stateChangesMsg{diff: <-changes}
+	}
+}
+
+// handleStateChanges records changed project states, notes projects that
+// became active and redraws the sidebar.
+func (m *Model) handleStateChanges(msg stateChangesMsg) tea.Cmd {
+	states := make(map[string]registry.ProjectState, len(msg.diff))
+	for path, r := range msg.diff {
+		m.stateResults[path] = r
+		if p := m.registry.FindByPath(path); p != nil {
+			states[path], _ = m.stateOf(p)
+		}
+	}
+	var save tea.Cmd
+	if m.touchActiveProjects(states) {
+		save = m.saveRegistry()
+	}
+	m.updateDisplayedProjects()
+	return tea.Batch(save, m.waitForStateChanges())
+}
+
+// stateOf returns a project's last probed state, holding running projects
+// in Starting until their services pass readiness probes. ok is false if
+// the project hasn't been probed yet.
+func (m *Model) stateOf(p *registry.Project) (state registry.ProjectState, ok bool) {
+	r, ok := m.stateResults[p.Path]
+	if !ok {
+		return registry.StateIdle, false
+	}
+	if r.State == registry.StateRunning {
+		for _, service := range r.Services {
+			if !m.serviceReady(p.Name, service) {
+				return registry.StateStarting, true
+			}
+		}
+	}
+	return r.State, true
+}
+
+// assumeState sets a project's state ahead of the probes, such as while it
+// starts or stops. It holds until the project is probed again with
+// refreshState or its probed state changes.
+func (m *Model) assumeState(path string, state registry.ProjectState) {
+	m.projectStates[path] = state
+	delete(m.stateResults, path)
+}
+
+// refreshState probes a project again right away, such as after an action
+// that changed its state.
+func (m *Model) refreshState(path string) {
+	m.states.Refresh(path)
+}
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/registry"
+	"github.com/infktd/devdash/internal/statecache"
+)
+
+func TestStateChangesUpdateSidebar(t *testing.T) {
+	reg := &registry.Registry{}
+	api := reg.AddProject(filepath.Join(t.TempDir(), "api"))
+	web := reg.AddProject(filepath.Join(t.TempDir(), "web"))
+	cfg := config.Default()
+	cfg.Probes = []config.ProbeConfig{{Project: "web", Service: "server", TCP: "localhost:1"}}
+	m := New(cfg, reg)
+
+	if cmd := m.handleStateChanges(stateChangesMsg{diff: statecache.Diff{
+		api.Path: {State: registry.StateRunning, Services: []string{"db"}},
+		web.Path: {State: registry.StateRunning, Services: []string{"server"}},
+	}}); cmd == nil {
+		t.Error("handleStateChanges should wait for the next changes")
+	}
+	if got := m.projectStates[api.Path]; got != registry.StateRunning {
+		t.Errorf("api state = %v, want running", got)
+	}
+	if got := m.projectStates[web.Path]; got != registry.StateStarting {
+		t.Errorf("web should be starting until its readiness probe passes, got %v", got)
+	}
+}
+
+func TestAssumedStateHoldsUntilProbed(t *testing.T) {
+	reg := &registry.Registry{}
+	p := reg.AddProject(t.TempDir())
+	m := New(config.Default(), reg)
+	m.handleStateChanges(stateChangesMsg{diff: statecache.Diff{p.Path: {State: registry.StateIdle}}})
+
+	m.assumeState(p.Path, registry.StateRunning)
+	m.updateDisplayedProjects()
+	if got := m.projectStates[p.Path]; got != registry.StateRunning {
+		t.Fatalf("assumed state should hold, got %v", got)
+	}
+
+	m.handleStateChanges(stateChangesMsg{diff: statecache.Diff{p.Path: {State: registry.StateIdle}}})
+	if got := m.projectStates[p.Path]; got != registry.StateIdle {
+		t.Errorf("a probe should replace the assumed state, got %v", got)
+	}
+}
@@ -2,6 +2,8 @@ package ui
 
 import (
 	"testing"
+
+	"github.com/infktd/devdash/internal/config"
 )
 
 func TestGetThemeReturnsDefault(t *testing.T) {
@@ -34,3 +36,14 @@ func TestAllThemesHaveRequiredColors(t *testing.T) {
 		}
 	}
 }
+
+func TestThemesMatchConfigThemeNames(t *testing.T) {
+	if len(Themes) != len(config.ThemeNames) {
+		t.Errorf("%d themes, config.ThemeNames lists %d", len(Themes), len(config.ThemeNames))
+	}
+	for _, name := range config.ThemeNames {
+		if _, ok := Themes[name]; !ok {
+			t.Errorf("config.ThemeNames lists %q, which has no theme", name)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package watch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DirDebounce is how long the watched directories must be quiet before a
+// change is reported, so a git checkout or a moved tree is one change.
+const DirDebounce = 500 * time.Millisecond
+
+// DirWatcher reports entries being created, removed or renamed in a set of
+// directories, such as projects appearing or moving under the scan paths.
+// Writes to existing files are ignored, since they don't change what's in
+// a directory.
+type DirWatcher struct {
+	fsw       *fsnotify.Watcher
+	debounce  time.Duration
+	changes   chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu   sync.Mutex
+	dirs map[string]bool
+}
+
+// NewDirs returns a DirWatcher watching nothing until Set is called.
+func NewDirs() (*DirWatcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &DirWatcher{
+		fsw:      fsw,
+		debounce: DirDebounce,
+		changes:  make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		dirs:     make(map[string]bool),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Set watches exactly dirs, adding and dropping watches as needed. It
+// watches every directory it can and returns the first error, such as
+// running out of inotify watches.
+func (w *DirWatcher) Set(dirs []string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	want := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		want[dir] = true
+	}
+	for dir := range w.dirs {
+		if !want[dir] {
+			// Fails for directories that are gone, which fsnotify already dropped
+			w.fsw.Remove(dir)
+			delete(w.dirs, dir)
+		}
+	}
+	var firstErr error
+	for dir := range want {
+		if w.dirs[dir] {
+			continue
+		}
+		if err := w.fsw.Add(dir); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		w.dirs[dir] = true
+	}
+	return firstErr
+}
+
+// Changes receives a value after a burst of changes to the watched
+// directories has settled.
+func (w *DirWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching and closes the Changes channel.
+func (w *DirWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+func (w *DirWatcher) run() {
+	defer close(w.changes)
+
+	timer := time.NewTimer(0)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Remove) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			select {
+			case w.changes <- struct{}{}:
+			default: // A change is already waiting to be read
+			}
+		case _, ok := <-w.fsw.Errors:
+			// Errors mean missed events, such as an overflowing queue;
+			// the next change is still reported
+			if !ok {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package watch reports changes to files devdash shares with editors and
+// other instances, such as the config and the registry, and to the
+// directories it scans for projects.
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long a file must be quiet before its change is
+// reported, so an editor's write, chmod and rename count as one change.
+const DefaultDebounce = 100 * time.Millisecond
+
+// Watcher reports changes to a set of files. It watches their directories
+// rather than the files themselves, since editors and fsutil.WriteFile
+// replace files by renaming over them.
+type Watcher struct {
+	fsw       *fsnotify.Watcher
+	files     map[string]bool
+	debounce  time.Duration
+	changes   chan string
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// New watches paths, creating their directories if needed.
+func New(paths ...string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		fsw:      fsw,
+		files:    make(map[string]bool),
+		debounce: DefaultDebounce,
+		changes:  make(chan string, len(paths)),
+		done:     make(chan struct{}),
+	}
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		w.files[path] = true
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+		if err := fsw.Add(dir); err != nil {
+			fsw.Close()
+			return nil, err
+		}
+	}
+	go w.run()
+	return w, nil
+}
+
+// Changes delivers the path of each watched file that changed. A burst of
+// writes to one file is delivered once.
+func (w *Watcher) Changes() <-chan string {
+	return w.changes
+}
+
+// Close stops watching and closes the Changes channel.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.done)
+		err = w.fsw.Close()
+	})
+	return err
+}
+
+func (w *Watcher) run() {
+	defer close(w.changes)
+
+	timers := make(map[string]*time.Timer)
+	fire := make(chan string)
+	defer func() {
+		for _, t := range timers {
+			t.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			path := filepath.Clean(event.Name)
+			if !w.files[path] || event.Op == fsnotify.Chmod {
+				continue
+			}
+			if t, ok := timers[path]; ok {
+				t.Reset(w.debounce)
+				continue
+			}
+			timers[path] = time.AfterFunc(w.debounce, func() {
+				select {
+				case fire <- path:
+				case <-w.done:
+				}
+			})
+		case path := <-fire:
+			delete(timers, path)
+			select {
+			case w.changes <- path:
+			case <-w.done:
+				return
+			}
+		case _, ok := <-w.fsw.Errors:
+			// Errors mean missed events, such as an overflowing queue;
+			// the next change to the file is still reported
+			if !ok {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/infktd/devdash/internal/fsutil"
+)
+
+func expectChange(t *testing.T, w *Watcher, want string) {
+	t.Helper()
+	select {
+	case got := <-w.Changes():
+		if got != want {
+			t.Errorf("change = %q, want %q", got, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no change reported for %s", want)
+	}
+}
+
+func expectQuiet(t *testing.T, w *Watcher) {
+	t.Helper()
+	select {
+	case got := <-w.Changes():
+		t.Errorf("unexpected change %q", got)
+	case <-time.After(3 * DefaultDebounce):
+	}
+}
+
+func TestWatcherReportsChanges(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "devdash")
+	config := filepath.Join(dir, "config.yaml")
+	projects := filepath.Join(dir, "projects.yaml")
+
+	w, err := New(config, projects)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer w.Close()
+
+	// Several writes in a burst are one change
+	for i := 0; i < 3; i++ {
+		os.WriteFile(config, []byte("ui: {}\n"), 0644)
+	}
+	expectChange(t, w, config)
+	expectQuiet(t, w)
+
+	// Atomic replacement by rename
+	if err := fsutil.WriteFile(projects, []byte("projects: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	expectChange(t, w, projects)
+
+	// Other files in the directory are ignored
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644)
+	expectQuiet(t, w)
+}
+
+func TestWatcherClose(t *testing.T) {
+	w, err := New(filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	w.Close()
+	w.Close() // Closing twice is fine
+
+	select {
+	case _, ok := <-w.Changes():
+		if ok {
+			t.Error("Changes should be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Changes not closed after Close")
+	}
+}
+
+func expectDirChange(t *testing.T, w *DirWatcher) {
+	t.Helper()
+	select {
+	case <-w.Changes():
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+}
+
+func expectDirQuiet(t *testing.T, w *DirWatcher) {
+	t.Helper()
+	select {
+	case <-w.Changes():
+		t.Error("unexpected change")
+	case <-time.After(w.debounce + 200*time.Millisecond):
+	}
+}
+
+func TestDirWatcherReportsEntryChanges(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	os.MkdirAll(sub, 0755)
+	file := filepath.Join(sub, "devenv.nix")
+	os.WriteFile(file, []byte("{}"), 0644)
+
+	w, err := NewDirs()
+	if err != nil {
+		t.Fatalf("NewDirs() error = %v", err)
+	}
+	defer w.Close()
+	w.debounce = 50 * time.Millisecond
+	if err := w.Set([]string{root, sub}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// A new project is one change
+	for _, name := range []string{"app", "app2"} {
+		os.Mkdir(filepath.Join(root, name), 0755)
+	}
+	expectDirChange(t, w)
+	expectDirQuiet(t, w)
+
+	// Writes to existing files aren't
+	os.WriteFile(file, []byte("{ }"), 0644)
+	expectDirQuiet(t, w)
+
+	// Moves are
+	os.Rename(filepath.Join(root, "app"), filepath.Join(root, "moved"))
+	expectDirChange(t, w)
+
+	// Dropped directories are no longer watched
+	if err := w.Set([]string{root}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	os.Remove(file)
+	expectDirQuiet(t, w)
+}
@@ -1,24 +1,48 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/infktd/devdash/internal/cli"
 	"github.com/infktd/devdash/internal/config"
+	"github.com/infktd/devdash/internal/demo"
 	"github.com/infktd/devdash/internal/registry"
-	"github.com/infktd/devdash/internal/scanner"
 	"github.com/infktd/devdash/internal/ui"
+	"github.com/infktd/devdash/internal/watch"
 )
 
 func main() {
+	// Headless subcommands (list, status, start, ...) bypass the TUI
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+	}
+
+	// Fake projects for trying devdash out without devenv
+	if len(os.Args) > 1 && os.Args[1] == "--demo" {
+		os.Exit(runDemo())
+	}
+
 	// Load config
 	cfg, err := config.Load(config.Path())
+	var invalid *config.ValidationError
+	if errors.As(err, &invalid) {
+		fmt.Fprintf(os.Stderr, "Invalid config %s:\n", config.Path())
+		for _, p := range invalid.Problems {
+			fmt.Fprintf(os.Stderr, "  %s\n", p)
+		}
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		os.Exit(1)
 	}
+	for _, w := range cfg.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", config.Path(), w)
+	}
 
 	// Load registry
 	reg, err := registry.Load(registry.Path())
@@ -26,30 +50,59 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error loading registry: %v\n", err)
 		os.Exit(1)
 	}
+	for _, w := range reg.Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", registry.Path(), w)
+	}
 
-	// Auto-discover projects if enabled
-	if cfg.Projects.AutoDiscover {
-		projects, err := scanner.Scan(cfg.Projects.ScanPaths, cfg.Projects.ScanDepth)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: error during project scan: %v\n", err)
-		}
-		for _, path := range projects {
-			reg.AddProject(path)
-		}
-		// Save updated registry
-		if err := registry.Save(registry.Path(), reg); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to save registry: %v\n", err)
-		}
+	if err := runTUI(cfg, reg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runTUI runs the dashboard with mouse support, applying changes to the
+// config and registry files live. With auto_discover on, it scans for new
+// projects in the background once the dashboard is showing, and again
+// whenever projects appear or move under the scan paths.
+func runTUI(cfg *config.Config, reg *registry.Registry) error {
+	m := ui.New(cfg, reg)
+	if w, err := watch.New(config.Path(), registry.Path()); err == nil {
+		defer w.Close()
+		m.SetWatcher(w)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: config changes will apply after a restart: %v\n", err)
+	}
+	if w, err := watch.NewDirs(); err == nil {
+		defer w.Close()
+		m.SetDirWatcher(w)
+	} else {
+		fmt.Fprintf(os.Stderr, "Warning: new and moved projects will show after a restart: %v\n", err)
 	}
 
-	// Run TUI with mouse support
 	p := tea.NewProgram(
-		ui.New(cfg, reg),
+		m,
 		tea.WithAltScreen(),
 		tea.WithMouseCellMotion(), // Enable mouse motion tracking
 	)
-	if _, err := p.Run(); err != nil {
+	_, err := p.Run()
+	return err
+}
+
+// runDemo runs the dashboard against fake projects, leaving the real config,
+// registry and alert history untouched.
+func runDemo() int {
+	d, err := demo.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error starting demo: %v\n", err)
+		return 1
+	}
+	defer d.Close()
+
+	cfg := config.Default()
+	cfg.Projects.AutoDiscover = false // Keep real projects out of the demo
+	if err := runTUI(cfg, d.Registry); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		return 1
 	}
+	return 0
 }